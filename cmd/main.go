@@ -17,9 +17,11 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"crypto/tls"
 	"flag"
 	"os"
+	"sync/atomic"
 	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
@@ -40,11 +42,15 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/config"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	"github.com/kdex-tech/nexus-manager/internal/controller"
+	"github.com/kdex-tech/nexus-manager/internal/extensions"
+	"github.com/kdex-tech/nexus-manager/internal/metrics"
+	"github.com/kdex-tech/nexus-manager/internal/reload"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -66,7 +72,9 @@ func init() {
 // nolint:gocyclo
 func main() {
 	var configFile string
+	var configReloadSeconds int
 	namedLogLevels := make(kdexlog.NamedLogLevelPairs)
+	var observerNamespace string
 	var requeueDelaySeconds int
 
 	var metricsAddr string
@@ -79,8 +87,12 @@ func main() {
 	var tlsOpts []func(*tls.Config)
 
 	flag.StringVar(&configFile, "config-file", "/config.yaml", "The path to a configuration yaml file.")
+	flag.IntVar(&configReloadSeconds, "config-reload-seconds", 10, "Set the interval for checking the configuration "+
+		"file for changes. 0 disables reloading.")
 	flag.Var(&namedLogLevels, "named-log-level", "Specify a named log level pair (format: NAME=LEVEL) (can be used "+
 		"multiple times)")
+	flag.StringVar(&observerNamespace, "observer-namespace", "", "The namespace of the observer CronJobs of "+
+		"KDexClusterFaaSAdaptors. Cluster scoped adaptors with an observer are degraded when it is not set.")
 	flag.IntVar(&requeueDelaySeconds, "requeue-delay-seconds", 15, "Set the delay for requeuing reconciliation loops")
 
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
@@ -202,7 +214,28 @@ func main() {
 		os.Exit(1)
 	}
 
-	conf := configuration.LoadConfiguration(configFile, scheme)
+	// Rejected reloads are reported on the manager pod, known from the downward API.
+	var managerPod runtime.Object
+	if name, namespace := os.Getenv("POD_NAME"), os.Getenv("POD_NAMESPACE"); name != "" && namespace != "" {
+		managerPod = &corev1.ObjectReference{APIVersion: "v1", Kind: "Pod", Name: name, Namespace: namespace}
+	}
+
+	configWatcher := &reload.Watcher{
+		File:      configFile,
+		Interval:  time.Duration(configReloadSeconds) * time.Second,
+		Log:       logger.WithName("configuration"),
+		Recorder:  mgr.GetEventRecorder("configuration"),
+		Regarding: managerPod,
+		Scheme:    scheme,
+	}
+	conf, ext, err := configWatcher.Load()
+	if err != nil {
+		// Earlier releases started with any configuration, so an invalid one is reported rather than fatal.
+		setupLog.Error(err, "starting with an invalid configuration", "file", configFile)
+	}
+	var currentConf atomic.Pointer[configuration.NexusConfiguration]
+	currentConf.Store(&conf)
+
 	requeueDelay := time.Duration(requeueDelaySeconds) * time.Second
 
 	registryLog := logger.WithName("npm-registry")
@@ -212,14 +245,16 @@ func main() {
 		if err == nil {
 			return reg, nil
 		}
-		registryLog.V(2).Info("using default registry", "requested", registry, "default", conf.DefaultNpmRegistry.Host)
+		defaultRegistry := currentConf.Load().DefaultNpmRegistry
+		registryLog.V(2).Info("using default registry", "requested", registry, "default", defaultRegistry.Host)
 		return &npm.RegistryImpl{
-			Config: &conf.DefaultNpmRegistry,
+			Config: &defaultRegistry,
 		}, nil
 	}
 
 	if err := (&controller.KDexAppReconciler{
 		Client:          mgr.GetClient(),
+		Recorder:        mgr.GetEventRecorder("kdexapp-controller"),
 		RegistryFactory: registryFactory,
 		RequeueDelay:    requeueDelay,
 		Scheme:          mgr.GetScheme(),
@@ -227,17 +262,22 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "KDexApp")
 		os.Exit(1)
 	}
-	if err := (&controller.KDexHostReconciler{
+	hostReconciler := &controller.KDexHostReconciler{
 		Client:        mgr.GetClient(),
+		APIReader:     mgr.GetAPIReader(),
 		Configuration: conf,
+		Extensions:    ext,
+		Recorder:      mgr.GetEventRecorder("kdexhost-controller"),
 		RequeueDelay:  requeueDelay,
 		Scheme:        mgr.GetScheme(),
-	}).SetupWithManager(mgr); err != nil {
+	}
+	if err := hostReconciler.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "KDexHost")
 		os.Exit(1)
 	}
 	if err := (&controller.KDexPageArchetypeReconciler{
 		Client:       mgr.GetClient(),
+		Recorder:     mgr.GetEventRecorder("kdexpagearchetype-controller"),
 		RequeueDelay: requeueDelay,
 		Scheme:       mgr.GetScheme(),
 	}).SetupWithManager(mgr); err != nil {
@@ -246,6 +286,7 @@ func main() {
 	}
 	if err := (&controller.KDexPageHeaderReconciler{
 		Client:       mgr.GetClient(),
+		Recorder:     mgr.GetEventRecorder("kdexpageheader-controller"),
 		RequeueDelay: requeueDelay,
 		Scheme:       mgr.GetScheme(),
 	}).SetupWithManager(mgr); err != nil {
@@ -254,6 +295,7 @@ func main() {
 	}
 	if err := (&controller.KDexPageFooterReconciler{
 		Client:       mgr.GetClient(),
+		Recorder:     mgr.GetEventRecorder("kdexpagefooter-controller"),
 		RequeueDelay: requeueDelay,
 		Scheme:       mgr.GetScheme(),
 	}).SetupWithManager(mgr); err != nil {
@@ -262,6 +304,7 @@ func main() {
 	}
 	if err := (&controller.KDexPageNavigationReconciler{
 		Client:       mgr.GetClient(),
+		Recorder:     mgr.GetEventRecorder("kdexpagenavigation-controller"),
 		RequeueDelay: requeueDelay,
 		Scheme:       mgr.GetScheme(),
 	}).SetupWithManager(mgr); err != nil {
@@ -270,6 +313,7 @@ func main() {
 	}
 	if err := (&controller.KDexScriptLibraryReconciler{
 		Client:          mgr.GetClient(),
+		Recorder:        mgr.GetEventRecorder("kdexscriptlibrary-controller"),
 		RegistryFactory: registryFactory,
 		RequeueDelay:    requeueDelay,
 		Scheme:          mgr.GetScheme(),
@@ -279,6 +323,7 @@ func main() {
 	}
 	if err := (&controller.KDexThemeReconciler{
 		Client:       mgr.GetClient(),
+		Recorder:     mgr.GetEventRecorder("kdextheme-controller"),
 		RequeueDelay: requeueDelay,
 		Scheme:       mgr.GetScheme(),
 	}).SetupWithManager(mgr); err != nil {
@@ -287,6 +332,7 @@ func main() {
 	}
 	if err := (&controller.KDexTranslationReconciler{
 		Client:       mgr.GetClient(),
+		Recorder:     mgr.GetEventRecorder("kdextranslation-controller"),
 		RequeueDelay: requeueDelay,
 		Scheme:       mgr.GetScheme(),
 	}).SetupWithManager(mgr); err != nil {
@@ -294,31 +340,66 @@ func main() {
 		os.Exit(1)
 	}
 	if err := (&controller.KDexPageBindingReconciler{
-		Client:        mgr.GetClient(),
-		Configuration: conf,
-		RequeueDelay:  requeueDelay,
-		Scheme:        mgr.GetScheme(),
+		Client:       mgr.GetClient(),
+		Recorder:     mgr.GetEventRecorder("kdexpagebinding-controller"),
+		RequeueDelay: requeueDelay,
+		Scheme:       mgr.GetScheme(),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "KDexPageBinding")
 		os.Exit(1)
 	}
 	if err := (&controller.KDexUtilityPageReconciler{
 		Client:       mgr.GetClient(),
+		Recorder:     mgr.GetEventRecorder("kdexutilitypage-controller"),
 		RequeueDelay: requeueDelay,
 		Scheme:       mgr.GetScheme(),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "KDexUtilityPage")
 		os.Exit(1)
 	}
+	if err := (&controller.KDexFaaSAdaptorReconciler{
+		Client:            mgr.GetClient(),
+		ObserverNamespace: observerNamespace,
+		Recorder:          mgr.GetEventRecorder("kdexfaasadaptor-controller"),
+		RequeueDelay:      requeueDelay,
+		Scheme:            mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "KDexFaaSAdaptor")
+		os.Exit(1)
+	}
 	if err := (&controller.KDexFunctionReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:       mgr.GetClient(),
+		APIReader:    mgr.GetAPIReader(),
+		Recorder:     mgr.GetEventRecorder("kdexfunction-controller"),
+		RequeueDelay: requeueDelay,
+		Scheme:       mgr.GetScheme(),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "KDexFunction")
 		os.Exit(1)
 	}
 	// +kubebuilder:scaffold:builder
 
+	if configReloadSeconds > 0 {
+		configWatcher.Listeners = append(configWatcher.Listeners,
+			func(_ context.Context, c configuration.NexusConfiguration, _ extensions.Configuration) {
+				currentConf.Store(&c)
+			},
+			hostReconciler.SetConfiguration,
+		)
+		if err := mgr.Add(configWatcher); err != nil {
+			setupLog.Error(err, "unable to set up configuration reloading")
+			os.Exit(1)
+		}
+	}
+
+	if err := crmetrics.Registry.Register(&metrics.ObjectCollector{
+		Log:    logger.WithName("metrics"),
+		Reader: mgr.GetCache(),
+	}); err != nil {
+		setupLog.Error(err, "unable to register the object metrics")
+		os.Exit(1)
+	}
+
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
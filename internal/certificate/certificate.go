@@ -0,0 +1,138 @@
+package certificate
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"slices"
+	"time"
+)
+
+// backdate is subtracted from NotBefore to tolerate clock skew between the manager and the clients of a certificate.
+const backdate = 5 * time.Minute
+
+// Authority is a CA able to sign host certificates.
+type Authority struct {
+	Certificate *x509.Certificate
+	CertPEM     []byte
+	Key         crypto.Signer
+}
+
+// ParseAuthority reads a CA from a PEM encoded certificate and private key, as found in a kubernetes.io/tls Secret.
+func ParseAuthority(certPEM []byte, keyPEM []byte) (*Authority, error) {
+	pair, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CA key pair: %w", err)
+	}
+
+	cert, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid CA certificate: %w", err)
+	}
+
+	if !cert.IsCA {
+		return nil, fmt.Errorf("certificate %q is not a CA", cert.Subject.CommonName)
+	}
+
+	key, ok := pair.PrivateKey.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported CA private key type %T", pair.PrivateKey)
+	}
+
+	return &Authority{Certificate: cert, CertPEM: certPEM, Key: key}, nil
+}
+
+// Issue returns a PEM encoded certificate and private key for domains, valid from now for duration. The certificate is
+// signed by ca, or self-signed when ca is nil.
+func Issue(domains []string, duration time.Duration, ca *Authority, now time.Time) ([]byte, []byte, error) {
+	if len(domains) == 0 {
+		return nil, nil, fmt.Errorf("at least one domain is required")
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	template := &x509.Certificate{
+		BasicConstraintsValid: true,
+		DNSNames:              slices.Clone(domains),
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		NotAfter:              now.Add(duration),
+		NotBefore:             now.Add(-backdate),
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: domains[0]},
+	}
+
+	parent, signer := template, crypto.Signer(key)
+	if ca != nil {
+		parent, signer = ca.Certificate, ca.Key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, key.Public(), signer)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+
+	return certPEM, keyPEM, nil
+}
+
+// Parse returns the first certificate found in certPEM.
+func Parse(certPEM []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(certPEM)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("no PEM encoded certificate found")
+	}
+
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// NeedsRenewal reports whether cert must be replaced: when it does not cover exactly domains, was not issued by ca
+// (or is not self-signed when ca is nil), or enters its renewal window, renewBefore ahead of expiry.
+func NeedsRenewal(cert *x509.Certificate, domains []string, ca *Authority, renewBefore time.Duration, now time.Time) bool {
+	if !slices.Equal(sortedCopy(cert.DNSNames), sortedCopy(domains)) {
+		return true
+	}
+
+	issuer := cert
+	if ca != nil {
+		issuer = ca.Certificate
+	}
+	if issuer.CheckSignature(cert.SignatureAlgorithm, cert.RawTBSCertificate, cert.Signature) != nil {
+		return true
+	}
+
+	return !now.Before(RenewalTime(cert, renewBefore))
+}
+
+// RenewalTime returns the time at which cert enters its renewal window.
+func RenewalTime(cert *x509.Certificate, renewBefore time.Duration) time.Time {
+	return cert.NotAfter.Add(-renewBefore)
+}
+
+func sortedCopy(values []string) []string {
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	return sorted
+}
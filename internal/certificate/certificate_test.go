@@ -0,0 +1,143 @@
+package certificate
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_NeedsRenewal(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	domains := []string{"kdex.dev", "*.kdex.dev"}
+	ca := testAuthority(t, now)
+
+	selfSigned := issue(t, domains, nil, now)
+	signed := issue(t, domains, ca, now)
+
+	tests := []struct {
+		name     string
+		cert     *x509.Certificate
+		domains  []string
+		ca       *Authority
+		now      time.Time
+		expected bool
+	}{
+		{
+			name:     "self-signed and valid",
+			cert:     selfSigned,
+			domains:  domains,
+			now:      now,
+			expected: false,
+		},
+		{
+			name:     "domains in a different order",
+			cert:     selfSigned,
+			domains:  []string{"*.kdex.dev", "kdex.dev"},
+			now:      now,
+			expected: false,
+		},
+		{
+			name:     "domains changed",
+			cert:     selfSigned,
+			domains:  []string{"kdex.dev"},
+			now:      now,
+			expected: true,
+		},
+		{
+			name:     "inside the renewal window",
+			cert:     selfSigned,
+			domains:  domains,
+			now:      now.Add(61 * 24 * time.Hour),
+			expected: true,
+		},
+		{
+			name:     "signed by the authority",
+			cert:     signed,
+			domains:  domains,
+			ca:       ca,
+			now:      now,
+			expected: false,
+		},
+		{
+			name:     "self-signed once an authority is configured",
+			cert:     selfSigned,
+			domains:  domains,
+			ca:       ca,
+			now:      now,
+			expected: true,
+		},
+		{
+			name:     "signed by an authority no longer configured",
+			cert:     signed,
+			domains:  domains,
+			now:      now,
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, NeedsRenewal(tt.cert, tt.domains, tt.ca, 30*24*time.Hour, tt.now))
+		})
+	}
+}
+
+func Test_ParseAuthority(t *testing.T) {
+	now := time.Now()
+	ca := testAuthority(t, now)
+
+	_, err := ParseAuthority(ca.CertPEM, []byte("not a key"))
+	assert.Error(t, err)
+
+	certPEM, keyPEM, err := Issue([]string{"kdex.dev"}, time.Hour, nil, now)
+	assert.NoError(t, err)
+	_, err = ParseAuthority(certPEM, keyPEM)
+	assert.ErrorContains(t, err, "is not a CA")
+}
+
+func issue(t *testing.T, domains []string, ca *Authority, now time.Time) *x509.Certificate {
+	certPEM, _, err := Issue(domains, 90*24*time.Hour, ca, now)
+	assert.NoError(t, err)
+
+	cert, err := Parse(certPEM)
+	assert.NoError(t, err)
+
+	return cert
+}
+
+func testAuthority(t *testing.T, now time.Time) *Authority {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	template := &x509.Certificate{
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		NotAfter:              now.Add(365 * 24 * time.Hour),
+		NotBefore:             now.Add(-time.Hour),
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "KDex Test CA"},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	assert.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+
+	ca, err := ParseAuthority(
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	)
+	assert.NoError(t, err)
+
+	return ca
+}
@@ -0,0 +1,278 @@
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	kdexv1alpha1 "kdex.dev/crds/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	fieldManager         = "kdex-nexus-manager"
+	desiredStateHashKey  = "kdex.dev/desired-state-hash"
+	driftAttributeSuffix = ".drift"
+
+	ConditionTypeDrifted          kdexv1alpha1.ConditionType   = "Drifted"
+	ConditionReasonDriftCorrected kdexv1alpha1.ConditionReason = "DriftCorrected"
+	ConditionReasonNoDrift        kdexv1alpha1.ConditionReason = "NoDrift"
+)
+
+// applyOwned server-side applies obj, which must hold the complete desired state of a resource owned by the host,
+// under the manager's field manager. On return obj holds the state returned by the API server.
+//
+// The hash of the desired state is recorded on the resource. When the previously applied hash matches the current
+// one, any field the apply had to change was modified by someone else since the last reconcile; those fields are
+// reported as drift on the host.
+func (r *KDexHostReconciler) applyOwned(
+	ctx context.Context,
+	host *kdexv1alpha1.KDexHost,
+	obj client.Object,
+) (controllerutil.OperationResult, error) {
+	gvk, err := apiutil.GVKForObject(obj, r.Scheme)
+	if err != nil {
+		return controllerutil.OperationResultNone, err
+	}
+	obj.GetObjectKind().SetGroupVersionKind(gvk)
+
+	desired, err := toApplyConfiguration(obj)
+	if err != nil {
+		return controllerutil.OperationResultNone, err
+	}
+
+	hash, err := hashOf(desired)
+	if err != nil {
+		return controllerutil.OperationResultNone, err
+	}
+
+	annotations := desired.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[desiredStateHashKey] = hash
+	desired.SetAnnotations(annotations)
+
+	owned := desired.DeepCopy().Object
+
+	liveObj, err := r.Scheme.New(gvk)
+	if err != nil {
+		return controllerutil.OperationResultNone, err
+	}
+	live := liveObj.(client.Object)
+	exists := true
+	// The cache may not hold the previous apply yet, which would then be mistaken for drift.
+	if err := r.APIReader.Get(ctx, client.ObjectKeyFromObject(obj), live); err != nil {
+		if !errors.IsNotFound(err) {
+			return controllerutil.OperationResultNone, err
+		}
+		exists = false
+	}
+
+	if err := r.Apply(
+		ctx,
+		client.ApplyConfigurationFromUnstructured(desired),
+		client.FieldOwner(fieldManager),
+		client.ForceOwnership,
+	); err != nil {
+		return controllerutil.OperationResultNone, err
+	}
+
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(desired.Object, obj); err != nil {
+		return controllerutil.OperationResultNone, err
+	}
+
+	if !exists {
+		r.Recorder.Eventf(host, obj, corev1.EventTypeNormal, EventReasonCreated, eventActionApply,
+			"Created %s %s", gvk.Kind, obj.GetName())
+		return controllerutil.OperationResultCreated, nil
+	}
+
+	if live.GetResourceVersion() == obj.GetResourceVersion() {
+		return controllerutil.OperationResultNone, nil
+	}
+
+	r.Recorder.Eventf(host, obj, corev1.EventTypeNormal, EventReasonUpdated, eventActionApply,
+		"Updated %s %s", gvk.Kind, obj.GetName())
+
+	if live.GetAnnotations()[desiredStateHashKey] == hash {
+		drifted, err := driftedFields(owned, live, obj)
+		if err != nil {
+			return controllerutil.OperationResultNone, err
+		}
+		if len(drifted) > 0 {
+			recordDrift(host, gvk.Kind, obj.GetName(), drifted)
+			r.Recorder.Eventf(host, obj, corev1.EventTypeWarning, string(ConditionReasonDriftCorrected), eventActionApply,
+				"Corrected drift in %s %s: %s", gvk.Kind, obj.GetName(), strings.Join(drifted, ", "))
+		}
+	}
+
+	return controllerutil.OperationResultUpdated, nil
+}
+
+// deleteOwned deletes the resource identified by obj if it exists and is controlled by the host. It reports whether a
+// deletion was issued.
+func (r *KDexHostReconciler) deleteOwned(
+	ctx context.Context,
+	host *kdexv1alpha1.KDexHost,
+	obj client.Object,
+) (bool, error) {
+	if err := r.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+
+	if !metav1.IsControlledBy(obj, host) || !obj.GetDeletionTimestamp().IsZero() {
+		return false, nil
+	}
+
+	if err := r.Delete(ctx, obj); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+
+	return true, nil
+}
+
+// clearStaleDrift drops drift reported against an earlier generation of the host so that the Drifted condition only
+// ever describes drift corrected since the host was last changed.
+func clearStaleDrift(host *kdexv1alpha1.KDexHost) {
+	condition := meta.FindStatusCondition(host.Status.Conditions, string(ConditionTypeDrifted))
+	if condition != nil && condition.ObservedGeneration == host.Generation {
+		return
+	}
+
+	for key := range host.Status.Attributes {
+		if strings.HasSuffix(key, driftAttributeSuffix) {
+			delete(host.Status.Attributes, key)
+		}
+	}
+
+	meta.SetStatusCondition(&host.Status.Conditions, metav1.Condition{
+		Message:            "No drift detected",
+		ObservedGeneration: host.Generation,
+		Reason:             string(ConditionReasonNoDrift),
+		Status:             metav1.ConditionFalse,
+		Type:               string(ConditionTypeDrifted),
+	})
+}
+
+func recordDrift(host *kdexv1alpha1.KDexHost, kind string, name string, fields []string) {
+	host.Status.Attributes[strings.ToLower(kind)+"."+name+driftAttributeSuffix] = strings.Join(fields, ",")
+
+	entries := []string{}
+	for key, value := range host.Status.Attributes {
+		if strings.HasSuffix(key, driftAttributeSuffix) {
+			entries = append(entries, fmt.Sprintf("%s: %s", strings.TrimSuffix(key, driftAttributeSuffix), value))
+		}
+	}
+	sort.Strings(entries)
+
+	meta.SetStatusCondition(&host.Status.Conditions, metav1.Condition{
+		Message:            "Corrected drift in " + strings.Join(entries, "; "),
+		ObservedGeneration: host.Generation,
+		Reason:             string(ConditionReasonDriftCorrected),
+		Status:             metav1.ConditionTrue,
+		Type:               string(ConditionTypeDrifted),
+	})
+}
+
+// driftedFields returns the paths, among those present in the owned field set, whose value differs between the live
+// object and the object resulting from the apply.
+func driftedFields(owned map[string]any, live client.Object, applied client.Object) ([]string, error) {
+	liveMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(live)
+	if err != nil {
+		return nil, err
+	}
+	appliedMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(applied)
+	if err != nil {
+		return nil, err
+	}
+
+	delete(owned, "apiVersion")
+	delete(owned, "kind")
+	if metadata, ok := owned["metadata"].(map[string]any); ok {
+		delete(metadata, "name")
+		delete(metadata, "namespace")
+		if annotations, ok := metadata["annotations"].(map[string]any); ok {
+			delete(annotations, desiredStateHashKey)
+		}
+	}
+
+	drifted := []string{}
+	collectDrift(owned, liveMap, appliedMap, "", &drifted)
+	sort.Strings(drifted)
+	return drifted, nil
+}
+
+func collectDrift(owned map[string]any, live map[string]any, applied map[string]any, prefix string, drifted *[]string) {
+	for key, value := range owned {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+
+		if ownedChild, ok := value.(map[string]any); ok && len(ownedChild) > 0 {
+			liveChild, _ := live[key].(map[string]any)
+			appliedChild, _ := applied[key].(map[string]any)
+			collectDrift(ownedChild, liveChild, appliedChild, path, drifted)
+			continue
+		}
+
+		if !reflect.DeepEqual(live[key], applied[key]) {
+			*drifted = append(*drifted, path)
+		}
+	}
+}
+
+// toApplyConfiguration converts a typed object into the unstructured form sent as an apply configuration, omitting
+// the fields that are never owned by the manager.
+func toApplyConfiguration(obj client.Object) (*unstructured.Unstructured, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	delete(content, "status")
+	pruneNil(content)
+
+	u := &unstructured.Unstructured{Object: content}
+	u.SetGroupVersionKind(obj.GetObjectKind().GroupVersionKind())
+
+	return u, nil
+}
+
+func pruneNil(content map[string]any) {
+	for key, value := range content {
+		switch v := value.(type) {
+		case nil:
+			delete(content, key)
+		case map[string]any:
+			pruneNil(v)
+		case []any:
+			for _, item := range v {
+				if m, ok := item.(map[string]any); ok {
+					pruneNil(m)
+				}
+			}
+		}
+	}
+}
+
+func hashOf(u *unstructured.Unstructured) (string, error) {
+	content, err := json.Marshal(u.Object)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(content)), nil
+}
@@ -0,0 +1,53 @@
+package controller
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/events"
+	kdexv1alpha1 "kdex.dev/crds/api/v1alpha1"
+)
+
+const (
+	EventReasonCreated                 = "Created"
+	EventReasonDeleted                 = "Deleted"
+	EventReasonInvalidPackageReference = "InvalidPackageReference"
+	EventReasonProgressing             = "Progressing"
+	EventReasonReady                   = "Ready"
+	EventReasonUpdated                 = "Updated"
+
+	eventActionApply     = "Apply"
+	eventActionDelete    = "Delete"
+	eventActionReconcile = "Reconcile"
+)
+
+// recordConditionTransition records an Event on obj when the outcome of a reconcile, as told by its Ready condition,
+// differs from the one stored before: the object became ready, degraded (the event then carries the condition
+// reason, e.g. ReconcileError), or is waiting on something else than before, such as a dependency which is not ready.
+// Reconciles which leave the conditions as they were record nothing, so a steady state does not flood the Events.
+func recordConditionTransition(
+	recorder events.EventRecorder,
+	obj runtime.Object,
+	previous []metav1.Condition,
+	current []metav1.Condition,
+) {
+	ready := meta.FindStatusCondition(current, string(kdexv1alpha1.ConditionTypeReady))
+	if ready == nil || ready.Reason == string(kdexv1alpha1.ConditionReasonReconciling) {
+		return
+	}
+
+	before := meta.FindStatusCondition(previous, string(kdexv1alpha1.ConditionTypeReady))
+	if before != nil && before.Status == ready.Status && before.Reason == ready.Reason && before.Message == ready.Message {
+		return
+	}
+
+	switch {
+	case ready.Status == metav1.ConditionTrue:
+		recorder.Eventf(obj, nil, corev1.EventTypeNormal, EventReasonReady, eventActionReconcile, "%s", ready.Message)
+	case meta.IsStatusConditionTrue(current, string(kdexv1alpha1.ConditionTypeDegraded)):
+		recorder.Eventf(obj, nil, corev1.EventTypeWarning, ready.Reason, eventActionReconcile, "%s", ready.Message)
+	default:
+		recorder.Eventf(obj, nil, corev1.EventTypeNormal, EventReasonProgressing, eventActionReconcile, "%s", ready.Message)
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"slices"
 	"time"
 
 	"github.com/kdex-tech/nexus-manager/internal/validation"
@@ -27,6 +28,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/events"
 	kdexv1alpha1 "kdex.dev/crds/api/v1alpha1"
 	"kdex.dev/crds/npm"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -40,6 +42,7 @@ import (
 // KDexAppReconciler reconciles a KDexApp object
 type KDexAppReconciler struct {
 	client.Client
+	Recorder        events.EventRecorder
 	RegistryFactory npm.RegistryFactory
 	RequeueDelay    time.Duration
 	Scheme          *runtime.Scheme
@@ -50,7 +53,6 @@ func (r *KDexAppReconciler) Reconcile(ctx context.Context, req ctrl.Request) (re
 
 	var status *kdexv1alpha1.KDexObjectStatus
 	var spec kdexv1alpha1.KDexAppSpec
-	var om metav1.ObjectMeta
 	var o client.Object
 
 	if req.Namespace == "" {
@@ -60,7 +62,6 @@ func (r *KDexAppReconciler) Reconcile(ctx context.Context, req ctrl.Request) (re
 		}
 		status = &clusterApp.Status
 		spec = clusterApp.Spec
-		om = clusterApp.ObjectMeta
 		o = &clusterApp
 	} else {
 		var app kdexv1alpha1.KDexApp
@@ -69,7 +70,6 @@ func (r *KDexAppReconciler) Reconcile(ctx context.Context, req ctrl.Request) (re
 		}
 		status = &app.Status
 		spec = app.Spec
-		om = app.ObjectMeta
 		o = &app
 	}
 
@@ -77,13 +77,17 @@ func (r *KDexAppReconciler) Reconcile(ctx context.Context, req ctrl.Request) (re
 		status.Attributes = make(map[string]string)
 	}
 
+	base := o.DeepCopyObject().(client.Object)
+	previousConditions := slices.Clone(status.Conditions)
+
+	if isPaused(o) {
+		return ctrl.Result{}, pauseReconcile(ctx, r.Client, r.Recorder, base, o, status)
+	}
+	resumeReconcile(r.Recorder, o, status)
+
 	// Defer status update
 	defer func() {
-		status.ObservedGeneration = om.Generation
-		if updateErr := r.Status().Update(ctx, o); updateErr != nil {
-			err = updateErr
-			res = ctrl.Result{}
-		}
+		res, err = finishReconcile(ctx, r.Client, r.Recorder, base, o, previousConditions, res, err)
 
 		log.V(2).Info("status", "status", status, "err", err, "res", res)
 	}()
@@ -109,6 +113,9 @@ func (r *KDexAppReconciler) Reconcile(ctx context.Context, req ctrl.Request) (re
 	}
 
 	if err := validation.ValidatePackageReference(&spec.PackageReference, secret, r.RegistryFactory); err != nil {
+		r.Recorder.Eventf(o, nil, corev1.EventTypeWarning, EventReasonInvalidPackageReference, eventActionReconcile,
+			"Invalid package reference: %v", err)
+
 		kdexv1alpha1.SetConditions(
 			&status.Conditions,
 			kdexv1alpha1.ConditionStatuses{
@@ -144,7 +151,7 @@ func (r *KDexAppReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	if os.Getenv("ENABLE_WEBHOOKS") != FALSE {
 		err := ctrl.NewWebhookManagedBy(mgr, &kdexv1alpha1.KDexApp{}).
 			WithDefaulter(&nexuswebhook.KDexAppDefaulter[*kdexv1alpha1.KDexApp]{}).
-			WithValidator(&nexuswebhook.KDexAppValidator[*kdexv1alpha1.KDexApp]{}).
+			WithValidator(nexuswebhook.RecordRejections[*kdexv1alpha1.KDexApp](r.Recorder, &nexuswebhook.KDexAppValidator[*kdexv1alpha1.KDexApp]{})).
 			Complete()
 
 		if err != nil {
@@ -153,7 +160,7 @@ func (r *KDexAppReconciler) SetupWithManager(mgr ctrl.Manager) error {
 
 		err = ctrl.NewWebhookManagedBy(mgr, &kdexv1alpha1.KDexClusterApp{}).
 			WithDefaulter(&nexuswebhook.KDexAppDefaulter[*kdexv1alpha1.KDexClusterApp]{}).
-			WithValidator(&nexuswebhook.KDexAppValidator[*kdexv1alpha1.KDexClusterApp]{}).
+			WithValidator(nexuswebhook.RecordRejections[*kdexv1alpha1.KDexClusterApp](r.Recorder, &nexuswebhook.KDexAppValidator[*kdexv1alpha1.KDexClusterApp]{})).
 			Complete()
 
 		if err != nil {
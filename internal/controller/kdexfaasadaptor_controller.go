@@ -0,0 +1,390 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/kdex-tech/nexus-manager/internal/validation"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/events"
+	kdexv1alpha1 "kdex.dev/crds/api/v1alpha1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	kdexFaaSObserver = "kdex-faas-observer"
+
+	// defaultObserverSchedule is the schedule of an observer which does not set one, as defaulted by the CRD.
+	defaultObserverSchedule = "*/5 * * * *"
+
+	builderGenerationAttributeSuffix = ".builder.generation"
+	observerAttribute                = "observer"
+)
+
+// kpackVersion is the API version of the kpack Builders and ClusterBuilders referenced by the builders of an adaptor.
+var kpackVersion = schema.GroupVersion{Group: "kpack.io", Version: "v1alpha2"}
+
+// KDexFaaSAdaptorReconciler reconciles a KDexFaaSAdaptor or KDexClusterFaaSAdaptor object
+type KDexFaaSAdaptorReconciler struct {
+	client.Client
+	// ObserverNamespace is the namespace of the observers of KDexClusterFaaSAdaptors; those of KDexFaaSAdaptors run
+	// in the namespace of the adaptor.
+	ObserverNamespace string
+	Recorder          events.EventRecorder
+	RequeueDelay      time.Duration
+	Scheme            *runtime.Scheme
+}
+
+func (r *KDexFaaSAdaptorReconciler) Reconcile(ctx context.Context, req ctrl.Request) (res ctrl.Result, err error) {
+	log := logf.FromContext(ctx)
+
+	var status *kdexv1alpha1.KDexObjectStatus
+	var spec kdexv1alpha1.KDexFaaSAdaptorSpec
+	var o client.Object
+
+	if req.Namespace == "" {
+		var clusterFaaSAdaptor kdexv1alpha1.KDexClusterFaaSAdaptor
+		if err := r.Get(ctx, req.NamespacedName, &clusterFaaSAdaptor); err != nil {
+			return ctrl.Result{}, client.IgnoreNotFound(err)
+		}
+		status = &clusterFaaSAdaptor.Status.KDexObjectStatus
+		spec = clusterFaaSAdaptor.Spec
+		o = &clusterFaaSAdaptor
+	} else {
+		var faasAdaptor kdexv1alpha1.KDexFaaSAdaptor
+		if err := r.Get(ctx, req.NamespacedName, &faasAdaptor); err != nil {
+			return ctrl.Result{}, client.IgnoreNotFound(err)
+		}
+		status = &faasAdaptor.Status.KDexObjectStatus
+		spec = faasAdaptor.Spec
+		o = &faasAdaptor
+	}
+
+	if status.Attributes == nil {
+		status.Attributes = make(map[string]string)
+	}
+
+	base := o.DeepCopyObject().(client.Object)
+	previousConditions := slices.Clone(status.Conditions)
+
+	if isPaused(o) {
+		return ctrl.Result{}, pauseReconcile(ctx, r.Client, r.Recorder, base, o, status)
+	}
+	resumeReconcile(r.Recorder, o, status)
+
+	// Defer status update
+	defer func() {
+		res, err = finishReconcile(ctx, r.Client, r.Recorder, base, o, previousConditions, res, err)
+
+		log.V(2).Info("status", "status", status, "err", err, "res", res)
+	}()
+
+	kdexv1alpha1.SetConditions(
+		&status.Conditions,
+		kdexv1alpha1.ConditionStatuses{
+			Degraded:    metav1.ConditionFalse,
+			Progressing: metav1.ConditionTrue,
+			Ready:       metav1.ConditionUnknown,
+		},
+		kdexv1alpha1.ConditionReasonReconciling,
+		"Reconciling",
+	)
+
+	// An invalid spec is not retried; the adaptor is reconciled again once it is changed.
+	if err := validation.ValidateFaaSAdaptor(&spec); err != nil {
+		setDegraded(status, err)
+		return ctrl.Result{}, nil
+	}
+
+	if req.Namespace == "" {
+		for _, builder := range spec.Builders {
+			if builder.BuilderRef.Kind == "Builder" && builder.BuilderRef.Namespace == "" {
+				setDegraded(status, fmt.Errorf(
+					"builders.%s: builderRef must set the namespace of Builder %s", builder.Name, builder.BuilderRef.Name))
+				return ctrl.Result{}, nil
+			}
+		}
+	}
+
+	waiting, err := r.resolveBuilders(ctx, o, spec.Builders, status)
+	if err != nil {
+		setDegraded(status, err)
+		return ctrl.Result{}, err
+	}
+
+	if err := r.reconcileObserver(ctx, o, spec.Observer, status); err != nil {
+		setDegraded(status, err)
+		return ctrl.Result{}, err
+	}
+
+	if waiting != "" {
+		kdexv1alpha1.SetConditions(
+			&status.Conditions,
+			kdexv1alpha1.ConditionStatuses{
+				Degraded:    metav1.ConditionFalse,
+				Progressing: metav1.ConditionTrue,
+				Ready:       metav1.ConditionFalse,
+			},
+			kdexv1alpha1.ConditionReasonReconciling,
+			waiting,
+		)
+
+		// kpack resources are not watched, since kpack may not be installed, so readiness is polled instead.
+		return ctrl.Result{RequeueAfter: r.RequeueDelay}, nil
+	}
+
+	kdexv1alpha1.SetConditions(
+		&status.Conditions,
+		kdexv1alpha1.ConditionStatuses{
+			Degraded:    metav1.ConditionFalse,
+			Progressing: metav1.ConditionFalse,
+			Ready:       metav1.ConditionTrue,
+		},
+		kdexv1alpha1.ConditionReasonReconcileSuccess,
+		"Reconciliation successful",
+	)
+
+	log.V(1).Info("reconciled")
+
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *KDexFaaSAdaptorReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&kdexv1alpha1.KDexFaaSAdaptor{}).
+		Owns(&batchv1.CronJob{}).
+		Watches(
+			&kdexv1alpha1.KDexClusterFaaSAdaptor{},
+			&handler.EnqueueRequestForObject{}).
+		Watches(
+			&batchv1.CronJob{},
+			handler.EnqueueRequestForOwner(
+				mgr.GetScheme(), mgr.GetRESTMapper(), &kdexv1alpha1.KDexClusterFaaSAdaptor{}, handler.OnlyControllerOwner())).
+		WithOptions(controller.TypedOptions[reconcile.Request]{
+			LogConstructor: LogConstructor("kdexfaasadaptor", mgr),
+		}).
+		Named("kdexfaasadaptor").
+		Complete(r)
+}
+
+// resolveBuilders looks up the kpack Builder or ClusterBuilder of each builder. It returns a message naming the first
+// one which is missing or not ready, or "" when all of them can build.
+func (r *KDexFaaSAdaptorReconciler) resolveBuilders(
+	ctx context.Context,
+	faasAdaptor client.Object,
+	builders []kdexv1alpha1.Builder,
+	status *kdexv1alpha1.KDexObjectStatus,
+) (string, error) {
+	waiting := ""
+
+	for _, builder := range builders {
+		ref := builder.BuilderRef
+
+		key := client.ObjectKey{Name: ref.Name}
+		if ref.Kind == "Builder" {
+			key.Namespace = ref.Namespace
+			if key.Namespace == "" {
+				key.Namespace = faasAdaptor.GetNamespace()
+			}
+		}
+
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(kpackVersion.WithKind(ref.Kind))
+
+		err := r.Get(ctx, key, obj)
+		switch {
+		case meta.IsNoMatchError(err):
+			return fmt.Sprintf("Waiting for kpack to be installed; %s is not served.", kpackVersion.WithKind(ref.Kind)), nil
+		case errors.IsNotFound(err):
+			if waiting == "" {
+				waiting = fmt.Sprintf("Waiting for %s %s to be created.", ref.Kind, ref.Name)
+			}
+			delete(status.Attributes, builder.Name+builderGenerationAttributeSuffix)
+			continue
+		case err != nil:
+			return "", err
+		}
+
+		status.Attributes[builder.Name+builderGenerationAttributeSuffix] = fmt.Sprintf("%d", obj.GetGeneration())
+
+		if !kpackReady(obj) && waiting == "" {
+			waiting = fmt.Sprintf("Waiting for %s %s to be ready.", ref.Kind, ref.Name)
+		}
+	}
+
+	return waiting, nil
+}
+
+// kpackReady reports whether the Ready condition of a kpack resource is true.
+func kpackReady(obj *unstructured.Unstructured) bool {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, condition := range conditions {
+		c, ok := condition.(map[string]any)
+		if ok && c["type"] == "Ready" {
+			return c["status"] == string(metav1.ConditionTrue)
+		}
+	}
+	return false
+}
+
+// reconcileObserver applies the CronJob which periodically runs the observer of the adaptor, or deletes it when the
+// adaptor has no observer.
+func (r *KDexFaaSAdaptorReconciler) reconcileObserver(
+	ctx context.Context,
+	faasAdaptor client.Object,
+	observer *kdexv1alpha1.Observer,
+	status *kdexv1alpha1.KDexObjectStatus,
+) error {
+	namespace := faasAdaptor.GetNamespace()
+	if namespace == "" {
+		namespace = r.ObserverNamespace
+	}
+
+	cronJob := &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      observerName(faasAdaptor),
+			Namespace: namespace,
+		},
+	}
+
+	if observer == nil {
+		delete(status.Attributes, observerAttribute)
+
+		if namespace == "" {
+			return nil
+		}
+
+		if err := r.Get(ctx, client.ObjectKeyFromObject(cronJob), cronJob); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		if !metav1.IsControlledBy(cronJob, faasAdaptor) {
+			return nil
+		}
+
+		return client.IgnoreNotFound(r.Delete(ctx, cronJob))
+	}
+
+	if namespace == "" {
+		return fmt.Errorf("no namespace is configured for the observers of KDexClusterFaaSAdaptors")
+	}
+
+	schedule := observer.Schedule
+	if schedule == "" {
+		schedule = defaultObserverSchedule
+	}
+
+	labels := map[string]string{
+		"app.kubernetes.io/name": kdexFaaSObserver,
+		"kdex.dev/instance":      cronJob.Name,
+	}
+
+	cronJob.Labels = labels
+	cronJob.Spec = batchv1.CronJobSpec{
+		ConcurrencyPolicy: batchv1.ForbidConcurrent,
+		JobTemplate: batchv1.JobTemplateSpec{
+			Spec: batchv1.JobSpec{
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: labels,
+					},
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{
+							{
+								Args:    observer.Args,
+								Command: observer.Command,
+								Env:     observer.Env,
+								Image:   observer.Image,
+								Name:    "observer",
+							},
+						},
+						RestartPolicy:      corev1.RestartPolicyNever,
+						ServiceAccountName: observer.ServiceAccountName,
+					},
+				},
+			},
+		},
+		Schedule: schedule,
+	}
+
+	if err := ctrl.SetControllerReference(faasAdaptor, cronJob, r.Scheme); err != nil {
+		return err
+	}
+
+	cronJob.SetGroupVersionKind(batchv1.SchemeGroupVersion.WithKind("CronJob"))
+
+	desired, err := toApplyConfiguration(cronJob)
+	if err != nil {
+		return err
+	}
+
+	existing := &batchv1.CronJob{}
+	exists := true
+	if err := r.Get(ctx, client.ObjectKeyFromObject(cronJob), existing); err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		exists = false
+	}
+
+	if err := r.Apply(
+		ctx,
+		client.ApplyConfigurationFromUnstructured(desired),
+		client.FieldOwner(fieldManager),
+		client.ForceOwnership,
+	); err != nil {
+		return err
+	}
+
+	switch {
+	case !exists:
+		r.Recorder.Eventf(faasAdaptor, cronJob, corev1.EventTypeNormal, EventReasonCreated, eventActionApply,
+			"Created CronJob %s/%s", cronJob.Namespace, cronJob.Name)
+	case existing.ResourceVersion != desired.GetResourceVersion():
+		r.Recorder.Eventf(faasAdaptor, cronJob, corev1.EventTypeNormal, EventReasonUpdated, eventActionApply,
+			"Updated CronJob %s/%s", cronJob.Namespace, cronJob.Name)
+	}
+
+	status.Attributes[observerAttribute] = cronJob.Namespace + "/" + cronJob.Name
+
+	return nil
+}
+
+// observerName returns the name of the observer CronJob of an adaptor. The observers of cluster scoped adaptors share
+// a namespace with those of the adaptors of that namespace, hence the prefix.
+func observerName(faasAdaptor client.Object) string {
+	if faasAdaptor.GetNamespace() == "" {
+		return "kdex-cluster-" + faasAdaptor.GetName() + "-observer"
+	}
+	return faasAdaptor.GetName() + "-observer"
+}
@@ -0,0 +1,209 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	kdexv1alpha1 "kdex.dev/crds/api/v1alpha1"
+)
+
+func faasAdaptorSpec(builder string) kdexv1alpha1.KDexFaaSAdaptorSpec {
+	return kdexv1alpha1.KDexFaaSAdaptorSpec{
+		Builders: []kdexv1alpha1.Builder{
+			{
+				BuilderRef: kdexv1alpha1.KDexObjectReference{Kind: "ClusterBuilder", Name: builder},
+				Languages:  []string{"go"},
+				Name:       "tiny",
+			},
+		},
+		DefaultBuilderGenerator: "tiny/go",
+		Deployer: kdexv1alpha1.Deployer{
+			Image: "ghcr.io/kdex-tech/knative-deployer:0.1.1",
+		},
+		Generators: []kdexv1alpha1.Generator{
+			{
+				Git: kdexv1alpha1.Git{
+					CommitterEmail: "generator@kdex.dev",
+					CommitterName:  "KDex Generator",
+					Image:          "ghcr.io/kdex-tech/cli-tools:0.3.4",
+				},
+				Image:    "ghcr.io/kdex-tech/fngogen:0.1.1",
+				Language: "go",
+			},
+		},
+		Provider: "knative",
+	}
+}
+
+var _ = Describe("KDexFaaSAdaptor Controller", func() {
+	Context("When reconciling a resource", func() {
+		const resourceName = "test-resource"
+
+		ctx := context.Background()
+
+		AfterEach(func() {
+			cleanupResources(namespace)
+		})
+
+		It("it waits for its builders", func() {
+			resource := &kdexv1alpha1.KDexFaaSAdaptor{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: namespace,
+				},
+				Spec: faasAdaptorSpec("waiting-builder"),
+			}
+
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+			assertResourceReady(
+				ctx, k8sClient, resourceName, namespace,
+				&kdexv1alpha1.KDexFaaSAdaptor{}, false)
+
+			setClusterBuilder(ctx, k8sClient, "waiting-builder", false)
+
+			assertResourceReady(
+				ctx, k8sClient, resourceName, namespace,
+				&kdexv1alpha1.KDexFaaSAdaptor{}, false)
+
+			setClusterBuilder(ctx, k8sClient, "waiting-builder", true)
+
+			assertResourceReady(
+				ctx, k8sClient, resourceName, namespace,
+				&kdexv1alpha1.KDexFaaSAdaptor{}, true)
+
+			Eventually(func(g Gomega) {
+				adaptor := &kdexv1alpha1.KDexFaaSAdaptor{}
+				g.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resourceName, Namespace: namespace}, adaptor)).To(Succeed())
+				g.Expect(adaptor.Status.Attributes).To(HaveKey("tiny" + builderGenerationAttributeSuffix))
+			}).Should(Succeed())
+		})
+
+		It("it is degraded when its default generator has no builder", func() {
+			spec := faasAdaptorSpec("tiny-builder")
+			spec.DefaultBuilderGenerator = "base/go"
+
+			resource := &kdexv1alpha1.KDexFaaSAdaptor{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: namespace,
+				},
+				Spec: spec,
+			}
+
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+			Eventually(func(g Gomega) {
+				adaptor := &kdexv1alpha1.KDexFaaSAdaptor{}
+				g.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resourceName, Namespace: namespace}, adaptor)).To(Succeed())
+				g.Expect(meta.IsStatusConditionTrue(
+					adaptor.Status.Conditions, string(kdexv1alpha1.ConditionTypeDegraded))).To(BeTrue())
+			}).Should(Succeed())
+		})
+
+		It("it owns the CronJob of its observer", func() {
+			setClusterBuilder(ctx, k8sClient, "tiny-builder", true)
+
+			spec := faasAdaptorSpec("tiny-builder")
+			spec.Observer = &kdexv1alpha1.Observer{
+				Command: []string{"/deployer", "observe"},
+				Image:   "ghcr.io/kdex-tech/knative-deployer:0.1.1",
+			}
+
+			resource := &kdexv1alpha1.KDexFaaSAdaptor{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: namespace,
+				},
+				Spec: spec,
+			}
+
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+			assertResourceReady(
+				ctx, k8sClient, resourceName, namespace,
+				&kdexv1alpha1.KDexFaaSAdaptor{}, true)
+
+			key := types.NamespacedName{Name: resourceName + "-observer", Namespace: namespace}
+
+			Eventually(func(g Gomega) {
+				cronJob := &batchv1.CronJob{}
+				g.Expect(k8sClient.Get(ctx, key, cronJob)).To(Succeed())
+				g.Expect(cronJob.Spec.Schedule).To(Equal(defaultObserverSchedule))
+				g.Expect(cronJob.OwnerReferences).To(HaveLen(1))
+				g.Expect(cronJob.OwnerReferences[0].Name).To(Equal(resourceName))
+
+				containers := cronJob.Spec.JobTemplate.Spec.Template.Spec.Containers
+				g.Expect(containers).To(HaveLen(1))
+				g.Expect(containers[0].Image).To(Equal("ghcr.io/kdex-tech/knative-deployer:0.1.1"))
+				g.Expect(containers[0].Command).To(Equal([]string{"/deployer", "observe"}))
+			}).Should(Succeed())
+
+			Eventually(func(g Gomega) {
+				adaptor := &kdexv1alpha1.KDexFaaSAdaptor{}
+				g.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resourceName, Namespace: namespace}, adaptor)).To(Succeed())
+				adaptor.Spec.Observer = nil
+				g.Expect(k8sClient.Update(ctx, adaptor)).To(Succeed())
+			}).Should(Succeed())
+
+			Eventually(func(g Gomega) {
+				err := k8sClient.Get(ctx, key, &batchv1.CronJob{})
+				g.Expect(errors.IsNotFound(err)).To(BeTrue())
+			}).Should(Succeed())
+		})
+
+		It("it runs the observer of a cluster adaptor in the observer namespace", func() {
+			setClusterBuilder(ctx, k8sClient, "tiny-builder", true)
+
+			spec := faasAdaptorSpec("tiny-builder")
+			spec.Observer = &kdexv1alpha1.Observer{
+				Image:    "ghcr.io/kdex-tech/knative-deployer:0.1.1",
+				Schedule: "*/1 * * * *",
+			}
+
+			resource := &kdexv1alpha1.KDexClusterFaaSAdaptor{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: resourceName,
+				},
+				Spec: spec,
+			}
+
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+			assertResourceReady(
+				ctx, k8sClient, resourceName, "",
+				&kdexv1alpha1.KDexClusterFaaSAdaptor{}, true)
+
+			Eventually(func(g Gomega) {
+				cronJob := &batchv1.CronJob{}
+				g.Expect(k8sClient.Get(ctx, types.NamespacedName{
+					Name:      "kdex-cluster-" + resourceName + "-observer",
+					Namespace: secondNamespace,
+				}, cronJob)).To(Succeed())
+				g.Expect(cronJob.Spec.Schedule).To(Equal("*/1 * * * *"))
+			}).Should(Succeed())
+		})
+	})
+})
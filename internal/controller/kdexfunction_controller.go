@@ -17,19 +17,123 @@ limitations under the License.
 package controller
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"os"
+	"slices"
+	"time"
 
 	nexuswebhook "github.com/kdex-tech/nexus-manager/internal/webhook"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/events"
 	kdexv1alpha1 "kdex.dev/crds/api/v1alpha1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
+const builderAttribute = "builder"
+
 // KDexFunctionReconciler reconciles a KDexFunction object
 type KDexFunctionReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	APIReader    client.Reader
+	Recorder     events.EventRecorder
+	RequeueDelay time.Duration
+	Scheme       *runtime.Scheme
+}
+
+// Reconcile drives a KDexFunction through its lifecycle: the OpenAPI contract is validated, a generator and a builder
+// are chosen from the FaaS adaptor of the host, the source is generated by a Job, built into an image by kpack and
+// deployed by a Job running the deployer of the adaptor. A function which brings its own source or executable skips
+// the states it does not need. A change of the spec starts a new pass.
+func (r *KDexFunctionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (res ctrl.Result, err error) {
+	log := logf.FromContext(ctx)
+
+	var function kdexv1alpha1.KDexFunction
+	if err := r.Get(ctx, req.NamespacedName, &function); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	status := &function.Status.KDexObjectStatus
+
+	if status.Attributes == nil {
+		status.Attributes = make(map[string]string)
+	}
+
+	base := function.DeepCopy()
+	previousConditions := slices.Clone(status.Conditions)
+
+	if isPaused(&function) {
+		return ctrl.Result{}, pauseReconcile(ctx, r.Client, r.Recorder, base, &function, status)
+	}
+	resumeReconcile(r.Recorder, &function, status)
+
+	// Defer status update
+	defer func() {
+		res, err = finishReconcile(ctx, r.Client, r.Recorder, base, &function, previousConditions, res, err)
+
+		log.V(2).Info("status", "status", function.Status, "err", err, "res", res)
+	}()
+
+	if status.ObservedGeneration != function.Generation {
+		resetFunctionStatus(&function.Status)
+	}
+
+	kdexv1alpha1.SetConditions(
+		&status.Conditions,
+		kdexv1alpha1.ConditionStatuses{
+			Degraded:    metav1.ConditionFalse,
+			Progressing: metav1.ConditionTrue,
+			Ready:       metav1.ConditionUnknown,
+		},
+		kdexv1alpha1.ConditionReasonReconciling,
+		"Reconciling",
+	)
+
+	host, shouldReturn, r1, err := ResolveHost(ctx, r.Client, &function, &status.Conditions, &function.Spec.HostRef, r.RequeueDelay)
+	if shouldReturn {
+		return r1, err
+	}
+
+	status.Attributes["host.generation"] = fmt.Sprintf("%d", host.GetGeneration())
+
+	if err := r.deleteStaleJobs(ctx, &function, host); err != nil {
+		setDegraded(status, err)
+		return ctrl.Result{}, err
+	}
+
+	if host.Spec.FaaSAdaptorRef == nil {
+		return ctrl.Result{}, r.fail(&function, reconcile.TerminalError(fmt.Errorf("host %s has no spec.faasAdaptorRef", host.Name)))
+	}
+
+	faasAdaptorObj, shouldReturn, r1, err := ResolveKDexObjectReference(ctx, r.Client, &function, &status.Conditions, host.Spec.FaaSAdaptorRef, r.RequeueDelay)
+	if shouldReturn {
+		return r1, err
+	}
+
+	status.Attributes[faasAdaptorGenerationAttribute] = fmt.Sprintf("%d", faasAdaptorObj.GetGeneration())
+
+	var faasAdaptor *kdexv1alpha1.KDexFaaSAdaptorSpec
+	switch v := faasAdaptorObj.(type) {
+	case *kdexv1alpha1.KDexFaaSAdaptor:
+		faasAdaptor = &v.Spec
+	case *kdexv1alpha1.KDexClusterFaaSAdaptor:
+		faasAdaptor = &v.Spec
+	}
+
+	plan, err := planFunction(&function, faasAdaptor)
+	if err != nil {
+		return ctrl.Result{}, r.fail(&function, reconcile.TerminalError(err))
+	}
+
+	return r.advance(ctx, &function, host, faasAdaptor, plan)
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -37,7 +141,7 @@ func (r *KDexFunctionReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	if os.Getenv("ENABLE_WEBHOOKS") != FALSE {
 		err := ctrl.NewWebhookManagedBy(mgr, &kdexv1alpha1.KDexFunction{}).
 			WithDefaulter(&nexuswebhook.KDexFunctionDefaulter[*kdexv1alpha1.KDexFunction]{}).
-			WithValidator(&nexuswebhook.KDexFunctionValidator[*kdexv1alpha1.KDexFunction]{}).
+			WithValidator(nexuswebhook.RecordRejections[*kdexv1alpha1.KDexFunction](r.Recorder, &nexuswebhook.KDexFunctionValidator[*kdexv1alpha1.KDexFunction]{})).
 			Complete()
 
 		if err != nil {
@@ -45,5 +149,184 @@ func (r *KDexFunctionReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		}
 	}
 
-	return nil
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&kdexv1alpha1.KDexFunction{}).
+		Owns(&batchv1.Job{}).
+		Watches(
+			&kdexv1alpha1.KDexHost{},
+			MakeHandlerByReferencePath(r.Client, r.Scheme, &kdexv1alpha1.KDexFunction{}, &kdexv1alpha1.KDexFunctionList{}, "{.Spec.HostRef}")).
+		WithOptions(controller.TypedOptions[reconcile.Request]{
+			LogConstructor: LogConstructor("kdexfunction", mgr),
+		}).
+		Named("kdexfunction").
+		Complete(r)
+}
+
+// advance moves the function from state to state until it is ready or one of the states has to wait: for a Job,
+// which is watched, for a kpack build, which is polled, or for the URL of the function.
+//
+// nolint:gocyclo
+func (r *KDexFunctionReconciler) advance(
+	ctx context.Context,
+	function *kdexv1alpha1.KDexFunction,
+	host *kdexv1alpha1.KDexHost,
+	faasAdaptor *kdexv1alpha1.KDexFaaSAdaptorSpec,
+	plan functionPlan,
+) (ctrl.Result, error) {
+	origin := function.Spec.Origin
+
+	for {
+		switch function.Status.State {
+		case "", kdexv1alpha1.KDexFunctionStatePending:
+			// Webhooks may be skipped, so the contract is validated again.
+			if _, err := (&nexuswebhook.KDexFunctionValidator[*kdexv1alpha1.KDexFunction]{}).ValidateCreate(ctx, function); err != nil {
+				return ctrl.Result{}, r.fail(function, reconcile.TerminalError(err))
+			}
+
+			r.setState(function, kdexv1alpha1.KDexFunctionStateOpenAPIValid, "The OpenAPI contract is valid.")
+
+		case kdexv1alpha1.KDexFunctionStateOpenAPIValid:
+			detail := "The function is deployed from its executable."
+			if plan.generator != nil {
+				function.Status.Generator = plan.generator.DeepCopy()
+			}
+			if plan.builder != nil {
+				function.Status.Attributes[builderAttribute] = plan.builder.Name
+				detail = fmt.Sprintf("The function is built by builder %s.", plan.builder.Name)
+			}
+
+			r.setState(function, kdexv1alpha1.KDexFunctionStateBuildValid, detail)
+
+		case kdexv1alpha1.KDexFunctionStateBuildValid:
+			switch {
+			case origin.Executable != nil:
+				function.Status.Executable = origin.Executable.DeepCopy()
+				r.setState(function, kdexv1alpha1.KDexFunctionStateExecutableAvailable,
+					fmt.Sprintf("The executable is %s.", origin.Executable.Image))
+				continue
+			case origin.Source != nil:
+				function.Status.Source = origin.Source.DeepCopy()
+				r.setState(function, kdexv1alpha1.KDexFunctionStateSourceAvailable,
+					fmt.Sprintf("The source is %s at %s.", origin.Source.Repository, origin.Source.Revision))
+				continue
+			}
+
+			source, waiting, err := r.generateSource(ctx, function, host, plan.generator)
+			if err != nil {
+				return ctrl.Result{}, r.fail(function, err)
+			}
+			if waiting != "" {
+				return r.wait(function, waiting, 0)
+			}
+
+			function.Status.Source = source
+			r.setState(function, kdexv1alpha1.KDexFunctionStateSourceAvailable,
+				fmt.Sprintf("Generated the source in %s at %s.", source.Repository, source.Revision))
+
+		case kdexv1alpha1.KDexFunctionStateSourceAvailable:
+			image, waiting, err := r.buildImage(ctx, function, host, plan.builder)
+			if err != nil {
+				return ctrl.Result{}, r.fail(function, err)
+			}
+			if waiting != "" {
+				// kpack resources are not watched, since kpack may not be installed, so builds are polled instead.
+				return r.wait(function, waiting, r.RequeueDelay)
+			}
+
+			function.Status.Executable = &kdexv1alpha1.Executable{Image: image}
+			r.setState(function, kdexv1alpha1.KDexFunctionStateExecutableAvailable, fmt.Sprintf("Built the image %s.", image))
+
+		case kdexv1alpha1.KDexFunctionStateExecutableAvailable:
+			url, waiting, err := r.deployFunction(ctx, function, host, faasAdaptor)
+			if err != nil {
+				return ctrl.Result{}, r.fail(function, err)
+			}
+			if waiting != "" {
+				return r.wait(function, waiting, 0)
+			}
+
+			function.Status.URL = url
+			r.setState(function, kdexv1alpha1.KDexFunctionStateFunctionDeployed, "Deployed the function.")
+
+		case kdexv1alpha1.KDexFunctionStateFunctionDeployed:
+			// A deployer which does not report the URL leaves it to the observer of the adaptor.
+			if function.Status.URL == "" {
+				return r.wait(function, "Waiting for the URL of the function to be reported.", 0)
+			}
+
+			r.setState(function, kdexv1alpha1.KDexFunctionStateReady, fmt.Sprintf("The function is served at %s.", function.Status.URL))
+
+		case kdexv1alpha1.KDexFunctionStateReady:
+			kdexv1alpha1.SetConditions(
+				&function.Status.Conditions,
+				kdexv1alpha1.ConditionStatuses{
+					Degraded:    metav1.ConditionFalse,
+					Progressing: metav1.ConditionFalse,
+					Ready:       metav1.ConditionTrue,
+				},
+				kdexv1alpha1.ConditionReasonReconcileSuccess,
+				"Reconciliation successful",
+			)
+
+			logf.FromContext(ctx).V(1).Info("reconciled", "url", function.Status.URL)
+
+			return ctrl.Result{}, nil
+
+		default:
+			return ctrl.Result{}, r.fail(function, reconcile.TerminalError(fmt.Errorf("unknown state %s", function.Status.State)))
+		}
+	}
+}
+
+// setState moves the function to state and records the move as an Event.
+func (r *KDexFunctionReconciler) setState(function *kdexv1alpha1.KDexFunction, state kdexv1alpha1.KDexFunctionState, detail string) {
+	function.Status.State = state
+	function.Status.Detail = detail
+
+	r.Recorder.Eventf(function, nil, corev1.EventTypeNormal, string(state), eventActionReconcile, "%s", detail)
+}
+
+// fail records why the function cannot leave its state. A terminal error, such as a failed Job, is not retried until
+// the function or its host changes; any other error is returned to be retried.
+func (r *KDexFunctionReconciler) fail(function *kdexv1alpha1.KDexFunction, err error) error {
+	cause := err
+	if errors.Is(err, reconcile.TerminalError(nil)) {
+		cause = errors.Unwrap(err)
+		err = nil
+	}
+
+	function.Status.Detail = cause.Error()
+	setDegraded(&function.Status.KDexObjectStatus, cause)
+
+	return err
+}
+
+func (r *KDexFunctionReconciler) wait(function *kdexv1alpha1.KDexFunction, detail string, requeueAfter time.Duration) (ctrl.Result, error) {
+	function.Status.Detail = detail
+
+	kdexv1alpha1.SetConditions(
+		&function.Status.Conditions,
+		kdexv1alpha1.ConditionStatuses{
+			Degraded:    metav1.ConditionFalse,
+			Progressing: metav1.ConditionTrue,
+			Ready:       metav1.ConditionFalse,
+		},
+		kdexv1alpha1.ConditionReasonReconciling,
+		detail,
+	)
+
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// resetFunctionStatus forgets the outcome of the previous pass.
+func resetFunctionStatus(status *kdexv1alpha1.KDexFunctionStatus) {
+	status.Detail = ""
+	status.Executable = nil
+	status.Generator = nil
+	status.OpenAPISchemaURL = ""
+	status.Source = nil
+	status.State = kdexv1alpha1.KDexFunctionStatePending
+	status.URL = ""
+
+	delete(status.Attributes, builderAttribute)
 }
@@ -0,0 +1,137 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	kdexv1alpha1 "kdex.dev/crds/api/v1alpha1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// buildImage applies the kpack Image which builds the source of the function with the builder of its plan and pushes
+// it to the image registry of the host. Once the build of the current spec of the Image succeeded, the digest
+// reference of the built image is returned.
+func (r *KDexFunctionReconciler) buildImage(
+	ctx context.Context,
+	function *kdexv1alpha1.KDexFunction,
+	host *kdexv1alpha1.KDexHost,
+	builder *kdexv1alpha1.Builder,
+) (string, string, error) {
+	registry := host.Spec.Registries.ImageRegistry.Host
+	if registry == "" {
+		return "", "", reconcile.TerminalError(fmt.Errorf("host %s has no image registry", host.Name))
+	}
+
+	source := function.Status.Source
+
+	git := map[string]any{
+		"revision": source.Revision,
+		"url":      source.Repository,
+	}
+	sourceSpec := map[string]any{"git": git}
+	if source.Path != "" && source.Path != "." {
+		sourceSpec["subPath"] = source.Path
+	}
+
+	builderRef := map[string]any{
+		"kind": builder.BuilderRef.Kind,
+		"name": builder.BuilderRef.Name,
+	}
+	if builder.BuilderRef.Kind == "Builder" {
+		namespace := builder.BuilderRef.Namespace
+		if namespace == "" {
+			namespace = function.Namespace
+		}
+		builderRef["namespace"] = namespace
+	}
+
+	env := make([]any, 0, len(builder.Env))
+	for _, envVar := range builder.Env {
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&envVar)
+		if err != nil {
+			return "", "", err
+		}
+		env = append(env, content)
+	}
+
+	serviceAccountName := builder.ServiceAccountName
+	if serviceAccountName == "" {
+		serviceAccountName = "default"
+	}
+
+	image := &unstructured.Unstructured{}
+	image.SetGroupVersionKind(kpackVersion.WithKind("Image"))
+	image.SetName(function.Name)
+	image.SetNamespace(function.Namespace)
+	image.SetLabels(map[string]string{functionLabel: function.Name})
+	image.Object["spec"] = map[string]any{
+		"build":              map[string]any{"env": env},
+		"builder":            builderRef,
+		"serviceAccountName": serviceAccountName,
+		"source":             sourceSpec,
+		"tag":                fmt.Sprintf("%s/%s/%s", registry, function.Namespace, function.Name),
+	}
+
+	if err := ctrl.SetControllerReference(function, image, r.Scheme); err != nil {
+		return "", "", err
+	}
+
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(image.GroupVersionKind())
+	exists := true
+	if err := r.Get(ctx, client.ObjectKeyFromObject(image), existing); err != nil {
+		switch {
+		case meta.IsNoMatchError(err):
+			return "", fmt.Sprintf("Waiting for kpack to be installed; %s is not served.", image.GroupVersionKind()), nil
+		case !errors.IsNotFound(err):
+			return "", "", err
+		}
+		exists = false
+	}
+
+	if err := r.Apply(
+		ctx,
+		client.ApplyConfigurationFromUnstructured(image),
+		client.FieldOwner(fieldManager),
+		client.ForceOwnership,
+	); err != nil {
+		return "", "", err
+	}
+
+	switch {
+	case !exists:
+		r.Recorder.Eventf(function, image, corev1.EventTypeNormal, EventReasonCreated, eventActionApply,
+			"Created Image %s/%s", image.GetNamespace(), image.GetName())
+	case existing.GetResourceVersion() != image.GetResourceVersion():
+		r.Recorder.Eventf(function, image, corev1.EventTypeNormal, EventReasonUpdated, eventActionApply,
+			"Updated Image %s/%s", image.GetNamespace(), image.GetName())
+	}
+
+	observedGeneration, _, _ := unstructured.NestedInt64(image.Object, "status", "observedGeneration")
+	if observedGeneration != image.GetGeneration() {
+		return "", fmt.Sprintf("Waiting for Image %s to be built.", image.GetName()), nil
+	}
+
+	conditions, _, _ := unstructured.NestedSlice(image.Object, "status", "conditions")
+	for _, condition := range conditions {
+		c, ok := condition.(map[string]any)
+		if ok && c["type"] == "Ready" && c["status"] == string(metav1.ConditionFalse) {
+			return "", "", reconcile.TerminalError(fmt.Errorf("image %s failed to build: %v", image.GetName(), c["message"]))
+		}
+	}
+
+	latestImage, _, _ := unstructured.NestedString(image.Object, "status", "latestImage")
+	if !kpackReady(image) || latestImage == "" {
+		return "", fmt.Sprintf("Waiting for Image %s to be built.", image.GetName()), nil
+	}
+
+	return latestImage, "", nil
+}
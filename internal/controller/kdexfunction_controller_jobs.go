@@ -0,0 +1,374 @@
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/kdex-tech/nexus-manager/internal/utils"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	kdexv1alpha1 "kdex.dev/crds/api/v1alpha1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// The labels of the Jobs of a function, which tie them to the generations of the function and of its host they
+	// were run for.
+	functionLabel           = "kdex.dev/function"
+	functionGenerationLabel = "kdex.dev/function-generation"
+	hostGenerationLabel     = "kdex.dev/host-generation"
+
+	workspacePath = "/workspace"
+)
+
+// generateSource runs the generator of the function in a Job, which commits the generated source to a branch of the
+// repository given by the git Secret of the host. The commit container reports the revision it pushed as its
+// termination message.
+func (r *KDexFunctionReconciler) generateSource(
+	ctx context.Context,
+	function *kdexv1alpha1.KDexFunction,
+	host *kdexv1alpha1.KDexHost,
+	generator *kdexv1alpha1.Generator,
+) (*kdexv1alpha1.Source, string, error) {
+	secret, err := r.gitSecret(ctx, host)
+	if err != nil {
+		return nil, "", err
+	}
+
+	repository, err := gitRepository(secret)
+	if err != nil {
+		return nil, "", err
+	}
+
+	api, err := json.Marshal(function.Spec.API)
+	if err != nil {
+		return nil, "", err
+	}
+
+	branch := fmt.Sprintf("kdex/%s/%s", function.Namespace, function.Name)
+
+	subDirectory := generator.Git.FunctionSubDirectory
+	if subDirectory == "" {
+		subDirectory = "."
+	}
+
+	env := append(functionEnv(function),
+		corev1.EnvVar{Name: "FUNCTION_API", Value: string(api)},
+		corev1.EnvVar{Name: "FUNCTION_ENTRYPOINT", Value: generator.Entrypoint},
+		corev1.EnvVar{Name: "FUNCTION_LANGUAGE", Value: generator.Language},
+		corev1.EnvVar{Name: "WORKSPACE", Value: workspacePath},
+		corev1.EnvVar{Name: "GIT_BRANCH", Value: branch},
+		corev1.EnvVar{Name: "GIT_COMMITTER_EMAIL", Value: generator.Git.CommitterEmail},
+		corev1.EnvVar{Name: "GIT_COMMITTER_NAME", Value: generator.Git.CommitterName},
+		corev1.EnvVar{Name: "GIT_FUNCTION_SUBDIRECTORY", Value: subDirectory},
+	)
+
+	for _, key := range []string{"host", "org", "repo", "username", "password"} {
+		env = append(env, corev1.EnvVar{
+			Name: "GIT_" + strings.ToUpper(key),
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: secret.Name},
+					Key:                  key,
+				},
+			},
+		})
+	}
+
+	workspace := []corev1.VolumeMount{{Name: "workspace", MountPath: workspacePath}}
+
+	job, err := functionJob(function, host, "codegen", corev1.PodSpec{
+		Containers: []corev1.Container{
+			{
+				Env:          env,
+				Image:        generator.Git.Image,
+				Name:         "commit",
+				VolumeMounts: workspace,
+				WorkingDir:   workspacePath,
+			},
+		},
+		InitContainers: []corev1.Container{
+			{
+				Args:         generator.Args,
+				Command:      generator.Command,
+				Env:          env,
+				Image:        generator.Image,
+				Name:         "generate",
+				VolumeMounts: workspace,
+				WorkingDir:   workspacePath,
+			},
+		},
+		RestartPolicy:      corev1.RestartPolicyNever,
+		ServiceAccountName: generator.ServiceAccountName,
+		Volumes: []corev1.Volume{
+			{
+				Name:         "workspace",
+				VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+			},
+		},
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	done, revision, err := r.runJob(ctx, function, job, "commit")
+	if err != nil {
+		return nil, "", err
+	}
+	if !done {
+		return nil, fmt.Sprintf("Waiting for Job %s to generate the source.", job.Name), nil
+	}
+
+	if revision == "" {
+		revision = branch
+	}
+
+	return &kdexv1alpha1.Source{
+		Path:       subDirectory,
+		Repository: repository,
+		Revision:   revision,
+	}, "", nil
+}
+
+// deployFunction runs the deployer of the adaptor in a Job. A deployer may report the URL of the function as the
+// termination message of its container, otherwise the URL is left to the observer of the adaptor.
+func (r *KDexFunctionReconciler) deployFunction(
+	ctx context.Context,
+	function *kdexv1alpha1.KDexFunction,
+	host *kdexv1alpha1.KDexHost,
+	faasAdaptor *kdexv1alpha1.KDexFaaSAdaptorSpec,
+) (string, string, error) {
+	executable := function.Status.Executable
+
+	env := append(functionEnv(function),
+		corev1.EnvVar{Name: "FAAS_PROVIDER", Value: faasAdaptor.Provider},
+		corev1.EnvVar{Name: "FUNCTION_IMAGE", Value: executable.Image},
+	)
+
+	if executable.Scaling != nil {
+		scaling, err := json.Marshal(executable.Scaling)
+		if err != nil {
+			return "", "", err
+		}
+		env = append(env, corev1.EnvVar{Name: "FUNCTION_SCALING", Value: string(scaling)})
+	}
+
+	deployer := faasAdaptor.Deployer
+
+	job, err := functionJob(function, host, "deploy", corev1.PodSpec{
+		Containers: []corev1.Container{
+			{
+				Args:    deployer.Args,
+				Command: deployer.Command,
+				Env:     MergeEnvVars(slices.Clone(deployer.Env), env),
+				Image:   deployer.Image,
+				Name:    "deploy",
+			},
+		},
+		RestartPolicy:      corev1.RestartPolicyNever,
+		ServiceAccountName: deployer.ServiceAccountName,
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	done, message, err := r.runJob(ctx, function, job, "deploy")
+	if err != nil {
+		return "", "", err
+	}
+	if !done {
+		return "", fmt.Sprintf("Waiting for Job %s to deploy the function.", job.Name), nil
+	}
+
+	if message == "" {
+		return "", "", nil
+	}
+
+	u, err := url.Parse(message)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return "", "", reconcile.TerminalError(
+			fmt.Errorf("job %s reported %q, which is not the URL of the function", job.Name, message))
+	}
+
+	return u.String(), "", nil
+}
+
+// functionEnv returns the environment shared by the Jobs of a function.
+func functionEnv(function *kdexv1alpha1.KDexFunction) []corev1.EnvVar {
+	return []corev1.EnvVar{
+		{Name: "FUNCTION_BASE_PATH", Value: function.Spec.API.BasePath},
+		{Name: "FUNCTION_GENERATION", Value: fmt.Sprintf("%d", function.Generation)},
+		{Name: "FUNCTION_HOST", Value: function.Spec.HostRef.Name},
+		{Name: "FUNCTION_NAME", Value: function.Name},
+		{Name: "FUNCTION_NAMESPACE", Value: function.Namespace},
+	}
+}
+
+// functionJob returns the Job which runs a phase of the current pass of the function. The Job is named after a hash of
+// its inputs, the generations of the function and of its host and the pod spec, so that a change to any of them runs a
+// new Job rather than finding the one which ran, or failed, before.
+func functionJob(
+	function *kdexv1alpha1.KDexFunction,
+	host *kdexv1alpha1.KDexHost,
+	phase string,
+	podSpec corev1.PodSpec,
+) (*batchv1.Job, error) {
+	labels := map[string]string{
+		functionLabel:           function.Name,
+		functionGenerationLabel: fmt.Sprintf("%d", function.Generation),
+		hostGenerationLabel:     fmt.Sprintf("%d", host.Generation),
+	}
+
+	spec, err := json.Marshal(podSpec)
+	if err != nil {
+		return nil, err
+	}
+	inputs := fmt.Sprintf("%d/%d/%s", function.Generation, host.Generation, spec)
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels:    labels,
+			Name:      functionJobName(function.Name, phase, inputs),
+			Namespace: function.Namespace,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: utils.Ptr(int32(0)),
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: podSpec,
+			},
+		},
+	}, nil
+}
+
+// functionJobName returns "<function>-<phase>-<hash of inputs>", the name of the function being truncated so that the
+// name fits the job-name label of the pods of the Job.
+func functionJobName(function string, phase string, inputs string) string {
+	hash := fmt.Sprintf("%x", sha256.Sum256([]byte(inputs)))[:10]
+	suffix := fmt.Sprintf("-%s-%s", phase, hash)
+
+	prefix := function
+	if maxLength := validation.DNS1123LabelMaxLength - len(suffix); len(prefix) > maxLength {
+		prefix = strings.TrimRight(prefix[:maxLength], "-.")
+	}
+
+	return prefix + suffix
+}
+
+// runJob creates the Job unless it exists and reports whether it is complete, along with the termination message of
+// the given container of its successful pod. A failed Job is an error.
+func (r *KDexFunctionReconciler) runJob(
+	ctx context.Context,
+	function *kdexv1alpha1.KDexFunction,
+	job *batchv1.Job,
+	container string,
+) (bool, string, error) {
+	existing := &batchv1.Job{}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(job), existing); err != nil {
+		if !errors.IsNotFound(err) {
+			return false, "", err
+		}
+
+		if err := ctrl.SetControllerReference(function, job, r.Scheme); err != nil {
+			return false, "", err
+		}
+
+		if err := r.Create(ctx, job); err != nil {
+			return false, "", err
+		}
+
+		r.Recorder.Eventf(function, job, corev1.EventTypeNormal, EventReasonCreated, eventActionApply,
+			"Created Job %s/%s", job.Namespace, job.Name)
+
+		return false, "", nil
+	}
+
+	for _, condition := range existing.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+
+		switch condition.Type {
+		case batchv1.JobFailed:
+			return false, "", reconcile.TerminalError(fmt.Errorf("job %s failed: %s", existing.Name, condition.Message))
+		case batchv1.JobComplete:
+			message, err := r.terminationMessage(ctx, existing, container)
+			return true, message, err
+		}
+	}
+
+	return false, "", nil
+}
+
+// terminationMessage returns the termination message of a container of the successful pod of a Job.
+func (r *KDexFunctionReconciler) terminationMessage(ctx context.Context, job *batchv1.Job, container string) (string, error) {
+	// The cached client would start watching every pod of the cluster.
+	var pods corev1.PodList
+	if err := r.APIReader.List(ctx, &pods,
+		client.InNamespace(job.Namespace),
+		client.MatchingLabels{batchv1.JobNameLabel: job.Name},
+	); err != nil {
+		return "", err
+	}
+
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != corev1.PodSucceeded {
+			continue
+		}
+
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.Name == container && status.State.Terminated != nil {
+				return strings.TrimSpace(status.State.Terminated.Message), nil
+			}
+		}
+	}
+
+	return "", nil
+}
+
+// deleteStaleJobs deletes the Jobs of the function which were run for a previous generation of its spec or of its
+// host, failed ones included, which would otherwise hold the function in their failure.
+func (r *KDexFunctionReconciler) deleteStaleJobs(
+	ctx context.Context,
+	function *kdexv1alpha1.KDexFunction,
+	host *kdexv1alpha1.KDexHost,
+) error {
+	var jobs batchv1.JobList
+	if err := r.List(ctx, &jobs,
+		client.InNamespace(function.Namespace),
+		client.MatchingLabels{functionLabel: function.Name},
+	); err != nil {
+		return err
+	}
+
+	functionGeneration := fmt.Sprintf("%d", function.Generation)
+	hostGeneration := fmt.Sprintf("%d", host.Generation)
+
+	for _, job := range jobs.Items {
+		current := job.Labels[functionGenerationLabel] == functionGeneration && job.Labels[hostGenerationLabel] == hostGeneration
+		if current || !metav1.IsControlledBy(&job, function) {
+			continue
+		}
+
+		if err := r.Delete(ctx, &job, client.PropagationPolicy(metav1.DeletePropagationBackground)); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+
+		r.Recorder.Eventf(function, &job, corev1.EventTypeNormal, EventReasonDeleted, eventActionDelete,
+			"Deleted Job %s/%s", job.Namespace, job.Name)
+	}
+
+	return nil
+}
@@ -0,0 +1,129 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	kdexv1alpha1 "kdex.dev/crds/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// secretTypeAnnotation classifies the Secrets of the service account of a host, see spec.serviceAccountRef.
+	secretTypeAnnotation = "kdex.dev/secret-type"
+
+	gitSecretType = "git"
+)
+
+// functionPlan holds what a function is generated and built with. Both are nil for a function which brings its own
+// executable, and the generator is nil for one which brings its own source.
+type functionPlan struct {
+	builder   *kdexv1alpha1.Builder
+	generator *kdexv1alpha1.Generator
+}
+
+// planFunction chooses the generator and the builder of a function. Unless the origin of the function sets them, the
+// generator is the one of the language of the adaptor's defaultBuilderGenerator and the builder is the default
+// builder, or else the first builder of the adaptor which builds the language of the generator.
+func planFunction(function *kdexv1alpha1.KDexFunction, faasAdaptor *kdexv1alpha1.KDexFaaSAdaptorSpec) (functionPlan, error) {
+	origin := function.Spec.Origin
+	defaultBuilder, defaultLanguage, _ := strings.Cut(faasAdaptor.DefaultBuilderGenerator, "/")
+
+	builderNamed := func(name string) *kdexv1alpha1.Builder {
+		index := slices.IndexFunc(faasAdaptor.Builders, func(builder kdexv1alpha1.Builder) bool {
+			return builder.Name == name
+		})
+		if index < 0 {
+			return nil
+		}
+		return &faasAdaptor.Builders[index]
+	}
+
+	switch {
+	case origin.Executable != nil:
+		if strings.TrimSpace(origin.Executable.Image) == "" {
+			return functionPlan{}, fmt.Errorf("spec.origin.executable.image is required")
+		}
+		return functionPlan{}, nil
+
+	case origin.Source != nil:
+		if origin.Source.Builder != nil {
+			return functionPlan{builder: origin.Source.Builder}, nil
+		}
+		builder := builderNamed(defaultBuilder)
+		if builder == nil {
+			return functionPlan{}, fmt.Errorf("the FaaS adaptor has no builder named %s", defaultBuilder)
+		}
+		return functionPlan{builder: builder}, nil
+	}
+
+	generator := origin.Generator
+	if generator == nil {
+		index := slices.IndexFunc(faasAdaptor.Generators, func(generator kdexv1alpha1.Generator) bool {
+			return generator.Language == defaultLanguage
+		})
+		if index < 0 {
+			return functionPlan{}, fmt.Errorf("the FaaS adaptor has no generator for %s", defaultLanguage)
+		}
+		generator = &faasAdaptor.Generators[index]
+	}
+
+	builder := builderNamed(defaultBuilder)
+	if builder == nil || !slices.Contains(builder.Languages, generator.Language) {
+		index := slices.IndexFunc(faasAdaptor.Builders, func(builder kdexv1alpha1.Builder) bool {
+			return slices.Contains(builder.Languages, generator.Language)
+		})
+		if index < 0 {
+			return functionPlan{}, fmt.Errorf("the FaaS adaptor has no builder for %s", generator.Language)
+		}
+		builder = &faasAdaptor.Builders[index]
+	}
+
+	return functionPlan{builder: builder, generator: generator}, nil
+}
+
+// gitSecret returns the Secret of the service account of the host which gives the Git repository generated source is
+// pushed to.
+func (r *KDexFunctionReconciler) gitSecret(ctx context.Context, host *kdexv1alpha1.KDexHost) (*corev1.Secret, error) {
+	var serviceAccount corev1.ServiceAccount
+	if err := r.Get(ctx, client.ObjectKey{Name: host.Spec.ServiceAccountRef.Name, Namespace: host.Namespace}, &serviceAccount); err != nil {
+		return nil, err
+	}
+
+	for _, ref := range serviceAccount.Secrets {
+		var secret corev1.Secret
+		if err := r.Get(ctx, client.ObjectKey{Name: ref.Name, Namespace: host.Namespace}, &secret); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+
+		if secret.Annotations[secretTypeAnnotation] == gitSecretType {
+			return &secret, nil
+		}
+	}
+
+	return nil, reconcile.TerminalError(fmt.Errorf(
+		"service account %s has no Secret annotated %s=%s", serviceAccount.Name, secretTypeAnnotation, gitSecretType))
+}
+
+// gitRepository returns the address of the repository given by a git Secret.
+func gitRepository(secret *corev1.Secret) (string, error) {
+	keys := []string{"host", "org", "repo"}
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		value := strings.TrimSpace(string(secret.Data[key]))
+		if value == "" {
+			return "", reconcile.TerminalError(fmt.Errorf("secret %s has no %s", secret.Name, key))
+		}
+		parts = append(parts, value)
+	}
+
+	return "https://" + strings.Join(parts, "/"), nil
+}
@@ -5,10 +5,15 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	kdexv1alpha1 "kdex.dev/crds/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 var _ = Describe("KDexFunction Controller", func() {
@@ -91,5 +96,425 @@ var _ = Describe("KDexFunction Controller", func() {
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("json: error calling MarshalJSON for type *runtime.RawExtension: unexpected end of JSON input"))
 		})
+
+		It("it deploys an executable with the deployer of the FaaS adaptor", func() {
+			setClusterBuilder(ctx, k8sClient, "tiny-builder", true)
+
+			Expect(k8sClient.Create(ctx, &kdexv1alpha1.KDexFaaSAdaptor{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "knative",
+					Namespace: namespace,
+				},
+				Spec: faasAdaptorSpec("tiny-builder"),
+			})).To(Succeed())
+
+			addOrUpdateHost(ctx, k8sClient, kdexv1alpha1.KDexHost{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "function-host",
+					Namespace: namespace,
+				},
+				Spec: kdexv1alpha1.KDexHostSpec{
+					BrandName: "KDex Tech",
+					FaaSAdaptorRef: &kdexv1alpha1.KDexObjectReference{
+						Kind: "KDexFaaSAdaptor",
+						Name: "knative",
+					},
+					Organization: "KDex Tech Inc.",
+					Routing: kdexv1alpha1.Routing{
+						Domains: []string{
+							"kdex.dev",
+						},
+					},
+				},
+			})
+
+			assertResourceReady(
+				ctx, k8sClient, "function-host", namespace,
+				&kdexv1alpha1.KDexHost{}, true)
+
+			resource := &kdexv1alpha1.KDexFunction{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: namespace,
+				},
+				Spec: kdexv1alpha1.KDexFunctionSpec{
+					HostRef: corev1.LocalObjectReference{Name: "function-host"},
+					API: kdexv1alpha1.API{
+						BasePath: "/v1/api",
+						Paths: map[string]kdexv1alpha1.PathItem{
+							"/v1/api/test": {},
+						},
+					},
+					Origin: kdexv1alpha1.FunctionOrigin{
+						Executable: &kdexv1alpha1.Executable{
+							Image: "ghcr.io/kdex-tech/echo:0.1.0",
+						},
+					},
+				},
+			}
+
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+			var key types.NamespacedName
+
+			Eventually(func(g Gomega) {
+				job := getFunctionJob(ctx, g, k8sClient, namespace, resourceName, "deploy")
+				key = client.ObjectKeyFromObject(job)
+				g.Expect(len(job.Name)).To(BeNumerically("<=", 63))
+				g.Expect(job.OwnerReferences).To(HaveLen(1))
+				g.Expect(job.OwnerReferences[0].Name).To(Equal(resourceName))
+
+				containers := job.Spec.Template.Spec.Containers
+				g.Expect(containers).To(HaveLen(1))
+				g.Expect(containers[0].Image).To(Equal("ghcr.io/kdex-tech/knative-deployer:0.1.1"))
+				g.Expect(containers[0].Env).To(ContainElement(corev1.EnvVar{
+					Name: "FUNCTION_IMAGE", Value: "ghcr.io/kdex-tech/echo:0.1.0",
+				}))
+
+				function := &kdexv1alpha1.KDexFunction{}
+				g.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resourceName, Namespace: namespace}, function)).To(Succeed())
+				g.Expect(function.Status.State).To(Equal(kdexv1alpha1.KDexFunctionStateExecutableAvailable))
+			}).Should(Succeed())
+
+			finishJob(ctx, k8sClient, key, true)
+
+			Eventually(func(g Gomega) {
+				function := &kdexv1alpha1.KDexFunction{}
+				g.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resourceName, Namespace: namespace}, function)).To(Succeed())
+				g.Expect(function.Status.State).To(Equal(kdexv1alpha1.KDexFunctionStateFunctionDeployed))
+				g.Expect(function.Status.Executable.Image).To(Equal("ghcr.io/kdex-tech/echo:0.1.0"))
+			}).Should(Succeed())
+		})
+
+		It("it runs a new Job once the host of a failed one changes", func() {
+			setClusterBuilder(ctx, k8sClient, "tiny-builder", true)
+
+			Expect(k8sClient.Create(ctx, &kdexv1alpha1.KDexFaaSAdaptor{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "knative",
+					Namespace: namespace,
+				},
+				Spec: faasAdaptorSpec("tiny-builder"),
+			})).To(Succeed())
+
+			host := kdexv1alpha1.KDexHost{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "function-host",
+					Namespace: namespace,
+				},
+				Spec: kdexv1alpha1.KDexHostSpec{
+					BrandName: "KDex Tech",
+					FaaSAdaptorRef: &kdexv1alpha1.KDexObjectReference{
+						Kind: "KDexFaaSAdaptor",
+						Name: "knative",
+					},
+					Organization: "KDex Tech Inc.",
+					Routing: kdexv1alpha1.Routing{
+						Domains: []string{
+							"kdex.dev",
+						},
+					},
+				},
+			}
+			addOrUpdateHost(ctx, k8sClient, host)
+
+			assertResourceReady(
+				ctx, k8sClient, "function-host", namespace,
+				&kdexv1alpha1.KDexHost{}, true)
+
+			resource := &kdexv1alpha1.KDexFunction{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: namespace,
+				},
+				Spec: kdexv1alpha1.KDexFunctionSpec{
+					HostRef: corev1.LocalObjectReference{Name: "function-host"},
+					API: kdexv1alpha1.API{
+						BasePath: "/v1/api",
+						Paths: map[string]kdexv1alpha1.PathItem{
+							"/v1/api/test": {},
+						},
+					},
+					Origin: kdexv1alpha1.FunctionOrigin{
+						Executable: &kdexv1alpha1.Executable{
+							Image: "ghcr.io/kdex-tech/echo:0.1.0",
+						},
+					},
+				},
+			}
+
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+			var failed types.NamespacedName
+			Eventually(func(g Gomega) {
+				failed = client.ObjectKeyFromObject(getFunctionJob(ctx, g, k8sClient, namespace, resourceName, "deploy"))
+			}).Should(Succeed())
+
+			finishJob(ctx, k8sClient, failed, false)
+
+			Eventually(func(g Gomega) {
+				function := &kdexv1alpha1.KDexFunction{}
+				g.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resourceName, Namespace: namespace}, function)).To(Succeed())
+				g.Expect(function.Status.Detail).To(ContainSubstring("failed"))
+			}).Should(Succeed())
+
+			host.Spec.BrandName = "KDex"
+			addOrUpdateHost(ctx, k8sClient, host)
+
+			Eventually(func(g Gomega) {
+				err := k8sClient.Get(ctx, failed, &batchv1.Job{})
+				g.Expect(errors.IsNotFound(err)).To(BeTrue())
+
+				job := getFunctionJob(ctx, g, k8sClient, namespace, resourceName, "deploy")
+				g.Expect(job.Name).NotTo(Equal(failed.Name))
+				g.Expect(job.Status.Conditions).To(BeEmpty())
+			}).Should(Succeed())
+		})
+
+		It("it generates the source and builds it with the builder of its language", func() {
+			setClusterBuilder(ctx, k8sClient, "tiny-builder", true)
+			setClusterBuilder(ctx, k8sClient, "python-builder", true)
+
+			faasAdaptor := faasAdaptorSpec("tiny-builder")
+			faasAdaptor.Builders = append(faasAdaptor.Builders, kdexv1alpha1.Builder{
+				BuilderRef: kdexv1alpha1.KDexObjectReference{Kind: "ClusterBuilder", Name: "python-builder"},
+				Languages:  []string{"python"},
+				Name:       "python",
+			})
+
+			Expect(k8sClient.Create(ctx, &kdexv1alpha1.KDexFaaSAdaptor{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "knative",
+					Namespace: namespace,
+				},
+				Spec: faasAdaptor,
+			})).To(Succeed())
+
+			createGitServiceAccount(ctx, "function-git")
+
+			addOrUpdateHost(ctx, k8sClient, kdexv1alpha1.KDexHost{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "function-host",
+					Namespace: namespace,
+				},
+				Spec: kdexv1alpha1.KDexHostSpec{
+					BrandName: "KDex Tech",
+					FaaSAdaptorRef: &kdexv1alpha1.KDexObjectReference{
+						Kind: "KDexFaaSAdaptor",
+						Name: "knative",
+					},
+					Organization: "KDex Tech Inc.",
+					Registries: kdexv1alpha1.Registries{
+						ImageRegistry: kdexv1alpha1.Registry{Host: "registry.kdex.dev"},
+					},
+					Routing: kdexv1alpha1.Routing{
+						Domains: []string{
+							"kdex.dev",
+						},
+					},
+					ServiceAccountRef: corev1.LocalObjectReference{Name: "function-git"},
+				},
+			})
+
+			assertResourceReady(
+				ctx, k8sClient, "function-host", namespace,
+				&kdexv1alpha1.KDexHost{}, true)
+
+			resource := &kdexv1alpha1.KDexFunction{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: namespace,
+				},
+				Spec: kdexv1alpha1.KDexFunctionSpec{
+					HostRef: corev1.LocalObjectReference{Name: "function-host"},
+					API: kdexv1alpha1.API{
+						BasePath: "/v1/api",
+						Paths: map[string]kdexv1alpha1.PathItem{
+							"/v1/api/test": {},
+						},
+					},
+					Origin: kdexv1alpha1.FunctionOrigin{
+						Generator: &kdexv1alpha1.Generator{
+							Git: kdexv1alpha1.Git{
+								CommitterEmail: "generator@kdex.dev",
+								CommitterName:  "KDex Generator",
+								Image:          "ghcr.io/kdex-tech/cli-tools:0.3.4",
+							},
+							Image:    "ghcr.io/kdex-tech/fnpygen:0.1.0",
+							Language: "python",
+						},
+					},
+				},
+			}
+
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+			var key types.NamespacedName
+			Eventually(func(g Gomega) {
+				job := getFunctionJob(ctx, g, k8sClient, namespace, resourceName, "codegen")
+				key = client.ObjectKeyFromObject(job)
+				g.Expect(job.Labels).To(HaveKeyWithValue(hostGenerationLabel, Not(BeEmpty())))
+
+				pod := job.Spec.Template.Spec
+				g.Expect(pod.InitContainers).To(ConsistOf(HaveField("Image", "ghcr.io/kdex-tech/fnpygen:0.1.0")))
+				g.Expect(pod.Containers).To(ConsistOf(HaveField("Image", "ghcr.io/kdex-tech/cli-tools:0.3.4")))
+				g.Expect(pod.Containers[0].Env).To(ContainElement(corev1.EnvVar{
+					Name: "GIT_BRANCH", Value: "kdex/" + namespace + "/" + resourceName,
+				}))
+
+				function := &kdexv1alpha1.KDexFunction{}
+				g.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resourceName, Namespace: namespace}, function)).To(Succeed())
+				g.Expect(function.Status.State).To(Equal(kdexv1alpha1.KDexFunctionStateBuildValid))
+				g.Expect(function.Status.Attributes).To(HaveKeyWithValue(builderAttribute, "python"))
+			}).Should(Succeed())
+
+			finishJob(ctx, k8sClient, key, true)
+
+			Eventually(func(g Gomega) {
+				image := getKpackImage(ctx, g, resourceName)
+
+				revision, _, _ := unstructured.NestedString(image.Object, "spec", "source", "git", "revision")
+				g.Expect(revision).To(Equal("kdex/" + namespace + "/" + resourceName))
+				url, _, _ := unstructured.NestedString(image.Object, "spec", "source", "git", "url")
+				g.Expect(url).To(Equal("https://github.com/kdex-tech/functions"))
+				builder, _, _ := unstructured.NestedString(image.Object, "spec", "builder", "name")
+				g.Expect(builder).To(Equal("python-builder"))
+
+				function := &kdexv1alpha1.KDexFunction{}
+				g.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resourceName, Namespace: namespace}, function)).To(Succeed())
+				g.Expect(function.Status.State).To(Equal(kdexv1alpha1.KDexFunctionStateSourceAvailable))
+			}).Should(Succeed())
+		})
+
+		It("it builds its source into an image with kpack", func() {
+			setClusterBuilder(ctx, k8sClient, "tiny-builder", true)
+
+			Expect(k8sClient.Create(ctx, &kdexv1alpha1.KDexFaaSAdaptor{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "knative",
+					Namespace: namespace,
+				},
+				Spec: faasAdaptorSpec("tiny-builder"),
+			})).To(Succeed())
+
+			addOrUpdateHost(ctx, k8sClient, kdexv1alpha1.KDexHost{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "function-host",
+					Namespace: namespace,
+				},
+				Spec: kdexv1alpha1.KDexHostSpec{
+					BrandName: "KDex Tech",
+					FaaSAdaptorRef: &kdexv1alpha1.KDexObjectReference{
+						Kind: "KDexFaaSAdaptor",
+						Name: "knative",
+					},
+					Organization: "KDex Tech Inc.",
+					Registries: kdexv1alpha1.Registries{
+						ImageRegistry: kdexv1alpha1.Registry{Host: "registry.kdex.dev"},
+					},
+					Routing: kdexv1alpha1.Routing{
+						Domains: []string{
+							"kdex.dev",
+						},
+					},
+				},
+			})
+
+			assertResourceReady(
+				ctx, k8sClient, "function-host", namespace,
+				&kdexv1alpha1.KDexHost{}, true)
+
+			resource := &kdexv1alpha1.KDexFunction{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: namespace,
+				},
+				Spec: kdexv1alpha1.KDexFunctionSpec{
+					HostRef: corev1.LocalObjectReference{Name: "function-host"},
+					API: kdexv1alpha1.API{
+						BasePath: "/v1/api",
+						Paths: map[string]kdexv1alpha1.PathItem{
+							"/v1/api/test": {},
+						},
+					},
+					Origin: kdexv1alpha1.FunctionOrigin{
+						Source: &kdexv1alpha1.Source{
+							Path:       "echo",
+							Repository: "https://github.com/kdex-tech/functions",
+							Revision:   "v0.1.0",
+						},
+					},
+				},
+			}
+
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+			Eventually(func(g Gomega) {
+				image := getKpackImage(ctx, g, resourceName)
+
+				g.Expect(image.GetOwnerReferences()).To(ConsistOf(HaveField("Name", resourceName)))
+				tag, _, _ := unstructured.NestedString(image.Object, "spec", "tag")
+				g.Expect(tag).To(Equal("registry.kdex.dev/" + namespace + "/" + resourceName))
+				subPath, _, _ := unstructured.NestedString(image.Object, "spec", "source", "subPath")
+				g.Expect(subPath).To(Equal("echo"))
+				builder, _, _ := unstructured.NestedString(image.Object, "spec", "builder", "name")
+				g.Expect(builder).To(Equal("tiny-builder"))
+
+				g.Expect(unstructured.SetNestedField(image.Object, image.GetGeneration(), "status", "observedGeneration")).To(Succeed())
+				g.Expect(unstructured.SetNestedField(image.Object, "registry.kdex.dev/"+namespace+"/"+resourceName+"@sha256:0123456789abcdef", "status", "latestImage")).To(Succeed())
+				g.Expect(unstructured.SetNestedSlice(image.Object, []any{
+					map[string]any{"status": string(metav1.ConditionTrue), "type": "Ready"},
+				}, "status", "conditions")).To(Succeed())
+				g.Expect(k8sClient.Status().Update(ctx, image)).To(Succeed())
+			}).Should(Succeed())
+
+			Eventually(func(g Gomega) {
+				function := &kdexv1alpha1.KDexFunction{}
+				g.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resourceName, Namespace: namespace}, function)).To(Succeed())
+				g.Expect(function.Status.Source.Revision).To(Equal("v0.1.0"))
+				g.Expect(function.Status.Executable.Image).To(Equal("registry.kdex.dev/" + namespace + "/" + resourceName + "@sha256:0123456789abcdef"))
+				g.Expect(function.Status.State).To(Equal(kdexv1alpha1.KDexFunctionStateExecutableAvailable))
+			}).Should(Succeed())
+		})
 	})
 })
+
+// createGitServiceAccount creates a service account giving the git Secret generated source is pushed with.
+func createGitServiceAccount(ctx context.Context, name string) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{secretTypeAnnotation: gitSecretType},
+			Name:        name,
+			Namespace:   namespace,
+		},
+		StringData: map[string]string{
+			"host":     "github.com",
+			"org":      "kdex-tech",
+			"password": "secret",
+			"repo":     "functions",
+			"username": "kdex",
+		},
+	}
+	Expect(k8sClient.Create(ctx, secret)).To(Succeed())
+
+	serviceAccount := &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Secrets: []corev1.ObjectReference{{Name: name}},
+	}
+	Expect(k8sClient.Create(ctx, serviceAccount)).To(Succeed())
+	DeferCleanup(func() {
+		Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, serviceAccount))).To(Succeed())
+	})
+}
+
+// getKpackImage returns the kpack Image of the function.
+func getKpackImage(ctx context.Context, g Gomega, function string) *unstructured.Unstructured {
+	image := &unstructured.Unstructured{}
+	image.SetGroupVersionKind(kpackVersion.WithKind("Image"))
+	g.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: function, Namespace: namespace}, image)).To(Succeed())
+
+	return image
+}
@@ -19,16 +19,26 @@ package controller
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
 	"fmt"
 	"maps"
 	"os"
+	"slices"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/kdex-tech/nexus-manager/internal/domains"
+	"github.com/kdex-tech/nexus-manager/internal/extensions"
+	"github.com/kdex-tech/nexus-manager/internal/hostoptions"
+	"github.com/kdex-tech/nexus-manager/internal/metrics"
+	"github.com/kdex-tech/nexus-manager/internal/patch"
 	nexuswebhook "github.com/kdex-tech/nexus-manager/internal/webhook"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -36,29 +46,42 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
 	kdexv1alpha1 "kdex.dev/crds/api/v1alpha1"
 	"kdex.dev/crds/configuration"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
 const (
 	kdexWeb           = "kdex-host"
 	hostFinalizerName = "kdex.dev/kdex-nexus-host-finalizer"
+	configChecksumKey = "kdex.dev/config-checksum"
 	hostIndexKey      = "spec.hostRef.name"
+
+	// firstReadyAttribute records when the host first became ready.
+	firstReadyAttribute = "ready.first"
 )
 
 // KDexHostReconciler reconciles a KDexHost object
 type KDexHostReconciler struct {
 	client.Client
+	APIReader     client.Reader
 	Configuration configuration.NexusConfiguration
+	Extensions    extensions.Configuration
+	Recorder      events.EventRecorder
 	RequeueDelay  time.Duration
 	Scheme        *runtime.Scheme
 
+	configurationChanged  chan event.GenericEvent
+	defaulter             *nexuswebhook.KDexHostDefaulter[*kdexv1alpha1.KDexHost]
 	mu                    sync.RWMutex
 	memoizedConfiguration string
 	memoizedDeployment    *appsv1.DeploymentSpec
@@ -78,13 +101,18 @@ func (r *KDexHostReconciler) Reconcile(ctx context.Context, req ctrl.Request) (r
 		host.Status.Attributes = make(map[string]string)
 	}
 
+	base := host.DeepCopy()
+	previousConditions := slices.Clone(host.Status.Conditions)
+
+	// A paused host is still deleted, its finalizer would otherwise hold the deletion until it is resumed.
+	if host.DeletionTimestamp.IsZero() && isPaused(&host) {
+		return ctrl.Result{}, pauseReconcile(ctx, r.Client, r.Recorder, base, &host, &host.Status)
+	}
+	resumeReconcile(r.Recorder, &host, &host.Status)
+
 	// Defer status update
 	defer func() {
-		host.Status.ObservedGeneration = host.Generation
-		if updateErr := r.Status().Update(ctx, &host); updateErr != nil {
-			err = updateErr
-			res = ctrl.Result{}
-		}
+		res, err = finishReconcile(ctx, r.Client, r.Recorder, base, &host, previousConditions, res, err)
 
 		log.V(2).Info("status", "status", host.Status, "err", err, "res", res)
 	}()
@@ -98,82 +126,7 @@ func (r *KDexHostReconciler) Reconcile(ctx context.Context, req ctrl.Request) (r
 			return ctrl.Result{Requeue: true}, nil
 		}
 	} else {
-		if controllerutil.ContainsFinalizer(&host, hostFinalizerName) {
-			internalHost := &kdexv1alpha1.KDexInternalHost{}
-			err := r.Get(ctx, req.NamespacedName, internalHost)
-			if err == nil {
-				if internalHost.DeletionTimestamp.IsZero() {
-					if err := r.Delete(ctx, internalHost); err != nil {
-						return ctrl.Result{}, err
-					}
-				}
-				// KDexInternalHost still exists. We wait.
-				return ctrl.Result{Requeue: true}, nil
-			}
-			if !errors.IsNotFound(err) {
-				return ctrl.Result{}, err
-			}
-
-			// Wait for internal utility pages to be gone
-			internalUtilityPages := &kdexv1alpha1.KDexInternalUtilityPageList{}
-			if err := r.List(ctx, internalUtilityPages, client.InNamespace(host.Namespace), client.MatchingFields{hostIndexKey: host.Name}); err != nil {
-				return ctrl.Result{}, err
-			}
-			if len(internalUtilityPages.Items) > 0 {
-				for _, iup := range internalUtilityPages.Items {
-					if iup.DeletionTimestamp.IsZero() {
-						if err := r.Delete(ctx, &iup); err != nil {
-							return ctrl.Result{}, err
-						}
-					}
-				}
-				// KDexInternalUtilityPage still exists. We wait.
-				return ctrl.Result{Requeue: true}, nil
-			}
-
-			// Wait for internal translations to be gone
-			internalTranslations := &kdexv1alpha1.KDexInternalTranslationList{}
-			if err := r.List(ctx, internalTranslations, client.InNamespace(host.Namespace), client.MatchingFields{hostIndexKey: host.Name}); err != nil {
-				return ctrl.Result{}, err
-			}
-			if len(internalTranslations.Items) > 0 {
-				for _, it := range internalTranslations.Items {
-					if it.DeletionTimestamp.IsZero() {
-						if err := r.Delete(ctx, &it); err != nil {
-							return ctrl.Result{}, err
-						}
-					}
-				}
-				// KDexInternalTranslation still exists. We wait.
-				return ctrl.Result{Requeue: true}, nil
-			}
-
-			deployment := &appsv1.Deployment{}
-			err = r.Get(ctx, req.NamespacedName, deployment)
-			if err == nil {
-				if deployment.DeletionTimestamp.IsZero() {
-					if err := r.Delete(ctx, deployment); err != nil {
-						return ctrl.Result{}, err
-					}
-				}
-				// Deployment still exists. We wait.
-				return ctrl.Result{Requeue: true}, nil
-			}
-			if !errors.IsNotFound(err) {
-				return ctrl.Result{}, err
-			}
-
-			// Deployment is gone. Clean up RBAC finalizers.
-			if err := r.cleanupRbacFinalizers(ctx, &host); err != nil {
-				return ctrl.Result{}, err
-			}
-
-			controllerutil.RemoveFinalizer(&host, hostFinalizerName)
-			if err := r.Update(ctx, &host); err != nil {
-				return ctrl.Result{}, err
-			}
-		}
-		return ctrl.Result{}, nil
+		return r.finalizeHost(ctx, &host)
 	}
 
 	kdexv1alpha1.SetConditions(
@@ -187,9 +140,33 @@ func (r *KDexHostReconciler) Reconcile(ctx context.Context, req ctrl.Request) (r
 		"Reconciling",
 	)
 
+	clearStaleDrift(&host)
+
+	// A host whose domains overlap those of a host which precedes it is left alone until the conflict is resolved.
+	// The webhook normally rejects such hosts but it fails open.
+	conflicts, err := domains.FindConflicts(ctx, r.Client, &host, host.Spec.Routing.Domains)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	for _, conflict := range conflicts {
+		if domains.Precedes(&conflict.Host, &host) {
+			kdexv1alpha1.SetConditions(
+				&host.Status.Conditions,
+				kdexv1alpha1.ConditionStatuses{
+					Degraded:    metav1.ConditionTrue,
+					Progressing: metav1.ConditionFalse,
+					Ready:       metav1.ConditionFalse,
+				},
+				kdexv1alpha1.ConditionReasonReconcileError,
+				conflict.String(),
+			)
+			return ctrl.Result{}, nil
+		}
+	}
+
 	// Resolve direct requirements from host spec
 
-	announcementRef, errorRef, loginRef, shouldReturn, err := r.resolveUtilityPages(ctx, &host)
+	utilityPageRefs, shouldReturn, err := r.resolveUtilityPages(ctx, &host)
 	if shouldReturn {
 		kdexv1alpha1.SetConditions(
 			&host.Status.Conditions,
@@ -204,6 +181,19 @@ func (r *KDexHostReconciler) Reconcile(ctx context.Context, req ctrl.Request) (r
 		return ctrl.Result{}, err
 	}
 
+	// The annotation was validated while resolving the utility pages.
+	maintenance, _ := r.getMaintenance(&host)
+	now := time.Now()
+	maintenanceRequeueAfter := setMaintenanceStatus(r.Recorder, &host, maintenance, now)
+
+	// Whichever way the reconcile ends, including while waiting on a dependency, it must run again when the
+	// maintenance window opens or closes.
+	defer func() {
+		if err == nil && maintenanceRequeueAfter > 0 && (res.RequeueAfter == 0 || maintenanceRequeueAfter < res.RequeueAfter) {
+			res.RequeueAfter = maintenanceRequeueAfter
+		}
+	}()
+
 	themeObj, shouldReturn, _, err := ResolveKDexObjectReference(ctx, r.Client, &host, &host.Status.Conditions, host.Spec.ThemeRef, r.RequeueDelay)
 	if shouldReturn {
 		kdexv1alpha1.SetConditions(
@@ -242,6 +232,17 @@ func (r *KDexHostReconciler) Reconcile(ctx context.Context, req ctrl.Request) (r
 		host.Status.Attributes["scriptLibrary.generation"] = fmt.Sprintf("%d", scriptLibraryObj.GetGeneration())
 	}
 
+	faasAdaptorObj, shouldReturn, r1, err := ResolveKDexObjectReference(ctx, r.Client, &host, &host.Status.Conditions, host.Spec.FaaSAdaptorRef, r.RequeueDelay)
+	if shouldReturn {
+		setFaaSAdaptorConditions(&host, faasAdaptorObj, err)
+		return r1, err
+	}
+
+	delete(host.Status.Attributes, faasAdaptorGenerationAttribute)
+	if faasAdaptorObj != nil {
+		host.Status.Attributes[faasAdaptorGenerationAttribute] = fmt.Sprintf("%d", faasAdaptorObj.GetGeneration())
+	}
+
 	translationRefs, shouldReturn, err := r.resolveTranslations(ctx, &host)
 	if shouldReturn {
 		if err == nil {
@@ -290,7 +291,13 @@ func (r *KDexHostReconciler) Reconcile(ctx context.Context, req ctrl.Request) (r
 		return ctrl.Result{}, err
 	}
 
-	clusterRoleBindingOp, err := r.createOrUpdateClusterRoleBinding(ctx, &host)
+	roleBindingOp, err := r.createOrUpdateRoleBinding(ctx, &host)
+	if err != nil {
+		setDegraded(&host.Status, err)
+		return ctrl.Result{}, err
+	}
+
+	certificateOp, certificateSecret, certificateRequeueAfter, err := r.createOrUpdateCertificate(ctx, &host)
 	if err != nil {
 		kdexv1alpha1.SetConditions(
 			&host.Status.Conditions,
@@ -320,6 +327,24 @@ func (r *KDexHostReconciler) Reconcile(ctx context.Context, req ctrl.Request) (r
 		return ctrl.Result{}, err
 	}
 
+	horizontalPodAutoscalerOp, err := r.createOrUpdateHorizontalPodAutoscaler(ctx, &host)
+	if err != nil {
+		setDegraded(&host.Status, err)
+		return ctrl.Result{}, err
+	}
+
+	podDisruptionBudgetOp, err := r.createOrUpdatePodDisruptionBudget(ctx, &host)
+	if err != nil {
+		setDegraded(&host.Status, err)
+		return ctrl.Result{}, err
+	}
+
+	networkPolicyOp, err := r.createOrUpdateNetworkPolicy(ctx, &host)
+	if err != nil {
+		setDegraded(&host.Status, err)
+		return ctrl.Result{}, err
+	}
+
 	serviceOp, err := r.createOrUpdateService(ctx, &host)
 	if err != nil {
 		kdexv1alpha1.SetConditions(
@@ -335,23 +360,48 @@ func (r *KDexHostReconciler) Reconcile(ctx context.Context, req ctrl.Request) (r
 		return ctrl.Result{}, err
 	}
 
-	for _, cond := range deployment.Status.Conditions {
-		if cond.Type == appsv1.DeploymentAvailable && cond.Status != corev1.ConditionTrue {
-			kdexv1alpha1.SetConditions(
-				&host.Status.Conditions,
-				kdexv1alpha1.ConditionStatuses{
-					Degraded:    metav1.ConditionFalse,
-					Progressing: metav1.ConditionTrue,
-					Ready:       metav1.ConditionFalse,
-				},
-				kdexv1alpha1.ConditionReasonReconcileSuccess,
-				fmt.Sprintf("Waiting for deployment %s/%s to be ready.", deployment.Namespace, deployment.Name),
-			)
-			return ctrl.Result{RequeueAfter: r.RequeueDelay}, err
+	diagnosis, err := r.diagnoseDeployment(ctx, &host, deployment)
+	if err != nil {
+		setDegraded(&host.Status, err)
+		return ctrl.Result{}, err
+	}
+
+	if !diagnosis.Available {
+		degraded, progressing := metav1.ConditionFalse, metav1.ConditionTrue
+		if diagnosis.Failed {
+			degraded, progressing = metav1.ConditionTrue, metav1.ConditionFalse
 		}
+		kdexv1alpha1.SetConditions(
+			&host.Status.Conditions,
+			kdexv1alpha1.ConditionStatuses{
+				Degraded:    degraded,
+				Progressing: progressing,
+				Ready:       metav1.ConditionFalse,
+			},
+			kdexv1alpha1.ConditionReason(diagnosis.Reason),
+			fmt.Sprintf("Waiting for deployment %s/%s to be ready: %s.", deployment.Namespace, deployment.Name, diagnosis),
+		)
+		return ctrl.Result{RequeueAfter: r.RequeueDelay}, nil
+	}
+
+	if err := r.reconcilePool(ctx, &host); err != nil {
+		kdexv1alpha1.SetConditions(
+			&host.Status.Conditions,
+			kdexv1alpha1.ConditionStatuses{
+				Degraded:    metav1.ConditionTrue,
+				Progressing: metav1.ConditionFalse,
+				Ready:       metav1.ConditionFalse,
+			},
+			kdexv1alpha1.ConditionReasonReconcileError,
+			err.Error(),
+		)
+		return ctrl.Result{}, err
 	}
 
-	internalHostOp, internalHost, err := r.createOrUpdateInternalHostResource(ctx, &host, announcementRef, errorRef, loginRef, translationRefs)
+	internalHostOp, internalHost, err := r.createOrUpdateInternalHostResource(
+		ctx, &host, utilityPageRefs, translationRefs, faasAdaptorObj, certificateSecret,
+		maintenanceAnnotations(maintenance, utilityPageRefs[hostoptions.MaintenanceUtilityPageType], now),
+	)
 	if err != nil {
 		kdexv1alpha1.SetConditions(
 			&host.Status.Conditions,
@@ -384,6 +434,14 @@ func (r *KDexHostReconciler) Reconcile(ctx context.Context, req ctrl.Request) (r
 		host.Status.Attributes["ingress"] = val
 	}
 
+	// Hosts which were ready before the attribute existed are not observed, their creation is too far behind.
+	if _, ok := host.Status.Attributes[firstReadyAttribute]; !ok {
+		host.Status.Attributes[firstReadyAttribute] = now.UTC().Format(time.RFC3339)
+		if !meta.IsStatusConditionTrue(previousConditions, string(kdexv1alpha1.ConditionTypeReady)) {
+			metrics.ObserveHostReady(host.CreationTimestamp, now)
+		}
+	}
+
 	kdexv1alpha1.SetConditions(
 		&host.Status.Conditions,
 		kdexv1alpha1.ConditionStatuses{
@@ -399,23 +457,40 @@ func (r *KDexHostReconciler) Reconcile(ctx context.Context, req ctrl.Request) (r
 		"reconciled",
 		"configMapOp", configMapOp,
 		"serviceAccountOp", serviceAccountOp,
-		"clusterRoleBindingOp", clusterRoleBindingOp,
+		"roleBindingOp", roleBindingOp,
+		"certificateOp", certificateOp,
 		"deploymentOp", deploymentOp,
+		"horizontalPodAutoscalerOp", horizontalPodAutoscalerOp,
+		"podDisruptionBudgetOp", podDisruptionBudgetOp,
+		"networkPolicyOp", networkPolicyOp,
 		"serviceOp", serviceOp,
 		"internalHostOp", internalHostOp,
 	)
 
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: certificateRequeueAfter}, nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *KDexHostReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.configurationChanged = make(chan event.GenericEvent, 1)
+
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &kdexv1alpha1.KDexHost{}, domains.IndexField, func(rawObj client.Object) []string {
+		return domains.IndexValues(rawObj.(*kdexv1alpha1.KDexHost))
+	}); err != nil {
+		return err
+	}
+
 	if os.Getenv("ENABLE_WEBHOOKS") != FALSE {
+		r.defaulter = &nexuswebhook.KDexHostDefaulter[*kdexv1alpha1.KDexHost]{
+			Configuration: r.getConfiguration(),
+		}
+
 		err := ctrl.NewWebhookManagedBy(mgr, &kdexv1alpha1.KDexHost{}).
-			WithDefaulter(&nexuswebhook.KDexHostDefaulter[*kdexv1alpha1.KDexHost]{
-				Configuration: r.Configuration,
-			}).
-			WithValidator(&nexuswebhook.KDexHostValidator[*kdexv1alpha1.KDexHost]{}).
+			WithDefaulter(r.defaulter).
+			WithValidator(nexuswebhook.RecordRejections[*kdexv1alpha1.KDexHost](r.Recorder, &nexuswebhook.KDexHostValidator[*kdexv1alpha1.KDexHost]{
+				Client:     mgr.GetClient(),
+				Extensions: r.getExtensions,
+			})).
 			Complete()
 
 		if err != nil {
@@ -443,16 +518,35 @@ func (r *KDexHostReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		return err
 	}
 
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &kdexv1alpha1.KDexFunction{}, hostIndexKey, func(rawObj client.Object) []string {
+		function := rawObj.(*kdexv1alpha1.KDexFunction)
+		if function.Spec.HostRef.Name == "" {
+			return nil
+		}
+		return []string{function.Spec.HostRef.Name}
+	}); err != nil {
+		return err
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&kdexv1alpha1.KDexHost{}).
-		Owns(&appsv1.Deployment{}).
+		// The Deployment of a pool is owned, not controlled, by each of its members.
+		Watches(
+			&appsv1.Deployment{},
+			handler.EnqueueRequestForOwner(mgr.GetScheme(), mgr.GetRESTMapper(), &kdexv1alpha1.KDexHost{})).
+		Owns(&autoscalingv2.HorizontalPodAutoscaler{}).
+		Owns(&policyv1.PodDisruptionBudget{}).
+		Owns(&networkingv1.NetworkPolicy{}).
 		Owns(&corev1.ConfigMap{}).
+		Owns(&corev1.Secret{}).
 		Owns(&corev1.Service{}).
 		Owns(&corev1.ServiceAccount{}).
 		Owns(&kdexv1alpha1.KDexInternalHost{}).
 		Owns(&kdexv1alpha1.KDexInternalTranslation{}).
 		Owns(&kdexv1alpha1.KDexInternalUtilityPage{}).
 		Owns(&rbacv1.ClusterRoleBinding{}).
+		Owns(&rbacv1.Role{}).
+		Owns(&rbacv1.RoleBinding{}).
 		Watches(
 			&kdexv1alpha1.KDexScriptLibrary{},
 			MakeHandlerByReferencePath(r.Client, r.Scheme, &kdexv1alpha1.KDexHost{}, &kdexv1alpha1.KDexHostList{}, "{.Spec.ScriptLibraryRef}")).
@@ -483,6 +577,23 @@ func (r *KDexHostReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Watches(
 			&kdexv1alpha1.KDexClusterUtilityPage{},
 			MakeHandlerByReferencePath(r.Client, r.Scheme, &kdexv1alpha1.KDexHost{}, &kdexv1alpha1.KDexHostList{}, "{.Spec.UtilityPages.AnnouncementRef}", "{.Spec.UtilityPages.ErrorRef}", "{.Spec.UtilityPages.LoginRef}")).
+		Watches(
+			&kdexv1alpha1.KDexUtilityPage{},
+			handler.EnqueueRequestsFromMapFunc(r.utilityPageHosts)).
+		Watches(
+			&kdexv1alpha1.KDexClusterUtilityPage{},
+			handler.EnqueueRequestsFromMapFunc(r.utilityPageHosts)).
+		Watches(
+			&kdexv1alpha1.KDexHost{},
+			handler.EnqueueRequestsFromMapFunc(r.conflictingHosts)).
+		Watches(
+			&kdexv1alpha1.KDexHost{},
+			handler.EnqueueRequestsFromMapFunc(r.poolPeers)).
+		Watches(
+			&kdexv1alpha1.KDexFunction{},
+			handler.EnqueueRequestsFromMapFunc(functionHost)).
+		WatchesRawSource(
+			source.Channel(r.configurationChanged, handler.EnqueueRequestsFromMapFunc(r.allHosts))).
 		WithOptions(controller.TypedOptions[reconcile.Request]{
 			LogConstructor: LogConstructor("kdexhost", mgr),
 		}).
@@ -490,35 +601,130 @@ func (r *KDexHostReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Complete(r)
 }
 
+// SetConfiguration atomically replaces the configuration, discards everything memoized from the previous one and
+// requeues every KDexHost so the new configuration is rolled out.
+func (r *KDexHostReconciler) SetConfiguration(
+	ctx context.Context,
+	config configuration.NexusConfiguration,
+	ext extensions.Configuration,
+) {
+	r.mu.Lock()
+	r.Configuration = config
+	r.Extensions = ext
+	r.memoizedConfiguration = ""
+	r.memoizedDeployment = nil
+	r.memoizedService = nil
+	r.mu.Unlock()
+
+	if r.defaulter != nil {
+		r.defaulter.SetConfiguration(config)
+	}
+
+	// A pending event already requeues every host.
+	select {
+	case r.configurationChanged <- event.GenericEvent{Object: &kdexv1alpha1.KDexHost{}}:
+	default:
+	}
+}
+
+func (r *KDexHostReconciler) allHosts(ctx context.Context, _ client.Object) []reconcile.Request {
+	var hosts kdexv1alpha1.KDexHostList
+	if err := r.List(ctx, &hosts); err != nil {
+		logf.FromContext(ctx).Error(err, "failed to list hosts for configuration change")
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(hosts.Items))
+	for _, host := range hosts.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: host.Name, Namespace: host.Namespace},
+		})
+	}
+
+	return requests
+}
+
+// conflictingHosts maps a KDexHost to the hosts whose domains overlap its own, so that a host waiting on a conflict is
+// reconciled again once the conflict is resolved.
+func (r *KDexHostReconciler) conflictingHosts(ctx context.Context, obj client.Object) []reconcile.Request {
+	host, ok := obj.(*kdexv1alpha1.KDexHost)
+	if !ok {
+		return nil
+	}
+
+	conflicts, err := domains.FindConflicts(ctx, r.Client, host, host.Spec.Routing.Domains)
+	if err != nil {
+		logf.FromContext(ctx).Error(err, "failed to list hosts with conflicting domains")
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(conflicts))
+	for _, conflict := range conflicts {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: conflict.Host.Name, Namespace: conflict.Host.Namespace},
+		})
+	}
+
+	return requests
+}
+
 func (r *KDexHostReconciler) cleanupRbacFinalizers(ctx context.Context, host *kdexv1alpha1.KDexHost) error {
-	// ClusterRoleBinding
-	clusterRoleBinding := &rbacv1.ClusterRoleBinding{}
-	clusterRoleBinding.Name = fmt.Sprintf("%s-%s", host.Name, host.Namespace)
-	if err := r.Get(ctx, types.NamespacedName{Name: clusterRoleBinding.Name}, clusterRoleBinding); err == nil {
-		if controllerutil.RemoveFinalizer(clusterRoleBinding, hostFinalizerName) {
-			if err := r.Update(ctx, clusterRoleBinding); err != nil {
+	// Cluster-scoped objects cannot be owned by the host, so they are deleted here rather than garbage collected.
+	// Those of a pool are deleted along with its last member.
+	if pool := hostoptions.GetPool(host.Annotations); pool == "" {
+		for _, obj := range []client.Object{
+			&rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: clusterRoleBindingName(host)}},
+			&rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: clusterRoleBindingName(host)}},
+		} {
+			if err := r.deleteWithFinalizer(ctx, host, obj); err != nil {
+				return err
+			}
+		}
+	} else {
+		members, err := r.poolMembers(ctx, host.Namespace, pool)
+		if err != nil {
+			return err
+		}
+		if len(members) == 0 {
+			if err := r.deletePool(ctx, host.Namespace, pool); err != nil {
 				return err
 			}
 		}
-	} else if !errors.IsNotFound(err) {
-		return err
 	}
 
-	// ServiceAccount
-	serviceAccount := &corev1.ServiceAccount{}
-	if err := r.Get(ctx, types.NamespacedName{Name: host.Name, Namespace: host.Namespace}, serviceAccount); err == nil {
-		if controllerutil.RemoveFinalizer(serviceAccount, hostFinalizerName) {
-			if err := r.Update(ctx, serviceAccount); err != nil {
-				return err
+	for _, obj := range []client.Object{
+		&rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Name: host.Name, Namespace: host.Namespace}},
+		&rbacv1.Role{ObjectMeta: metav1.ObjectMeta{Name: host.Name, Namespace: host.Namespace}},
+		&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: host.Name, Namespace: host.Namespace}},
+	} {
+		if err := r.Get(ctx, client.ObjectKeyFromObject(obj), obj); err == nil {
+			if controllerutil.RemoveFinalizer(obj, hostFinalizerName) {
+				if err := r.Update(ctx, obj); err != nil {
+					return err
+				}
 			}
+		} else if !errors.IsNotFound(err) {
+			return err
 		}
-	} else if !errors.IsNotFound(err) {
-		return err
 	}
 
 	return nil
 }
 
+func (r *KDexHostReconciler) getConfiguration() configuration.NexusConfiguration {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.Configuration
+}
+
+func (r *KDexHostReconciler) getExtensions() extensions.Configuration {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.Extensions
+}
+
 func (r *KDexHostReconciler) getMemoizedConfiguration() (string, error) {
 	r.mu.RLock()
 
@@ -593,34 +799,21 @@ func (r *KDexHostReconciler) createOrUpdateConfigMap(
 		return controllerutil.OperationResultNone, err
 	}
 
+	objectMeta, members, err := r.servingObjectMeta(ctx, host)
 	configMap := &corev1.ConfigMap{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      host.Name,
-			Namespace: host.Namespace,
+		ObjectMeta: objectMeta,
+		Data: map[string]string{
+			"config.yaml": configString,
 		},
 	}
 
-	op, err := ctrl.CreateOrUpdate(ctx, r.Client, configMap, func() error {
-		if configMap.CreationTimestamp.IsZero() {
-			if configMap.Annotations == nil {
-				configMap.Annotations = make(map[string]string)
-			}
-			maps.Copy(configMap.Annotations, host.Annotations)
-			if configMap.Labels == nil {
-				configMap.Labels = make(map[string]string)
-			}
-			maps.Copy(configMap.Labels, host.Labels)
-
-			configMap.Labels["app.kubernetes.io/name"] = kdexWeb
-			configMap.Labels["kdex.dev/instance"] = host.Name
-		}
-
-		configMap.Data = map[string]string{
-			"config.yaml": configString,
-		}
-
-		return ctrl.SetControllerReference(host, configMap, r.Scheme)
-	})
+	if err == nil {
+		err = r.setServingOwners(host, members, configMap)
+	}
+	op := controllerutil.OperationResultNone
+	if err == nil {
+		op, err = r.applyOwned(ctx, host, configMap)
+	}
 
 	log := logf.FromContext(ctx)
 
@@ -651,42 +844,40 @@ func (r *KDexHostReconciler) createOrUpdateConfigMap(
 func (r *KDexHostReconciler) createOrUpdateInternalHostResource(
 	ctx context.Context,
 	host *kdexv1alpha1.KDexHost,
-	announcementRef *corev1.LocalObjectReference,
-	errorRef *corev1.LocalObjectReference,
-	loginRef *corev1.LocalObjectReference,
+	utilityPageRefs map[kdexv1alpha1.KDexUtilityPageType]*corev1.LocalObjectReference,
 	translationRefs []corev1.LocalObjectReference,
+	faasAdaptor client.Object,
+	certificateSecret string,
+	maintenanceAnnotations map[string]string,
 ) (controllerutil.OperationResult, *kdexv1alpha1.KDexInternalHost, error) {
 	internalHost := &kdexv1alpha1.KDexInternalHost{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      host.Name,
-			Namespace: host.Namespace,
-		},
+		ObjectMeta: r.hostOwnedObjectMeta(host, host.Name, host.Namespace),
 	}
 
-	op, err := ctrl.CreateOrUpdate(ctx, r.Client, internalHost, func() error {
-		if internalHost.CreationTimestamp.IsZero() {
-			if internalHost.Annotations == nil {
-				internalHost.Annotations = make(map[string]string)
-			}
-			maps.Copy(internalHost.Annotations, host.Annotations)
-			if internalHost.Labels == nil {
-				internalHost.Labels = make(map[string]string)
-			}
-			maps.Copy(internalHost.Labels, host.Labels)
-
-			internalHost.Labels["app.kubernetes.io/name"] = kdexWeb
-			internalHost.Labels["kdex.dev/instance"] = host.Name
-		}
-
-		internalHost.Labels["kdex.dev/generation"] = fmt.Sprintf("%d", host.Generation)
-		internalHost.Spec.KDexHostSpec = host.Spec
-		internalHost.Spec.AnnouncementRef = announcementRef
-		internalHost.Spec.ErrorRef = errorRef
-		internalHost.Spec.LoginRef = loginRef
-		internalHost.Spec.InternalTranslationRefs = translationRefs
+	internalHost.Labels["kdex.dev/generation"] = fmt.Sprintf("%d", host.Generation)
+	if certificateSecret != "" {
+		internalHost.Annotations[tlsSecretAnnotation] = certificateSecret
+	}
+	maps.Copy(internalHost.Annotations, maintenanceAnnotations)
+	internalHost.Spec.KDexHostSpec = host.Spec
+	internalHost.Spec.AnnouncementRef = utilityPageRefs[kdexv1alpha1.AnnouncementUtilityPageType]
+	internalHost.Spec.ErrorRef = utilityPageRefs[kdexv1alpha1.ErrorUtilityPageType]
+	internalHost.Spec.LoginRef = utilityPageRefs[kdexv1alpha1.LoginUtilityPageType]
+	internalHost.Spec.InternalTranslationRefs = translationRefs
+	if faasAdaptor != nil {
+		internalHost.Spec.FaaSAdaptorRef = faasAdaptorRef(faasAdaptor)
+		internalHost.Annotations[faasAdaptorGenerationAnnotation] = fmt.Sprintf("%d", faasAdaptor.GetGeneration())
+	}
 
-		return ctrl.SetControllerReference(host, internalHost, r.Scheme)
-	})
+	pageAnnotations, err := utilityPageAnnotations(host, utilityPageRefs)
+	if err == nil {
+		maps.Copy(internalHost.Annotations, pageAnnotations)
+		err = ctrl.SetControllerReference(host, internalHost, r.Scheme)
+	}
+	op := controllerutil.OperationResultNone
+	if err == nil {
+		op, err = r.applyOwned(ctx, host, internalHost)
+	}
 
 	log := logf.FromContext(ctx)
 
@@ -694,10 +885,11 @@ func (r *KDexHostReconciler) createOrUpdateInternalHostResource(
 		"createOrUpdateInternalHostResource",
 		"name", internalHost.Name,
 		"op", op,
-		"announcementRef", announcementRef,
-		"errorRef", errorRef,
-		"loginRef", loginRef,
+		"utilityPageRefs", utilityPageRefs,
 		"translationRefs", translationRefs,
+		"faasAdaptorRef", internalHost.Spec.FaaSAdaptorRef,
+		"certificateSecret", certificateSecret,
+		"maintenanceAnnotations", maintenanceAnnotations,
 		"err", err,
 	)
 
@@ -723,103 +915,130 @@ func (r *KDexHostReconciler) createOrUpdateDeployment(
 	ctx context.Context,
 	host *kdexv1alpha1.KDexHost,
 ) (controllerutil.OperationResult, *appsv1.Deployment, error) {
+	configString, err := r.getMemoizedConfiguration()
+	if err != nil {
+		return controllerutil.OperationResultNone, nil, err
+	}
+
+	objectMeta, members, err := r.servingObjectMeta(ctx, host)
+	if err != nil {
+		return controllerutil.OperationResultNone, nil, err
+	}
+	instance := objectMeta.Name
+
+	// The HostDefault template is rendered in full on every reconcile so that changes to it reach existing hosts.
+	// Host specific overrides are layered on top of it below and therefore always win.
 	deployment := &appsv1.Deployment{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      host.Name,
-			Namespace: host.Namespace,
-		},
+		ObjectMeta: objectMeta,
+		Spec:       *r.getMemoizedDeployment().DeepCopy(),
 	}
 
-	op, err := ctrl.CreateOrUpdate(
-		ctx,
-		r.Client,
-		deployment,
-		func() error {
-			if deployment.CreationTimestamp.IsZero() {
-				if deployment.Annotations == nil {
-					deployment.Annotations = make(map[string]string)
-				}
-				maps.Copy(deployment.Annotations, host.Annotations)
-				if deployment.Labels == nil {
-					deployment.Labels = make(map[string]string)
-				}
-				maps.Copy(deployment.Labels, host.Labels)
+	if deployment.Spec.Selector == nil {
+		deployment.Spec.Selector = &metav1.LabelSelector{}
+	}
+	if deployment.Spec.Selector.MatchLabels == nil {
+		deployment.Spec.Selector.MatchLabels = make(map[string]string)
+	}
+	deployment.Spec.Selector.MatchLabels["app.kubernetes.io/name"] = kdexWeb
+	deployment.Spec.Selector.MatchLabels["kdex.dev/instance"] = instance
 
-				deployment.Labels["app.kubernetes.io/name"] = kdexWeb
-				deployment.Labels["kdex.dev/instance"] = host.Name
+	if deployment.Spec.Template.Labels == nil {
+		deployment.Spec.Template.Labels = make(map[string]string)
+	}
+	deployment.Spec.Template.Labels["app.kubernetes.io/name"] = kdexWeb
+	deployment.Spec.Template.Labels["kdex.dev/instance"] = instance
 
-				deployment.Spec = *r.getMemoizedDeployment().DeepCopy()
+	// Pods mount config.yaml through a subPath which is never refreshed, so a change to the generated configuration
+	// must roll the pods.
+	if deployment.Spec.Template.Annotations == nil {
+		deployment.Spec.Template.Annotations = make(map[string]string)
+	}
+	deployment.Spec.Template.Annotations[configChecksumKey] = fmt.Sprintf("%x", sha256.Sum256([]byte(configString)))
+
+	// A pool Deployment serves every member of the pool, each named by a --focal-host argument.
+	container := &deployment.Spec.Template.Spec.Containers[0]
+	var foundFocalHost, foundServiceName, found bool
+	container.Args, foundFocalHost = setFlag(container.Args, "--focal-host", focalHosts(host, members))
+	container.Command, found = setFlag(container.Command, "--focal-host", focalHosts(host, members))
+	foundFocalHost = foundFocalHost || found
+	container.Args, foundServiceName = setFlag(container.Args, "--service-name", []string{instance})
+	container.Command, found = setFlag(container.Command, "--service-name", []string{instance})
+	foundServiceName = foundServiceName || found
+	if !foundFocalHost {
+		for _, focalHost := range focalHosts(host, members) {
+			container.Args = append(container.Args, "--focal-host="+focalHost)
+		}
+	}
+	if !foundServiceName {
+		container.Args = append(container.Args, "--service-name="+instance)
+	}
 
-				if deployment.Spec.Selector == nil {
-					deployment.Spec.Selector = &metav1.LabelSelector{
-						MatchLabels: map[string]string{},
-					}
-				}
-				deployment.Spec.Selector.MatchLabels["app.kubernetes.io/name"] = kdexWeb
-				deployment.Spec.Selector.MatchLabels["kdex.dev/instance"] = host.Name
+	container.Name = instance
+	deployment.Spec.Template.Spec.ServiceAccountName = instance
 
-				if deployment.Spec.Template.Labels == nil {
-					deployment.Spec.Template.Labels = make(map[string]string)
-				}
-				deployment.Spec.Template.Labels["app.kubernetes.io/name"] = kdexWeb
-				deployment.Spec.Template.Labels["kdex.dev/instance"] = host.Name
+	for idx, volume := range deployment.Spec.Template.Spec.Volumes {
+		if volume.Name == "config" {
+			deployment.Spec.Template.Spec.Volumes[idx].ConfigMap.Name = instance
+		}
+	}
 
-				deployment.Spec.Template.Spec = *r.getMemoizedDeployment().Template.Spec.DeepCopy()
-			}
+	// The members of a pool share its Deployment, which therefore takes none of their overrides.
+	if members != nil {
+		op := controllerutil.OperationResultNone
+		err = r.setServingOwners(host, members, deployment)
+		if err == nil {
+			op, err = r.applyOwned(ctx, host, deployment)
+		}
 
-			foundFocalHost := false
-			foundServiceName := false
-			for idx, value := range deployment.Spec.Template.Spec.Containers[0].Args {
-				if strings.Contains(value, "--focal-host") {
-					deployment.Spec.Template.Spec.Containers[0].Args[idx] = "--focal-host=" + host.Name
-					foundFocalHost = true
-				}
-				if strings.Contains(value, "--service-name") {
-					deployment.Spec.Template.Spec.Containers[0].Args[idx] = "--service-name=" + host.Name
-					foundServiceName = true
-				}
-			}
-			for idx, value := range deployment.Spec.Template.Spec.Containers[0].Command {
-				if strings.Contains(value, "--focal-host") {
-					deployment.Spec.Template.Spec.Containers[0].Command[idx] = "--focal-host=" + host.Name
-					foundFocalHost = true
-				}
-				if strings.Contains(value, "--service-name") {
-					deployment.Spec.Template.Spec.Containers[0].Command[idx] = "--service-name=" + host.Name
-					foundServiceName = true
-				}
-			}
-			if !foundFocalHost {
-				deployment.Spec.Template.Spec.Containers[0].Args = append(deployment.Spec.Template.Spec.Containers[0].Args, "--focal-host="+host.Name)
-			}
-			if !foundServiceName {
-				deployment.Spec.Template.Spec.Containers[0].Args = append(deployment.Spec.Template.Spec.Containers[0].Args, "--service-name="+host.Name)
-			}
+		logf.FromContext(ctx).V(2).Info(
+			"createOrUpdateDeployment",
+			"name", deployment.Name,
+			"pool", hostoptions.GetPool(host.Annotations),
+			"members", len(members),
+			"op", op,
+			"err", err,
+		)
 
-			deployment.Spec.Template.Spec.Containers[0].Name = host.Name
-			deployment.Spec.Template.Spec.ServiceAccountName = host.Name
+		return op, deployment, err
+	}
 
-			for idx, volume := range deployment.Spec.Template.Spec.Volumes {
-				if volume.Name == "config" {
-					deployment.Spec.Template.Spec.Volumes[idx].ConfigMap.Name = host.Name
-				}
-			}
+	if len(host.Spec.Env) > 0 {
+		container.Env = MergeEnvVars(container.Env, host.Spec.Env)
+	}
 
-			if len(host.Spec.Env) > 0 {
-				deployment.Spec.Template.Spec.Containers[0].Env = MergeEnvVars(deployment.Spec.Template.Spec.Containers[0].Env, host.Spec.Env)
-			}
+	if host.Spec.Resources.Size() > 0 {
+		container.Resources = host.Spec.Resources
+	}
 
-			if host.Spec.Resources.Size() > 0 {
-				deployment.Spec.Template.Spec.Containers[0].Resources = host.Spec.Resources
-			}
+	if host.Spec.Replicas != nil {
+		deployment.Spec.Replicas = host.Spec.Replicas
+	}
 
-			if host.Spec.Replicas != nil {
-				deployment.Spec.Replicas = host.Spec.Replicas
-			}
+	// Leave replicas out of the applied configuration while autoscaling so the HorizontalPodAutoscaler owns it.
+	autoscaling, err := hostoptions.GetAutoscaling(host.Annotations)
+	if err != nil {
+		return controllerutil.OperationResultNone, nil, err
+	}
+	if autoscaling != nil {
+		deployment.Spec.Replicas, err = r.handOffReplicas(ctx, deployment, *autoscaling.MinReplicas)
+		if err != nil {
+			return controllerutil.OperationResultNone, nil, err
+		}
+	}
 
-			return ctrl.SetControllerReference(host, deployment, r.Scheme)
-		},
-	)
+	// The host's deployment patch goes last so it can override anything but the fields the operator relies on.
+	generated := deployment.DeepCopy()
+	err = patch.ApplyToDeployment(deployment, host.Annotations)
+	if err == nil {
+		err = patch.VerifyOwnedFields(generated, deployment)
+	}
+	if err == nil {
+		err = ctrl.SetControllerReference(host, deployment, r.Scheme)
+	}
+	op := controllerutil.OperationResultNone
+	if err == nil {
+		op, err = r.applyOwned(ctx, host, deployment)
+	}
 
 	log := logf.FromContext(ctx)
 
@@ -848,54 +1067,36 @@ func (r *KDexHostReconciler) createOrUpdateDeployment(
 	return op, deployment, nil
 }
 
-func (r *KDexHostReconciler) createOrUpdateClusterRoleBinding(
+func (r *KDexHostReconciler) createOrUpdateService(
 	ctx context.Context,
 	host *kdexv1alpha1.KDexHost,
 ) (controllerutil.OperationResult, error) {
-	clusterRoleBinding := &rbacv1.ClusterRoleBinding{
-		ObjectMeta: metav1.ObjectMeta{
-			Name: fmt.Sprintf("%s-%s", host.Name, host.Namespace),
-		},
+	objectMeta, members, err := r.servingObjectMeta(ctx, host)
+	service := &corev1.Service{
+		ObjectMeta: objectMeta,
+		Spec:       *r.getMemoizedService().DeepCopy(),
 	}
 
-	op, err := ctrl.CreateOrUpdate(
-		ctx,
-		r.Client,
-		clusterRoleBinding,
-		func() error {
-			if clusterRoleBinding.CreationTimestamp.IsZero() {
-				if clusterRoleBinding.Annotations == nil {
-					clusterRoleBinding.Annotations = make(map[string]string)
-				}
-				maps.Copy(clusterRoleBinding.Annotations, host.Annotations)
-				if clusterRoleBinding.Labels == nil {
-					clusterRoleBinding.Labels = make(map[string]string)
-				}
-				maps.Copy(clusterRoleBinding.Labels, host.Labels)
-
-				clusterRoleBinding.Labels["app.kubernetes.io/name"] = kdexWeb
-				clusterRoleBinding.Labels["kdex.dev/instance"] = host.Name
-			}
+	if service.Spec.Selector == nil {
+		service.Spec.Selector = make(map[string]string)
+	}
 
-			clusterRoleBinding.RoleRef = *r.Configuration.HostDefault.RoleRef.DeepCopy()
-			clusterRoleBinding.Subjects = []rbacv1.Subject{
-				{
-					Kind:      "ServiceAccount",
-					Name:      host.Name,
-					Namespace: host.Namespace,
-				},
-			}
+	service.Spec.Selector["app.kubernetes.io/name"] = kdexWeb
+	service.Spec.Selector["kdex.dev/instance"] = objectMeta.Name
 
-			controllerutil.AddFinalizer(clusterRoleBinding, hostFinalizerName)
-			return nil
-		},
-	)
+	if err == nil {
+		err = r.setServingOwners(host, members, service)
+	}
+	op := controllerutil.OperationResultNone
+	if err == nil {
+		op, err = r.applyOwned(ctx, host, service)
+	}
 
 	log := logf.FromContext(ctx)
 
 	log.V(2).Info(
-		"createOrUpdateClusterRoleBinding",
-		"name", clusterRoleBinding.Name,
+		"createOrUpdateService",
+		"name", service.Name,
 		"op", op,
 		"err", err,
 	)
@@ -918,54 +1119,33 @@ func (r *KDexHostReconciler) createOrUpdateClusterRoleBinding(
 	return op, nil
 }
 
-func (r *KDexHostReconciler) createOrUpdateService(
+func (r *KDexHostReconciler) createOrUpdateServiceAccount(
 	ctx context.Context,
 	host *kdexv1alpha1.KDexHost,
 ) (controllerutil.OperationResult, error) {
-	service := &corev1.Service{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      host.Name,
-			Namespace: host.Namespace,
-		},
+	objectMeta, members, err := r.servingObjectMeta(ctx, host)
+	serviceAccount := &corev1.ServiceAccount{
+		ObjectMeta: objectMeta,
 	}
 
-	op, err := ctrl.CreateOrUpdate(
-		ctx,
-		r.Client,
-		service,
-		func() error {
-			if service.CreationTimestamp.IsZero() {
-				if service.Annotations == nil {
-					service.Annotations = make(map[string]string)
-				}
-				maps.Copy(service.Annotations, host.Annotations)
-				if service.Labels == nil {
-					service.Labels = make(map[string]string)
-				}
-				maps.Copy(service.Labels, host.Labels)
-
-				service.Labels["app.kubernetes.io/name"] = kdexWeb
-				service.Labels["kdex.dev/instance"] = host.Name
-
-				service.Spec = *r.getMemoizedService().DeepCopy()
-
-				if service.Spec.Selector == nil {
-					service.Spec.Selector = make(map[string]string)
-				}
-
-				service.Spec.Selector["app.kubernetes.io/name"] = kdexWeb
-				service.Spec.Selector["kdex.dev/instance"] = host.Name
-			}
+	// The service account of a pool outlives its members but the last, so no member holds it.
+	if members == nil {
+		controllerutil.AddFinalizer(serviceAccount, hostFinalizerName)
+	}
 
-			return ctrl.SetControllerReference(host, service, r.Scheme)
-		},
-	)
+	if err == nil {
+		err = r.setServingOwners(host, members, serviceAccount)
+	}
+	op := controllerutil.OperationResultNone
+	if err == nil {
+		op, err = r.applyOwned(ctx, host, serviceAccount)
+	}
 
 	log := logf.FromContext(ctx)
 
 	log.V(2).Info(
-		"createOrUpdateService",
-		"name", service.Name,
+		"createOrUpdateServiceAccount",
+		"name", serviceAccount.Name,
 		"op", op,
 		"err", err,
 	)
@@ -988,64 +1168,37 @@ func (r *KDexHostReconciler) createOrUpdateService(
 	return op, nil
 }
 
-func (r *KDexHostReconciler) createOrUpdateServiceAccount(
-	ctx context.Context,
-	host *kdexv1alpha1.KDexHost,
-) (controllerutil.OperationResult, error) {
-	serviceAccount := &corev1.ServiceAccount{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      host.Name,
-			Namespace: host.Namespace,
-		},
+// hostOwnedObjectMeta returns the metadata shared by every resource generated for the host. An empty namespace is
+// used for cluster scoped resources. Only the host annotations allowed by hostDefault.propagatedAnnotations are
+// copied, so that the options of the host and the annotations of clients such as kubectl stay on the host.
+func (r *KDexHostReconciler) hostOwnedObjectMeta(host *kdexv1alpha1.KDexHost, name string, namespace string) metav1.ObjectMeta {
+	om := metav1.ObjectMeta{
+		Annotations: propagatedAnnotations(host.Annotations, r.getExtensions().HostDefault.PropagatedAnnotations),
+		Labels:      make(map[string]string),
+		Name:        name,
+		Namespace:   namespace,
 	}
 
-	op, err := ctrl.CreateOrUpdate(
-		ctx,
-		r.Client,
-		serviceAccount,
-		func() error {
-			if serviceAccount.CreationTimestamp.IsZero() {
-				if serviceAccount.Annotations == nil {
-					serviceAccount.Annotations = make(map[string]string)
-				}
-				maps.Copy(serviceAccount.Annotations, host.Annotations)
-				if serviceAccount.Labels == nil {
-					serviceAccount.Labels = make(map[string]string)
-				}
-				maps.Copy(serviceAccount.Labels, host.Labels)
-
-				serviceAccount.Labels["app.kubernetes.io/name"] = kdexWeb
-				serviceAccount.Labels["kdex.dev/instance"] = host.Name
-			}
-
-			controllerutil.AddFinalizer(serviceAccount, hostFinalizerName)
-			return ctrl.SetControllerReference(host, serviceAccount, r.Scheme)
-		},
-	)
+	maps.Copy(om.Labels, host.Labels)
 
-	log := logf.FromContext(ctx)
+	om.Labels["app.kubernetes.io/name"] = kdexWeb
+	om.Labels["kdex.dev/instance"] = host.Name
 
-	log.V(2).Info(
-		"createOrUpdateServiceAccount",
-		"name", serviceAccount.Name,
-		"op", op,
-		"err", err,
-	)
+	return om
+}
 
-	if err != nil {
-		kdexv1alpha1.SetConditions(
-			&host.Status.Conditions,
-			kdexv1alpha1.ConditionStatuses{
-				Degraded:    metav1.ConditionTrue,
-				Progressing: metav1.ConditionFalse,
-				Ready:       metav1.ConditionFalse,
-			},
-			kdexv1alpha1.ConditionReasonReconcileError,
-			err.Error(),
-		)
+// propagatedAnnotations returns the annotations whose key is listed in allowed, or starts with an entry of allowed
+// ending in "/".
+func propagatedAnnotations(annotations map[string]string, allowed []string) map[string]string {
+	propagated := make(map[string]string)
 
-		return controllerutil.OperationResultNone, err
+	for key, value := range annotations {
+		if slices.ContainsFunc(allowed, func(entry string) bool {
+			return key == entry || (strings.HasSuffix(entry, "/") && strings.HasPrefix(key, entry))
+		}) {
+			propagated[key] = value
+		}
 	}
 
-	return op, nil
+	return propagated
 }
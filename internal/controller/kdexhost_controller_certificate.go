@@ -0,0 +1,316 @@
+package controller
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/kdex-tech/nexus-manager/internal/certificate"
+	"github.com/kdex-tech/nexus-manager/internal/extensions"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	kdexv1alpha1 "kdex.dev/crds/api/v1alpha1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// tlsSecretAnnotation names, on the KDexInternalHost, the kubernetes.io/tls Secret holding the host certificate.
+	tlsSecretAnnotation = "kdex.dev/tls-secret"
+
+	certificateIssuerAttribute   = "certificate.issuer"
+	certificateNotAfterAttribute = "certificate.notAfter"
+	certificateReadyAttribute    = "certificate.ready"
+	certificateSecretAttribute   = "certificate.secret"
+)
+
+var certificateGVK = schema.GroupVersionKind{Group: "cert-manager.io", Version: "v1", Kind: "Certificate"}
+
+// createOrUpdateCertificate provisions the TLS certificate of a host served over https and records its state in the
+// host status. It returns the name of the certificate Secret, empty when there is none, and the delay after which the
+// host must be reconciled again to follow the certificate's progress or rotate it.
+func (r *KDexHostReconciler) createOrUpdateCertificate(
+	ctx context.Context,
+	host *kdexv1alpha1.KDexHost,
+) (controllerutil.OperationResult, string, time.Duration, error) {
+	log := logf.FromContext(ctx)
+
+	settings := r.getExtensions().HostDefault.Certificate.WithDefaults()
+	secretName := host.Name + "-tls"
+
+	op := controllerutil.OperationResultNone
+	var requeueAfter time.Duration
+	var err error
+
+	switch {
+	case settings.Disabled || host.Spec.Routing.Scheme != "https":
+		secretName = ""
+		err = r.deleteCertificateResources(ctx, host, true, true)
+	case settings.IssuerRef != nil:
+		err = r.deleteCertificateResources(ctx, host, true, false)
+		if err == nil {
+			op, requeueAfter, err = r.applyCertificateResource(ctx, host, secretName, settings)
+		}
+	default:
+		err = r.deleteCertificateResources(ctx, host, false, true)
+		if err == nil {
+			op, requeueAfter, err = r.applyCertificateSecret(ctx, host, secretName, settings)
+		}
+	}
+
+	log.V(2).Info(
+		"createOrUpdateCertificate",
+		"secret", secretName,
+		"op", op,
+		"requeueAfter", requeueAfter,
+		"err", err,
+	)
+
+	if err != nil {
+		kdexv1alpha1.SetConditions(
+			&host.Status.Conditions,
+			kdexv1alpha1.ConditionStatuses{
+				Degraded:    metav1.ConditionTrue,
+				Progressing: metav1.ConditionFalse,
+				Ready:       metav1.ConditionFalse,
+			},
+			kdexv1alpha1.ConditionReasonReconcileError,
+			err.Error(),
+		)
+
+		return controllerutil.OperationResultNone, "", 0, err
+	}
+
+	if secretName == "" {
+		delete(host.Status.Attributes, certificateIssuerAttribute)
+		delete(host.Status.Attributes, certificateNotAfterAttribute)
+		delete(host.Status.Attributes, certificateReadyAttribute)
+		delete(host.Status.Attributes, certificateSecretAttribute)
+	} else {
+		host.Status.Attributes[certificateSecretAttribute] = secretName
+	}
+
+	return op, secretName, requeueAfter, nil
+}
+
+// applyCertificateResource hands the certificate over to cert-manager.
+func (r *KDexHostReconciler) applyCertificateResource(
+	ctx context.Context,
+	host *kdexv1alpha1.KDexHost,
+	secretName string,
+	settings extensions.Certificate,
+) (controllerutil.OperationResult, time.Duration, error) {
+	issuerRef := map[string]any{
+		"name": settings.IssuerRef.Name,
+	}
+	if settings.IssuerRef.Group != "" {
+		issuerRef["group"] = settings.IssuerRef.Group
+	}
+	if settings.IssuerRef.Kind != "" {
+		issuerRef["kind"] = settings.IssuerRef.Kind
+	}
+
+	dnsNames := make([]any, 0, len(host.Spec.Routing.Domains))
+	for _, domain := range host.Spec.Routing.Domains {
+		dnsNames = append(dnsNames, domain)
+	}
+
+	resource := &unstructured.Unstructured{}
+	resource.SetGroupVersionKind(certificateGVK)
+	resource.SetName(host.Name)
+	resource.SetNamespace(host.Namespace)
+	resource.SetLabels(r.hostOwnedObjectMeta(host, host.Name, host.Namespace).Labels)
+	resource.Object["spec"] = map[string]any{
+		"dnsNames":    dnsNames,
+		"duration":    settings.Duration.Duration.String(),
+		"issuerRef":   issuerRef,
+		"renewBefore": settings.RenewBefore.Duration.String(),
+		"secretName":  secretName,
+	}
+
+	if err := ctrl.SetControllerReference(host, resource, r.Scheme); err != nil {
+		return controllerutil.OperationResultNone, 0, err
+	}
+
+	previous := &unstructured.Unstructured{}
+	previous.SetGroupVersionKind(certificateGVK)
+	exists := true
+	if err := r.Get(ctx, client.ObjectKeyFromObject(resource), previous); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			return controllerutil.OperationResultNone, 0, err
+		}
+		exists = false
+	}
+
+	if err := r.Apply(
+		ctx,
+		client.ApplyConfigurationFromUnstructured(resource),
+		client.FieldOwner(fieldManager),
+		client.ForceOwnership,
+	); err != nil {
+		return controllerutil.OperationResultNone, 0, fmt.Errorf("failed to apply cert-manager Certificate: %w", err)
+	}
+
+	op := controllerutil.OperationResultUpdated
+	if !exists {
+		op = controllerutil.OperationResultCreated
+	} else if previous.GetResourceVersion() == resource.GetResourceVersion() {
+		op = controllerutil.OperationResultNone
+	}
+
+	host.Status.Attributes[certificateIssuerAttribute] = settings.IssuerRef.Name
+
+	ready := false
+	conditions, _, _ := unstructured.NestedSlice(resource.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, _ := c.(map[string]any)
+		if condition["type"] == "Ready" && condition["status"] == string(metav1.ConditionTrue) {
+			ready = true
+		}
+	}
+	host.Status.Attributes[certificateReadyAttribute] = strconv.FormatBool(ready)
+
+	if notAfter, found, _ := unstructured.NestedString(resource.Object, "status", "notAfter"); found {
+		host.Status.Attributes[certificateNotAfterAttribute] = notAfter
+	} else {
+		delete(host.Status.Attributes, certificateNotAfterAttribute)
+	}
+
+	// cert-manager is not watched, so its progress and renewals are followed by polling.
+	if !ready {
+		return op, r.RequeueDelay, nil
+	}
+
+	if renewalTime, found, _ := unstructured.NestedString(resource.Object, "status", "renewalTime"); found {
+		if at, err := time.Parse(time.RFC3339, renewalTime); err == nil {
+			return op, requeueUntil(at), nil
+		}
+	}
+
+	return op, 0, nil
+}
+
+// applyCertificateSecret issues the certificate in place of cert-manager, renewing it when it enters its renewal
+// window, no longer covers the host domains or was signed by another CA.
+func (r *KDexHostReconciler) applyCertificateSecret(
+	ctx context.Context,
+	host *kdexv1alpha1.KDexHost,
+	secretName string,
+	settings extensions.Certificate,
+) (controllerutil.OperationResult, time.Duration, error) {
+	var ca *certificate.Authority
+	if settings.CASecretRef != nil {
+		caSecret := &corev1.Secret{}
+		if err := r.Get(ctx, client.ObjectKey{Name: settings.CASecretRef.Name, Namespace: settings.CASecretRef.Namespace}, caSecret); err != nil {
+			return controllerutil.OperationResultNone, 0, fmt.Errorf("failed to get CA secret: %w", err)
+		}
+
+		var err error
+		ca, err = certificate.ParseAuthority(caSecret.Data[corev1.TLSCertKey], caSecret.Data[corev1.TLSPrivateKeyKey])
+		if err != nil {
+			return controllerutil.OperationResultNone, 0, fmt.Errorf("CA secret %s/%s: %w", caSecret.Namespace, caSecret.Name, err)
+		}
+	}
+
+	now := time.Now()
+	domains := host.Spec.Routing.Domains
+
+	secret := &corev1.Secret{
+		ObjectMeta: r.hostOwnedObjectMeta(host, secretName, host.Namespace),
+		Type:       corev1.SecretTypeTLS,
+	}
+
+	existing := &corev1.Secret{}
+	err := r.Get(ctx, client.ObjectKeyFromObject(secret), existing)
+	if client.IgnoreNotFound(err) != nil {
+		return controllerutil.OperationResultNone, 0, err
+	}
+
+	var cert *x509.Certificate
+	if err == nil && metav1.IsControlledBy(existing, host) {
+		if parsed, err := certificate.Parse(existing.Data[corev1.TLSCertKey]); err == nil &&
+			!certificate.NeedsRenewal(parsed, domains, ca, settings.RenewBefore.Duration, now) {
+			secret.Data = existing.Data
+			cert = parsed
+		}
+	}
+
+	if cert == nil {
+		certPEM, keyPEM, err := certificate.Issue(domains, settings.Duration.Duration, ca, now)
+		if err != nil {
+			return controllerutil.OperationResultNone, 0, err
+		}
+		if cert, err = certificate.Parse(certPEM); err != nil {
+			return controllerutil.OperationResultNone, 0, err
+		}
+
+		secret.Data = map[string][]byte{
+			corev1.TLSCertKey:       certPEM,
+			corev1.TLSPrivateKeyKey: keyPEM,
+		}
+		if ca != nil {
+			secret.Data["ca.crt"] = ca.CertPEM
+		}
+	}
+
+	op := controllerutil.OperationResultNone
+	err = ctrl.SetControllerReference(host, secret, r.Scheme)
+	if err == nil {
+		op, err = r.applyOwned(ctx, host, secret)
+	}
+	if err != nil {
+		return controllerutil.OperationResultNone, 0, err
+	}
+
+	host.Status.Attributes[certificateReadyAttribute] = strconv.FormatBool(now.Before(cert.NotAfter))
+	host.Status.Attributes[certificateNotAfterAttribute] = cert.NotAfter.UTC().Format(time.RFC3339)
+
+	return op, requeueUntil(certificate.RenewalTime(cert, settings.RenewBefore.Duration)), nil
+}
+
+// deleteCertificateResources deletes the certificate Secret issued by the manager and the cert-manager Certificate, as
+// selected, when the host owns them. The Certificate is only looked up when the host status says one was created, so
+// that clusters without cert-manager are not queried for it.
+func (r *KDexHostReconciler) deleteCertificateResources(
+	ctx context.Context,
+	host *kdexv1alpha1.KDexHost,
+	secret bool,
+	resource bool,
+) error {
+	if secret {
+		if _, err := r.deleteOwned(ctx, host, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: host.Name + "-tls", Namespace: host.Namespace},
+		}); err != nil {
+			return err
+		}
+	}
+
+	if resource && host.Status.Attributes[certificateIssuerAttribute] != "" {
+		certificateResource := &unstructured.Unstructured{}
+		certificateResource.SetGroupVersionKind(certificateGVK)
+		certificateResource.SetName(host.Name)
+		certificateResource.SetNamespace(host.Namespace)
+
+		// Without cert-manager installed there is nothing to delete.
+		if _, err := r.deleteOwned(ctx, host, certificateResource); err != nil && !meta.IsNoMatchError(err) {
+			return err
+		}
+
+		delete(host.Status.Attributes, certificateIssuerAttribute)
+	}
+
+	return nil
+}
+
+// requeueUntil returns the delay until at, never less than a minute so that a certificate which cannot be renewed
+// does not cause a busy loop.
+func requeueUntil(at time.Time) time.Duration {
+	return max(time.Until(at), time.Minute)
+}
@@ -0,0 +1,249 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kdex-tech/nexus-manager/internal/hostoptions"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kdexv1alpha1 "kdex.dev/crds/api/v1alpha1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	ConditionTypeTerminating kdexv1alpha1.ConditionType = "Terminating"
+
+	ConditionReasonDeletionTimedOut kdexv1alpha1.ConditionReason = "DeletionTimedOut"
+	ConditionReasonFinalized        kdexv1alpha1.ConditionReason = "Finalized"
+	ConditionReasonForcingDeletion  kdexv1alpha1.ConditionReason = "ForcingDeletion"
+
+	// deletionPollInterval bounds the wait between two checks of a deletion stage, in case the deletion of a
+	// downstream object is not observed.
+	deletionPollInterval = 10 * time.Second
+)
+
+// deletionStage is a set of downstream objects which must be gone before the host deletion moves on.
+type deletionStage struct {
+	name    string
+	pending func(ctx context.Context) ([]client.Object, error)
+}
+
+// finalizeHost deletes the downstream objects of a host being deleted, one stage at a time, and releases the host
+// finalizer once they are all gone. The Terminating condition names the current stage and the objects it waits for.
+// Past the deletion timeout the finalizers of those objects are removed, but only when the host carries the
+// force-delete annotation; each removal is recorded as an Event.
+func (r *KDexHostReconciler) finalizeHost(ctx context.Context, host *kdexv1alpha1.KDexHost) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	if !controllerutil.ContainsFinalizer(host, hostFinalizerName) {
+		return ctrl.Result{}, nil
+	}
+
+	timeout := r.getExtensions().HostDefault.Deletion.WithDefaults().Timeout.Duration
+	remaining := time.Until(host.DeletionTimestamp.Add(timeout))
+	force := remaining <= 0 && hostoptions.IsForceDelete(host.Annotations)
+
+	for _, stage := range r.deletionStages(host) {
+		pending, err := stage.pending(ctx)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if len(pending) == 0 {
+			continue
+		}
+
+		names := make([]string, 0, len(pending))
+		for _, obj := range pending {
+			if obj.GetDeletionTimestamp().IsZero() {
+				if err := r.Delete(ctx, obj); client.IgnoreNotFound(err) != nil {
+					return ctrl.Result{}, err
+				}
+			}
+
+			if force {
+				if err := r.forceRemoveFinalizers(ctx, host, obj, timeout); err != nil {
+					return ctrl.Result{}, err
+				}
+			}
+
+			names = append(names, obj.GetName())
+		}
+
+		reason := kdexv1alpha1.ConditionReason("Deleting" + stage.name)
+		message := fmt.Sprintf("Waiting for %s to be deleted: %s", stage.name, strings.Join(names, ", "))
+
+		eventType := corev1.EventTypeNormal
+		switch {
+		case force:
+			reason = ConditionReasonForcingDeletion
+			message = fmt.Sprintf("Deletion timed out after %s; removed the finalizers of %s: %s",
+				timeout, stage.name, strings.Join(names, ", "))
+			eventType = corev1.EventTypeWarning
+		case remaining <= 0:
+			reason = ConditionReasonDeletionTimedOut
+			message = fmt.Sprintf("Deletion timed out after %s waiting for %s: %s; annotate the host with %s=true to remove their finalizers",
+				timeout, stage.name, strings.Join(names, ", "), hostoptions.ForceDeleteAnnotation)
+			eventType = corev1.EventTypeWarning
+		}
+
+		if setTerminatingCondition(host, reason, message) {
+			r.Recorder.Eventf(host, nil, eventType, string(reason), eventActionDelete, "%s", message)
+		}
+
+		log.V(2).Info("finalizeHost", "stage", stage.name, "pending", names, "remaining", remaining, "force", force)
+
+		requeueAfter := deletionPollInterval
+		if remaining > 0 {
+			requeueAfter = min(requeueAfter, remaining)
+		}
+
+		return ctrl.Result{RequeueAfter: requeueAfter}, nil
+	}
+
+	if err := r.cleanupRbacFinalizers(ctx, host); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if setTerminatingCondition(host, ConditionReasonFinalized, "All downstream objects are deleted") {
+		r.Recorder.Eventf(host, nil, corev1.EventTypeNormal, string(ConditionReasonFinalized), eventActionDelete,
+			"All downstream objects are deleted")
+	}
+
+	controllerutil.RemoveFinalizer(host, hostFinalizerName)
+	if err := r.Update(ctx, host); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// deletionStages returns the stages of the host deletion in the order they are carried out.
+func (r *KDexHostReconciler) deletionStages(host *kdexv1alpha1.KDexHost) []deletionStage {
+	return []deletionStage{
+		{
+			name: "KDexInternalHost",
+			pending: func(ctx context.Context) ([]client.Object, error) {
+				return r.pendingObject(ctx, &kdexv1alpha1.KDexInternalHost{}, host)
+			},
+		},
+		{
+			name: "KDexInternalUtilityPages",
+			pending: func(ctx context.Context) ([]client.Object, error) {
+				return r.pendingObjects(ctx, &kdexv1alpha1.KDexInternalUtilityPageList{}, host)
+			},
+		},
+		{
+			name: "KDexInternalTranslations",
+			pending: func(ctx context.Context) ([]client.Object, error) {
+				return r.pendingObjects(ctx, &kdexv1alpha1.KDexInternalTranslationList{}, host)
+			},
+		},
+		{
+			name: "Deployment",
+			pending: func(ctx context.Context) ([]client.Object, error) {
+				return r.pendingObject(ctx, &appsv1.Deployment{}, host)
+			},
+		},
+	}
+}
+
+// pendingObject returns obj, named after the host, when it still exists.
+func (r *KDexHostReconciler) pendingObject(
+	ctx context.Context,
+	obj client.Object,
+	host *kdexv1alpha1.KDexHost,
+) ([]client.Object, error) {
+	if err := r.Get(ctx, client.ObjectKeyFromObject(host), obj); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return []client.Object{obj}, nil
+}
+
+// pendingObjects returns the objects of the list's kind which still reference the host.
+func (r *KDexHostReconciler) pendingObjects(
+	ctx context.Context,
+	list client.ObjectList,
+	host *kdexv1alpha1.KDexHost,
+) ([]client.Object, error) {
+	if err := r.List(ctx, list, client.InNamespace(host.Namespace), client.MatchingFields{hostIndexKey: host.Name}); err != nil {
+		return nil, err
+	}
+
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make([]client.Object, 0, len(items))
+	for _, item := range items {
+		if obj, ok := item.(client.Object); ok {
+			objects = append(objects, obj)
+		}
+	}
+
+	return objects, nil
+}
+
+// forceRemoveFinalizers strips every finalizer from obj, a downstream object of the host which outlived the deletion
+// timeout, and records the removal as an Event on the host.
+func (r *KDexHostReconciler) forceRemoveFinalizers(
+	ctx context.Context,
+	host *kdexv1alpha1.KDexHost,
+	obj client.Object,
+	timeout time.Duration,
+) error {
+	finalizers := obj.GetFinalizers()
+	if len(finalizers) == 0 {
+		return nil
+	}
+
+	kind := fmt.Sprintf("%T", obj)
+	if gvk, err := apiutil.GVKForObject(obj, r.Scheme); err == nil {
+		kind = gvk.Kind
+	}
+
+	base := obj.DeepCopyObject().(client.Object)
+	obj.SetFinalizers(nil)
+	if err := r.Patch(ctx, obj, client.MergeFrom(base)); client.IgnoreNotFound(err) != nil {
+		return err
+	}
+
+	r.Recorder.Eventf(
+		host, obj, corev1.EventTypeWarning, string(ConditionReasonForcingDeletion), eventActionDelete,
+		"Removed finalizers %s from %s %s/%s after the deletion timeout of %s",
+		strings.Join(finalizers, ", "), kind, obj.GetNamespace(), obj.GetName(), timeout,
+	)
+
+	return nil
+}
+
+// setTerminatingCondition sets the Terminating condition of the host and reports whether its reason or message
+// changed.
+func setTerminatingCondition(host *kdexv1alpha1.KDexHost, reason kdexv1alpha1.ConditionReason, message string) bool {
+	previous := meta.FindStatusCondition(host.Status.Conditions, string(ConditionTypeTerminating))
+	changed := previous == nil || previous.Reason != string(reason) || previous.Message != message
+
+	meta.SetStatusCondition(&host.Status.Conditions, metav1.Condition{
+		Message:            message,
+		ObservedGeneration: host.Generation,
+		Reason:             string(reason),
+		Status:             metav1.ConditionTrue,
+		Type:               string(ConditionTypeTerminating),
+	})
+
+	return changed
+}
@@ -0,0 +1,68 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kdex-tech/nexus-manager/internal/diagnostics"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kdexv1alpha1 "kdex.dev/crds/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	deploymentImageAttribute    = "deployment.image"
+	deploymentReasonAttribute   = "deployment.reason"
+	deploymentReplicasAttribute = "deployment.replicas"
+)
+
+// diagnoseDeployment inspects the host Deployment and records the current image, the ready and desired replica counts
+// and, when something is wrong, the reason in the host status. The ReplicaSets and pods of the Deployment are only
+// inspected to explain a Deployment which is not available or lacks ready replicas.
+func (r *KDexHostReconciler) diagnoseDeployment(
+	ctx context.Context,
+	host *kdexv1alpha1.KDexHost,
+	deployment *appsv1.Deployment,
+) (diagnostics.Diagnosis, error) {
+	log := logf.FromContext(ctx)
+
+	diagnosis := diagnostics.Diagnose(deployment, nil, nil)
+
+	if !diagnosis.Available || diagnosis.ReadyReplicas < diagnosis.DesiredReplicas {
+		replicaSets := &appsv1.ReplicaSetList{}
+		pods := &corev1.PodList{}
+
+		selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+		if err == nil {
+			err = r.List(ctx, replicaSets, client.InNamespace(deployment.Namespace), client.MatchingLabelsSelector{Selector: selector})
+		}
+		if err == nil {
+			err = r.List(ctx, pods, client.InNamespace(deployment.Namespace), client.MatchingLabelsSelector{Selector: selector})
+		}
+		if err != nil {
+			return diagnostics.Diagnosis{}, fmt.Errorf("failed to diagnose deployment %s/%s: %w", deployment.Namespace, deployment.Name, err)
+		}
+
+		diagnosis = diagnostics.Diagnose(deployment, replicaSets.Items, pods.Items)
+	}
+
+	log.V(2).Info(
+		"diagnoseDeployment",
+		"available", diagnosis.Available,
+		"reason", diagnosis.Reason,
+		"message", diagnosis.Message,
+	)
+
+	host.Status.Attributes[deploymentImageAttribute] = diagnosis.Image
+	host.Status.Attributes[deploymentReplicasAttribute] = fmt.Sprintf("%d/%d", diagnosis.ReadyReplicas, diagnosis.DesiredReplicas)
+	if diagnosis.Reason != "" {
+		host.Status.Attributes[deploymentReasonAttribute] = diagnosis.Reason
+	} else {
+		delete(host.Status.Attributes, deploymentReasonAttribute)
+	}
+
+	return diagnosis, nil
+}
@@ -0,0 +1,61 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/kdex-tech/nexus-manager/internal/webhook"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kdexv1alpha1 "kdex.dev/crds/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// faasAdaptorGenerationAnnotation records, on the KDexInternalHost, the generation of the adaptor through which the
+	// functions of the host are deployed, so that a change of the adaptor rolls out to them.
+	faasAdaptorGenerationAnnotation = "kdex.dev/faas-adaptor-generation"
+
+	faasAdaptorGenerationAttribute = "faasAdaptor.generation"
+)
+
+// setFaaSAdaptorConditions sets the conditions of a host whose adaptor could not be used. A missing adaptor is
+// already reported by ResolveKDexObjectReference, while an adaptor which is not ready is only reported on the adaptor.
+func setFaaSAdaptorConditions(host *kdexv1alpha1.KDexHost, faasAdaptor client.Object, err error) {
+	switch {
+	case err != nil:
+		kdexv1alpha1.SetConditions(
+			&host.Status.Conditions,
+			kdexv1alpha1.ConditionStatuses{
+				Degraded:    metav1.ConditionTrue,
+				Progressing: metav1.ConditionFalse,
+				Ready:       metav1.ConditionFalse,
+			},
+			kdexv1alpha1.ConditionReasonReconcileError,
+			err.Error(),
+		)
+	case faasAdaptor != nil:
+		ref := faasAdaptorRef(faasAdaptor)
+		kdexv1alpha1.SetConditions(
+			&host.Status.Conditions,
+			kdexv1alpha1.ConditionStatuses{
+				Degraded:    metav1.ConditionFalse,
+				Progressing: metav1.ConditionTrue,
+				Ready:       metav1.ConditionFalse,
+			},
+			kdexv1alpha1.ConditionReasonReconciling,
+			fmt.Sprintf("Waiting for %s %s to be ready.", ref.Kind, ref.Name),
+		)
+	}
+}
+
+// faasAdaptorRef returns the reference of a resolved adaptor, qualified with its namespace when it is namespaced, so
+// that the internal host and the functions of the host find the same adaptor.
+func faasAdaptorRef(faasAdaptor client.Object) *kdexv1alpha1.KDexObjectReference {
+	if _, ok := faasAdaptor.(*kdexv1alpha1.KDexClusterFaaSAdaptor); ok {
+		return &kdexv1alpha1.KDexObjectReference{Kind: webhook.KDexClusterFaaSAdaptor, Name: faasAdaptor.GetName()}
+	}
+	return &kdexv1alpha1.KDexObjectReference{
+		Kind:      webhook.KDexFaaSAdaptor,
+		Name:      faasAdaptor.GetName(),
+		Namespace: faasAdaptor.GetNamespace(),
+	}
+}
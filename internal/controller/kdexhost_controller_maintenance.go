@@ -0,0 +1,131 @@
+package controller
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/kdex-tech/nexus-manager/internal/hostoptions"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/events"
+	kdexv1alpha1 "kdex.dev/crds/api/v1alpha1"
+)
+
+const (
+	ConditionTypeMaintenance kdexv1alpha1.ConditionType = "Maintenance"
+
+	ConditionReasonMaintenanceActive    kdexv1alpha1.ConditionReason = "MaintenanceActive"
+	ConditionReasonMaintenanceEnded     kdexv1alpha1.ConditionReason = "MaintenanceEnded"
+	ConditionReasonMaintenanceScheduled kdexv1alpha1.ConditionReason = "MaintenanceScheduled"
+
+	// The annotations of the KDexInternalHost which put it into maintenance, read by the kdex-host serving it. They
+	// are only present while the host is in maintenance, and only written when hostDefault.maintenanceMode is on:
+	//
+	//   - kdex.dev/maintenance-status: the HTTP status of every response, e.g. "503".
+	//   - kdex.dev/maintenance-retry-after: the Retry-After header, in seconds or as an HTTP date.
+	//   - kdex.dev/maintenance-page: the KDexInternalUtilityPage served as the body of every response. It is absent
+	//     when the page could not be resolved, in which case the error page of the status is served instead.
+	maintenancePageAnnotation       = "kdex.dev/maintenance-page"
+	maintenanceRetryAfterAnnotation = "kdex.dev/maintenance-retry-after"
+	maintenanceStatusAnnotation     = "kdex.dev/maintenance-status"
+
+	maintenanceEndedAttribute   = "maintenance.ended"
+	maintenanceStartedAttribute = "maintenance.started"
+	maintenanceUntilAttribute   = "maintenance.until"
+)
+
+// getMaintenance returns the maintenance configured on the host, or nil when there is none or maintenance mode is not
+// enabled.
+func (r *KDexHostReconciler) getMaintenance(host *kdexv1alpha1.KDexHost) (*hostoptions.Maintenance, error) {
+	if !r.getExtensions().HostDefault.MaintenanceMode {
+		return nil, nil
+	}
+
+	return hostoptions.GetMaintenance(host.Annotations)
+}
+
+// setMaintenanceStatus sets the Maintenance condition of the host at now and records the times the host entered and
+// left maintenance in its attributes, along with an Event. It returns the time left until the maintenance window
+// opens or closes, or zero when the maintenance will not change by itself.
+func setMaintenanceStatus(
+	recorder events.EventRecorder,
+	host *kdexv1alpha1.KDexHost,
+	maintenance *hostoptions.Maintenance,
+	now time.Time,
+) time.Duration {
+	previous := meta.FindStatusCondition(host.Status.Conditions, string(ConditionTypeMaintenance))
+	wasActive := previous != nil && previous.Status == metav1.ConditionTrue
+	active := maintenance.Active(now)
+	next := maintenance.NextTransition(now)
+
+	delete(host.Status.Attributes, maintenanceUntilAttribute)
+
+	condition := metav1.Condition{
+		ObservedGeneration: host.Generation,
+		Type:               string(ConditionTypeMaintenance),
+	}
+
+	switch {
+	case active:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = string(ConditionReasonMaintenanceActive)
+		condition.Message = "The host serves the maintenance page on every route"
+		if !next.IsZero() {
+			condition.Message += fmt.Sprintf(" until %s", next.UTC().Format(time.RFC3339))
+			host.Status.Attributes[maintenanceUntilAttribute] = next.UTC().Format(time.RFC3339)
+		}
+	case !next.IsZero():
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = string(ConditionReasonMaintenanceScheduled)
+		condition.Message = fmt.Sprintf("Maintenance starts at %s", next.UTC().Format(time.RFC3339))
+	case previous != nil:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = string(ConditionReasonMaintenanceEnded)
+		condition.Message = "The host is out of maintenance"
+	default:
+		return 0
+	}
+
+	meta.SetStatusCondition(&host.Status.Conditions, condition)
+
+	switch {
+	case active && !wasActive:
+		host.Status.Attributes[maintenanceStartedAttribute] = now.UTC().Format(time.RFC3339)
+		delete(host.Status.Attributes, maintenanceEndedAttribute)
+		recorder.Eventf(host, nil, corev1.EventTypeNormal, condition.Reason, eventActionReconcile, "%s", condition.Message)
+	case !active && wasActive:
+		host.Status.Attributes[maintenanceEndedAttribute] = now.UTC().Format(time.RFC3339)
+		recorder.Eventf(host, nil, corev1.EventTypeNormal, string(ConditionReasonMaintenanceEnded), eventActionReconcile,
+			"The host is out of maintenance")
+	}
+
+	if next.IsZero() {
+		return 0
+	}
+
+	return next.Sub(now)
+}
+
+// maintenanceAnnotations returns the annotations which put the internal host into maintenance, serving the internal
+// utility page pageRef, or nil when the host is not in maintenance at now.
+func maintenanceAnnotations(
+	maintenance *hostoptions.Maintenance,
+	pageRef *corev1.LocalObjectReference,
+	now time.Time,
+) map[string]string {
+	if !maintenance.Active(now) {
+		return nil
+	}
+
+	annotations := map[string]string{
+		maintenanceRetryAfterAnnotation: maintenance.RetryAfter(now),
+		maintenanceStatusAnnotation:     strconv.FormatInt(int64(maintenance.StatusCode), 10),
+	}
+	if pageRef != nil {
+		annotations[maintenancePageAnnotation] = pageRef.Name
+	}
+
+	return annotations
+}
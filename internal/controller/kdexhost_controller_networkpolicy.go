@@ -0,0 +1,224 @@
+package controller
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"regexp"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	kdexv1alpha1 "kdex.dev/crds/api/v1alpha1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// inClusterService matches the service DNS names, <service>.<namespace>.svc[.<cluster domain>], of in-cluster
+// endpoints.
+var inClusterService = regexp.MustCompile(`^[a-z0-9-]+\.([a-z0-9-]+)\.svc(\..*)?$`)
+
+func (r *KDexHostReconciler) createOrUpdateNetworkPolicy(
+	ctx context.Context,
+	host *kdexv1alpha1.KDexHost,
+) (controllerutil.OperationResult, error) {
+	log := logf.FromContext(ctx)
+
+	networkPolicy := &networkingv1.NetworkPolicy{
+		ObjectMeta: r.hostOwnedObjectMeta(host, host.Name, host.Namespace),
+	}
+
+	settings := r.getExtensions().HostDefault.NetworkPolicy.WithDefaults()
+
+	if settings.Disabled {
+		deleted, err := r.deleteOwned(ctx, host, networkPolicy)
+		log.V(2).Info("deleteNetworkPolicy", "name", networkPolicy.Name, "deleted", deleted, "err", err)
+		return controllerutil.OperationResultNone, err
+	}
+
+	ingressPeers := settings.IngressPeers
+	if host.Spec.Routing.Strategy == kdexv1alpha1.HTTPRouteRoutingStrategy {
+		ingressPeers = settings.GatewayPeers
+	}
+
+	apiServer := settings.APIServer
+	if len(apiServer) == 0 {
+		var err error
+		if apiServer, err = r.apiServerEgressRules(ctx); err != nil {
+			setDegraded(&host.Status, err)
+			return controllerutil.OperationResultNone, err
+		}
+	}
+
+	egress := []networkingv1.NetworkPolicyEgressRule{}
+	egress = append(egress, settings.DNS...)
+	egress = append(egress, apiServer...)
+	egress = append(egress, settings.Registries...)
+	egress = append(egress, settings.FaaS...)
+
+	config := r.getConfiguration()
+	for _, address := range []string{
+		orDefault(host.Spec.Registries.ImageRegistry.Host, config.DefaultImageRegistry.Host),
+		orDefault(host.Spec.Registries.NpmRegistry.Host, config.DefaultNpmRegistry.Host),
+	} {
+		if rule := inClusterEgressRule(address); rule != nil {
+			egress = append(egress, *rule)
+		}
+	}
+
+	var functions kdexv1alpha1.KDexFunctionList
+	err := r.List(ctx, &functions, client.InNamespace(host.Namespace), client.MatchingFields{hostIndexKey: host.Name})
+	if err == nil {
+		for _, function := range functions.Items {
+			if rule := inClusterEgressRule(function.Status.URL); rule != nil {
+				egress = append(egress, *rule)
+			}
+		}
+
+		networkPolicy.Spec = networkingv1.NetworkPolicySpec{
+			Egress: egress,
+			Ingress: []networkingv1.NetworkPolicyIngressRule{
+				{
+					From: append(append([]networkingv1.NetworkPolicyPeer{}, ingressPeers...), settings.ManagerPeers...),
+				},
+			},
+			PodSelector: metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"app.kubernetes.io/name": kdexWeb,
+					"kdex.dev/instance":      servingName(host),
+				},
+			},
+			PolicyTypes: []networkingv1.PolicyType{
+				networkingv1.PolicyTypeIngress,
+				networkingv1.PolicyTypeEgress,
+			},
+		}
+
+		err = ctrl.SetControllerReference(host, networkPolicy, r.Scheme)
+	}
+
+	op := controllerutil.OperationResultNone
+	if err == nil {
+		op, err = r.applyOwned(ctx, host, networkPolicy)
+	}
+
+	log.V(2).Info(
+		"createOrUpdateNetworkPolicy",
+		"name", networkPolicy.Name,
+		"op", op,
+		"err", err,
+	)
+
+	if err != nil {
+		setDegraded(&host.Status, err)
+		return controllerutil.OperationResultNone, err
+	}
+
+	return op, nil
+}
+
+// apiServerEgressRules returns the rules reaching the endpoints of the kubernetes Service in the default namespace, the
+// API server. Traffic to the Service address is translated to an endpoint before policies apply, so the endpoints are
+// the peers. There is no rule when the Service has no endpoints.
+func (r *KDexHostReconciler) apiServerEgressRules(ctx context.Context) ([]networkingv1.NetworkPolicyEgressRule, error) {
+	var endpointSlices discoveryv1.EndpointSliceList
+	if err := r.APIReader.List(ctx, &endpointSlices,
+		client.InNamespace(metav1.NamespaceDefault),
+		client.MatchingLabels{discoveryv1.LabelServiceName: "kubernetes"},
+	); err != nil {
+		return nil, err
+	}
+
+	rules := []networkingv1.NetworkPolicyEgressRule{}
+	for _, endpointSlice := range endpointSlices.Items {
+		rule := networkingv1.NetworkPolicyEgressRule{}
+
+		for _, port := range endpointSlice.Ports {
+			if port.Port == nil {
+				continue
+			}
+			value := intstr.FromInt32(*port.Port)
+			rule.Ports = append(rule.Ports, networkingv1.NetworkPolicyPort{Port: &value, Protocol: port.Protocol})
+		}
+
+		for _, endpoint := range endpointSlice.Endpoints {
+			for _, address := range endpoint.Addresses {
+				ip := net.ParseIP(address)
+				if ip == nil {
+					continue
+				}
+				cidr := address + "/32"
+				if ip.To4() == nil {
+					cidr = address + "/128"
+				}
+				rule.To = append(rule.To, networkingv1.NetworkPolicyPeer{IPBlock: &networkingv1.IPBlock{CIDR: cidr}})
+			}
+		}
+
+		if len(rule.To) > 0 {
+			rules = append(rules, rule)
+		}
+	}
+
+	return rules, nil
+}
+
+// functionHost maps a KDexFunction to the host it belongs to.
+func functionHost(_ context.Context, obj client.Object) []reconcile.Request {
+	function, ok := obj.(*kdexv1alpha1.KDexFunction)
+	if !ok || function.Spec.HostRef.Name == "" {
+		return nil
+	}
+
+	return []reconcile.Request{
+		{
+			NamespacedName: client.ObjectKey{Name: function.Spec.HostRef.Name, Namespace: function.Namespace},
+		},
+	}
+}
+
+// inClusterEgressRule returns a rule allowing egress to address, a host[:port] or URL, when it names an in-cluster
+// service. Other addresses cannot be expressed in a NetworkPolicy and yield nil.
+func inClusterEgressRule(address string) *networkingv1.NetworkPolicyEgressRule {
+	if address == "" {
+		return nil
+	}
+
+	if !strings.Contains(address, "://") {
+		address = "//" + address
+	}
+
+	parsed, err := url.Parse(address)
+	if err != nil {
+		return nil
+	}
+
+	matches := inClusterService.FindStringSubmatch(parsed.Hostname())
+	if matches == nil {
+		return nil
+	}
+
+	// No ports are given because policies apply to pod ports, which the service port in address need not match.
+	return &networkingv1.NetworkPolicyEgressRule{
+		To: []networkingv1.NetworkPolicyPeer{
+			{
+				NamespaceSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{corev1.LabelMetadataName: matches[1]},
+				},
+			},
+		},
+	}
+}
+
+func orDefault(value string, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
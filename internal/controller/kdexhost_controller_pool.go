@@ -0,0 +1,299 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/kdex-tech/nexus-manager/internal/hostoptions"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	kdexv1alpha1 "kdex.dev/crds/api/v1alpha1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// poolLabel names the pool of the resources shared by the hosts of a pool.
+	poolLabel = "kdex.dev/pool"
+
+	poolAttribute        = "pool"
+	poolMembersAttribute = "pool.members"
+)
+
+// servingName returns the name of the Deployment, Service, ServiceAccount and ConfigMap which serve the host: those
+// of its pool when it is pooled, its own otherwise.
+func servingName(host *kdexv1alpha1.KDexHost) string {
+	if pool := hostoptions.GetPool(host.Annotations); pool != "" {
+		return hostoptions.PoolResourceName(pool)
+	}
+	return host.Name
+}
+
+// servingObjectMeta returns the metadata of the resources which serve the host along with, when the host is pooled,
+// the members of its pool. The metadata of pool resources is the same whichever member renders it, so that members
+// do not undo each other's changes.
+func (r *KDexHostReconciler) servingObjectMeta(
+	ctx context.Context,
+	host *kdexv1alpha1.KDexHost,
+) (metav1.ObjectMeta, []kdexv1alpha1.KDexHost, error) {
+	pool := hostoptions.GetPool(host.Annotations)
+	if pool == "" {
+		return r.hostOwnedObjectMeta(host, host.Name, host.Namespace), nil, nil
+	}
+
+	members, err := r.poolMembers(ctx, host.Namespace, pool)
+	if err != nil {
+		return metav1.ObjectMeta{}, nil, err
+	}
+
+	om := metav1.ObjectMeta{
+		Annotations: make(map[string]string),
+		Labels: map[string]string{
+			"app.kubernetes.io/name": kdexWeb,
+			"kdex.dev/instance":      hostoptions.PoolResourceName(pool),
+			poolLabel:                pool,
+		},
+		Name:      hostoptions.PoolResourceName(pool),
+		Namespace: host.Namespace,
+	}
+
+	return om, members, nil
+}
+
+// setServingOwners makes the host the controller of obj or, for a pool resource, makes every member of the pool an
+// owner of obj. The resource is then garbage collected along with its last member.
+func (r *KDexHostReconciler) setServingOwners(
+	host *kdexv1alpha1.KDexHost,
+	members []kdexv1alpha1.KDexHost,
+	obj client.Object,
+) error {
+	if members == nil {
+		return ctrl.SetControllerReference(host, obj, r.Scheme)
+	}
+
+	for i := range members {
+		if err := controllerutil.SetOwnerReference(&members[i], obj, r.Scheme); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// poolMembers returns the hosts of the namespace, not being deleted, which belong to pool, sorted by name.
+func (r *KDexHostReconciler) poolMembers(ctx context.Context, namespace string, pool string) ([]kdexv1alpha1.KDexHost, error) {
+	var hosts kdexv1alpha1.KDexHostList
+	if err := r.List(ctx, &hosts, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+
+	members := []kdexv1alpha1.KDexHost{}
+	for _, host := range hosts.Items {
+		if host.DeletionTimestamp.IsZero() && hostoptions.GetPool(host.Annotations) == pool {
+			members = append(members, host)
+		}
+	}
+
+	slices.SortFunc(members, func(a, b kdexv1alpha1.KDexHost) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return members, nil
+}
+
+// focalHosts returns the hosts served by a Deployment: the members of the pool or else the host alone.
+func focalHosts(host *kdexv1alpha1.KDexHost, members []kdexv1alpha1.KDexHost) []string {
+	if members == nil {
+		return []string{host.Name}
+	}
+
+	names := make([]string, 0, len(members))
+	for _, member := range members {
+		names = append(names, member.Name)
+	}
+
+	return names
+}
+
+// setFlag replaces the first of the values containing flag by one flag per setting and drops the others. It reports
+// whether flag was found.
+func setFlag(values []string, flag string, settings []string) ([]string, bool) {
+	if len(values) == 0 {
+		return values, false
+	}
+
+	result := make([]string, 0, len(values)+len(settings))
+	found := false
+
+	for _, value := range values {
+		if !strings.Contains(value, flag) {
+			result = append(result, value)
+			continue
+		}
+		if !found {
+			for _, setting := range settings {
+				result = append(result, flag+"="+setting)
+			}
+		}
+		found = true
+	}
+
+	return result, found
+}
+
+// reconcilePool records the pool of the host in its status. The resources the host had of its own before it joined
+// the pool are deleted; once it has left a pool, the pool is deleted if the host was its last member. Both wait until
+// the Deployment now serving the host is available.
+func (r *KDexHostReconciler) reconcilePool(ctx context.Context, host *kdexv1alpha1.KDexHost) error {
+	pool := hostoptions.GetPool(host.Annotations)
+	if pool == "" {
+		delete(host.Status.Attributes, poolAttribute)
+		delete(host.Status.Attributes, poolMembersAttribute)
+		return r.leavePools(ctx, host)
+	}
+
+	members, err := r.poolMembers(ctx, host.Namespace, pool)
+	if err != nil {
+		return err
+	}
+
+	host.Status.Attributes[poolAttribute] = pool
+	host.Status.Attributes[poolMembersAttribute] = fmt.Sprintf("%d", len(members))
+
+	for _, obj := range []client.Object{
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: host.Name, Namespace: host.Namespace}},
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: host.Name, Namespace: host.Namespace}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: host.Name, Namespace: host.Namespace}},
+	} {
+		if _, err := r.deleteOwned(ctx, host, obj); err != nil {
+			return err
+		}
+	}
+
+	ownClusterName := clusterScopedName(host.Name, host.Namespace)
+	for _, obj := range []client.Object{
+		&rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: ownClusterName}},
+		&rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: ownClusterName}},
+		&rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Name: host.Name, Namespace: host.Namespace}},
+		&rbacv1.Role{ObjectMeta: metav1.ObjectMeta{Name: host.Name, Namespace: host.Namespace}},
+		&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: host.Name, Namespace: host.Namespace}},
+	} {
+		if err := r.deleteWithFinalizer(ctx, host, obj); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// leavePools deletes the resources of the pools the host no longer belongs to when no member is left. Pools which
+// still have members are rendered again by them.
+func (r *KDexHostReconciler) leavePools(ctx context.Context, host *kdexv1alpha1.KDexHost) error {
+	log := logf.FromContext(ctx)
+
+	for _, pool := range r.ownedPools(ctx, host) {
+		if pool == hostoptions.GetPool(host.Annotations) {
+			continue
+		}
+
+		members, err := r.poolMembers(ctx, host.Namespace, pool)
+		if err != nil {
+			return err
+		}
+		if len(members) > 0 {
+			continue
+		}
+
+		if err := r.deletePool(ctx, host.Namespace, pool); err != nil {
+			return err
+		}
+
+		log.V(1).Info("deleted pool", "pool", pool)
+	}
+
+	return nil
+}
+
+// deletePool deletes the resources shared by the hosts of pool, which has no member left.
+func (r *KDexHostReconciler) deletePool(ctx context.Context, namespace string, pool string) error {
+	name := hostoptions.PoolResourceName(pool)
+	clusterName := clusterScopedName(name, namespace)
+
+	for _, obj := range []client.Object{
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}},
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}},
+		&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}},
+		&rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}},
+		&rbacv1.Role{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}},
+		&rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: clusterName}},
+		&rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: clusterName}},
+	} {
+		if err := r.Delete(ctx, obj); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ownedPools returns the pools whose Deployment the host owns.
+func (r *KDexHostReconciler) ownedPools(ctx context.Context, host *kdexv1alpha1.KDexHost) []string {
+	var deployments appsv1.DeploymentList
+	if err := r.List(ctx, &deployments, client.InNamespace(host.Namespace), client.HasLabels{poolLabel}); err != nil {
+		logf.FromContext(ctx).Error(err, "failed to list pool deployments")
+		return nil
+	}
+
+	pools := []string{}
+	for _, deployment := range deployments.Items {
+		for _, owner := range deployment.OwnerReferences {
+			if owner.UID == host.UID {
+				pools = append(pools, deployment.Labels[poolLabel])
+			}
+		}
+	}
+
+	return pools
+}
+
+// poolPeers maps a KDexHost to the other members of the pools it belongs or belonged to, so that the pool Deployment
+// is rendered again when a host joins or leaves.
+func (r *KDexHostReconciler) poolPeers(ctx context.Context, obj client.Object) []reconcile.Request {
+	host, ok := obj.(*kdexv1alpha1.KDexHost)
+	if !ok {
+		return nil
+	}
+
+	pools := r.ownedPools(ctx, host)
+	if pool := hostoptions.GetPool(host.Annotations); pool != "" && !slices.Contains(pools, pool) {
+		pools = append(pools, pool)
+	}
+
+	requests := []reconcile.Request{}
+	for _, pool := range pools {
+		members, err := r.poolMembers(ctx, host.Namespace, pool)
+		if err != nil {
+			logf.FromContext(ctx).Error(err, "failed to list pool members", "pool", pool)
+			continue
+		}
+
+		for _, member := range members {
+			if member.Name != host.Name {
+				requests = append(requests, reconcile.Request{
+					NamespacedName: types.NamespacedName{Name: member.Name, Namespace: member.Namespace},
+				})
+			}
+		}
+	}
+
+	return requests
+}
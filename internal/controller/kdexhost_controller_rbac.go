@@ -0,0 +1,392 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kdex-tech/nexus-manager/internal/extensions"
+	"github.com/kdex-tech/nexus-manager/internal/hostoptions"
+	"github.com/kdex-tech/nexus-manager/internal/validation"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	kdexv1alpha1 "kdex.dev/crds/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// createOrUpdateRoleBinding grants the host service account the permissions of its RBAC profile: cluster-wide through
+// a ClusterRoleBinding or, in namespaced mode, through a Role and RoleBinding in the host namespace plus a
+// ClusterRoleBinding for the rules on cluster-scoped resources. The bindings of the other mode are removed.
+//
+// The hosts of a pool share one service account and so one set of bindings, rendered alike by every member; members
+// must therefore agree on their profile and scope.
+func (r *KDexHostReconciler) createOrUpdateRoleBinding(
+	ctx context.Context,
+	host *kdexv1alpha1.KDexHost,
+) (controllerutil.OperationResult, error) {
+	log := logf.FromContext(ctx)
+
+	settings := r.getExtensions().HostDefault.RBAC
+
+	profileName, profile, err := hostoptions.GetRBACProfile(host.Annotations, settings)
+	if err == nil {
+		err = r.checkRBACProfile(ctx, host, profileName, profile)
+	}
+
+	namespaced := false
+	if err == nil {
+		namespaced, err = hostoptions.IsRBACNamespaced(host.Annotations, settings)
+	}
+
+	var objectMeta metav1.ObjectMeta
+	var members []kdexv1alpha1.KDexHost
+	if err == nil {
+		objectMeta, members, err = r.servingObjectMeta(ctx, host)
+	}
+	if err == nil {
+		err = validation.ValidatePoolRBAC(host, members, settings)
+	}
+
+	roleRef := r.getConfiguration().HostDefault.RoleRef
+	if profile.ClusterRole != "" {
+		roleRef = rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     profile.ClusterRole,
+		}
+	}
+
+	op := controllerutil.OperationResultNone
+	if err == nil {
+		if namespaced {
+			op, err = r.applyNamespacedRoleBinding(ctx, host, objectMeta, members, roleRef)
+		} else {
+			op, err = r.applyClusterRoleBinding(ctx, host, objectMeta, members, roleRef)
+			for _, obj := range []client.Object{
+				&rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: clusterRoleBindingName(host)}},
+				&rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Name: servingName(host), Namespace: host.Namespace}},
+				&rbacv1.Role{ObjectMeta: metav1.ObjectMeta{Name: servingName(host), Namespace: host.Namespace}},
+			} {
+				if err != nil {
+					break
+				}
+				err = r.deleteWithFinalizer(ctx, host, obj)
+			}
+		}
+	}
+
+	log.V(2).Info(
+		"createOrUpdateRoleBinding",
+		"profile", profileName,
+		"roleRef", roleRef.Name,
+		"namespaced", namespaced,
+		"op", op,
+		"err", err,
+	)
+
+	if err != nil {
+		setDegraded(&host.Status, err)
+		return controllerutil.OperationResultNone, err
+	}
+
+	host.Status.Attributes["rbac.profile"] = profileName
+
+	return op, nil
+}
+
+// checkRBACProfile verifies that the profile grants what the host uses. The webhook only sees the host spec; here the
+// KDexFunctions bound to the host are taken into account too.
+func (r *KDexHostReconciler) checkRBACProfile(
+	ctx context.Context,
+	host *kdexv1alpha1.KDexHost,
+	profileName string,
+	profile extensions.RBACProfile,
+) error {
+	if profile.Functions {
+		return nil
+	}
+
+	if host.Spec.FaaSAdaptorRef != nil {
+		return fmt.Errorf("RBAC profile %q does not allow KDexFunctions, which spec.faasAdaptorRef requires", profileName)
+	}
+
+	var functions kdexv1alpha1.KDexFunctionList
+	if err := r.List(ctx, &functions, client.InNamespace(host.Namespace), client.MatchingFields{hostIndexKey: host.Name}); err != nil {
+		return err
+	}
+	if len(functions.Items) > 0 {
+		return fmt.Errorf("RBAC profile %q does not allow KDexFunctions, but %d reference the host", profileName, len(functions.Items))
+	}
+
+	return nil
+}
+
+func (r *KDexHostReconciler) applyClusterRoleBinding(
+	ctx context.Context,
+	host *kdexv1alpha1.KDexHost,
+	objectMeta metav1.ObjectMeta,
+	members []kdexv1alpha1.KDexHost,
+	roleRef rbacv1.RoleRef,
+) (controllerutil.OperationResult, error) {
+	clusterRoleBinding := &rbacv1.ClusterRoleBinding{
+		ObjectMeta: rbacObjectMeta(objectMeta, clusterRoleBindingName(host), ""),
+		RoleRef:    roleRef,
+		Subjects:   hostSubjects(host),
+	}
+
+	// The roleRef of a binding is immutable, so a change of profile replaces the binding.
+	existing := &rbacv1.ClusterRoleBinding{}
+	err := r.Get(ctx, client.ObjectKeyFromObject(clusterRoleBinding), existing)
+	if err == nil && existing.RoleRef != roleRef {
+		err = r.deleteWithFinalizer(ctx, host, existing)
+	}
+	if client.IgnoreNotFound(err) != nil {
+		return controllerutil.OperationResultNone, err
+	}
+
+	if members == nil {
+		controllerutil.AddFinalizer(clusterRoleBinding, hostFinalizerName)
+	}
+
+	return r.applyOwned(ctx, host, clusterRoleBinding)
+}
+
+// applyNamespacedRoleBinding copies the rules of the profile ClusterRole on namespaced resources into a Role in the
+// host namespace and binds the host service account to it. The rules on cluster-scoped resources, such as the
+// KDexCluster kinds, cannot be granted by a Role; they are copied into a ClusterRole of the host, bound cluster-wide.
+func (r *KDexHostReconciler) applyNamespacedRoleBinding(
+	ctx context.Context,
+	host *kdexv1alpha1.KDexHost,
+	objectMeta metav1.ObjectMeta,
+	members []kdexv1alpha1.KDexHost,
+	roleRef rbacv1.RoleRef,
+) (controllerutil.OperationResult, error) {
+	clusterRole := &rbacv1.ClusterRole{}
+	if err := r.Get(ctx, client.ObjectKey{Name: roleRef.Name}, clusterRole); err != nil {
+		return controllerutil.OperationResultNone, fmt.Errorf("failed to get ClusterRole %s: %w", roleRef.Name, err)
+	}
+
+	namespacedRules, clusterRules, err := r.splitRules(clusterRole.Rules)
+	if err != nil {
+		return controllerutil.OperationResultNone, err
+	}
+
+	role := &rbacv1.Role{
+		ObjectMeta: rbacObjectMeta(objectMeta, objectMeta.Name, host.Namespace),
+		Rules:      namespacedRules,
+	}
+
+	if members == nil {
+		controllerutil.AddFinalizer(role, hostFinalizerName)
+	}
+
+	roleOp := controllerutil.OperationResultNone
+	err = r.setServingOwners(host, members, role)
+	if err == nil {
+		roleOp, err = r.applyOwned(ctx, host, role)
+	}
+	if err != nil {
+		return controllerutil.OperationResultNone, err
+	}
+
+	roleBinding := &rbacv1.RoleBinding{
+		ObjectMeta: rbacObjectMeta(objectMeta, objectMeta.Name, host.Namespace),
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "Role",
+			Name:     role.Name,
+		},
+		Subjects: hostSubjects(host),
+	}
+
+	if members == nil {
+		controllerutil.AddFinalizer(roleBinding, hostFinalizerName)
+	}
+
+	op := controllerutil.OperationResultNone
+	err = r.setServingOwners(host, members, roleBinding)
+	if err == nil {
+		op, err = r.applyOwned(ctx, host, roleBinding)
+	}
+	if err != nil {
+		return controllerutil.OperationResultNone, err
+	}
+
+	clusterOp := controllerutil.OperationResultNone
+	if len(clusterRules) == 0 {
+		for _, obj := range []client.Object{
+			&rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: clusterRoleBindingName(host)}},
+			&rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: clusterRoleBindingName(host)}},
+		} {
+			if err := r.deleteWithFinalizer(ctx, host, obj); err != nil {
+				return controllerutil.OperationResultNone, err
+			}
+		}
+	} else {
+		hostClusterRole := &rbacv1.ClusterRole{
+			ObjectMeta: rbacObjectMeta(objectMeta, clusterRoleBindingName(host), ""),
+			Rules:      clusterRules,
+		}
+		if members == nil {
+			controllerutil.AddFinalizer(hostClusterRole, hostFinalizerName)
+		}
+
+		clusterOp, err = r.applyOwned(ctx, host, hostClusterRole)
+		if err == nil {
+			var bindingOp controllerutil.OperationResult
+			bindingOp, err = r.applyClusterRoleBinding(ctx, host, objectMeta, members, rbacv1.RoleRef{
+				APIGroup: rbacv1.GroupName,
+				Kind:     "ClusterRole",
+				Name:     hostClusterRole.Name,
+			})
+			if clusterOp == controllerutil.OperationResultNone {
+				clusterOp = bindingOp
+			}
+		}
+		if err != nil {
+			return controllerutil.OperationResultNone, err
+		}
+	}
+
+	for _, other := range []controllerutil.OperationResult{roleOp, clusterOp} {
+		if op == controllerutil.OperationResultNone {
+			op = other
+		}
+	}
+
+	return op, nil
+}
+
+// splitRules separates the rules on namespaced resources from those on cluster-scoped resources and non-resource
+// URLs. Wildcards and resources unknown to the API server are kept with the namespaced rules, which never grant
+// beyond the host namespace.
+func (r *KDexHostReconciler) splitRules(rules []rbacv1.PolicyRule) ([]rbacv1.PolicyRule, []rbacv1.PolicyRule, error) {
+	var namespaced, clusterScoped []rbacv1.PolicyRule
+
+	for _, rule := range rules {
+		if len(rule.NonResourceURLs) > 0 {
+			clusterScoped = append(clusterScoped, rule)
+			continue
+		}
+
+		for _, group := range rule.APIGroups {
+			var namespacedResources, clusterResources []string
+			for _, resource := range rule.Resources {
+				isClusterScoped, err := r.isClusterScoped(group, resource)
+				if err != nil {
+					return nil, nil, err
+				}
+				if isClusterScoped {
+					clusterResources = append(clusterResources, resource)
+				} else {
+					namespacedResources = append(namespacedResources, resource)
+				}
+			}
+
+			for _, split := range []struct {
+				resources []string
+				rules     *[]rbacv1.PolicyRule
+			}{
+				{namespacedResources, &namespaced},
+				{clusterResources, &clusterScoped},
+			} {
+				if len(split.resources) == 0 {
+					continue
+				}
+				groupRule := *rule.DeepCopy()
+				groupRule.APIGroups = []string{group}
+				groupRule.Resources = split.resources
+				*split.rules = append(*split.rules, groupRule)
+			}
+		}
+	}
+
+	return namespaced, clusterScoped, nil
+}
+
+// isClusterScoped reports whether the resource, possibly naming a subresource, of group is cluster-scoped.
+func (r *KDexHostReconciler) isClusterScoped(group, resource string) (bool, error) {
+	resource, _, _ = strings.Cut(resource, "/")
+	if group == rbacv1.APIGroupAll || resource == rbacv1.ResourceAll {
+		return false, nil
+	}
+
+	gvk, err := r.RESTMapper().KindFor(schema.GroupVersionResource{Group: group, Resource: resource})
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	mapping, err := r.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return false, err
+	}
+
+	return mapping.Scope.Name() == meta.RESTScopeNameRoot, nil
+}
+
+// deleteWithFinalizer releases the host finalizer held on obj, a binding or role generated for the host or its pool,
+// and deletes it.
+func (r *KDexHostReconciler) deleteWithFinalizer(
+	ctx context.Context,
+	host *kdexv1alpha1.KDexHost,
+	obj client.Object,
+) error {
+	if err := r.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+
+	if instance := obj.GetLabels()["kdex.dev/instance"]; instance != host.Name && instance != servingName(host) {
+		return nil
+	}
+
+	if controllerutil.RemoveFinalizer(obj, hostFinalizerName) {
+		if err := r.Update(ctx, obj); err != nil {
+			return err
+		}
+	}
+
+	if !obj.GetDeletionTimestamp().IsZero() {
+		return nil
+	}
+
+	return client.IgnoreNotFound(r.Delete(ctx, obj))
+}
+
+// clusterRoleBindingName returns the name of the cluster-scoped binding and role of the host, or of its pool when it is
+// pooled.
+func clusterRoleBindingName(host *kdexv1alpha1.KDexHost) string {
+	return clusterScopedName(servingName(host), host.Namespace)
+}
+
+// clusterScopedName returns the name of the cluster-scoped binding and role serving the resources named name in
+// namespace.
+func clusterScopedName(name string, namespace string) string {
+	return fmt.Sprintf("%s-%s", name, namespace)
+}
+
+// rbacObjectMeta returns objectMeta, the metadata of the resources serving a host, for a role or binding named name in
+// namespace, which is empty for cluster-scoped objects.
+func rbacObjectMeta(objectMeta metav1.ObjectMeta, name string, namespace string) metav1.ObjectMeta {
+	om := *objectMeta.DeepCopy()
+	om.Name = name
+	om.Namespace = namespace
+	return om
+}
+
+// hostSubjects returns the service account serving the host, which is that of its pool when it is pooled.
+func hostSubjects(host *kdexv1alpha1.KDexHost) []rbacv1.Subject {
+	return []rbacv1.Subject{
+		{
+			Kind:      "ServiceAccount",
+			Name:      servingName(host),
+			Namespace: host.Namespace,
+		},
+	}
+}
@@ -0,0 +1,237 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/kdex-tech/nexus-manager/internal/extensions"
+	"github.com/kdex-tech/nexus-manager/internal/hostoptions"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kdexv1alpha1 "kdex.dev/crds/api/v1alpha1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// autoscalingFieldManager holds the replicas of an autoscaled Deployment from the moment the manager stops applying
+// them until the HorizontalPodAutoscaler first changes them.
+const autoscalingFieldManager = fieldManager + "-autoscaling"
+
+// handOffReplicas returns the replicas to apply to the Deployment of an autoscaled host. A new Deployment starts with
+// minReplicas. Replicas are otherwise left out, so that the HorizontalPodAutoscaler owns them, but dropping a field
+// nobody else owns would reset it to its default of 1. While the manager still owns the replicas they are therefore
+// first applied, at their live value, by autoscalingFieldManager.
+func (r *KDexHostReconciler) handOffReplicas(
+	ctx context.Context,
+	deployment *appsv1.Deployment,
+	minReplicas int32,
+) (*int32, error) {
+	live := &appsv1.Deployment{}
+	if err := r.APIReader.Get(ctx, client.ObjectKeyFromObject(deployment), live); err != nil {
+		if errors.IsNotFound(err) {
+			return &minReplicas, nil
+		}
+		return nil, err
+	}
+
+	if !managesField(live, fieldManager, "spec", "replicas") {
+		return nil, nil
+	}
+
+	replicas := minReplicas
+	if live.Spec.Replicas != nil {
+		replicas = max(*live.Spec.Replicas, minReplicas)
+	}
+
+	holder := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: live.Name, Namespace: live.Namespace},
+		Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+	}
+	holder.SetGroupVersionKind(appsv1.SchemeGroupVersion.WithKind("Deployment"))
+
+	desired, err := toApplyConfiguration(holder)
+	if err != nil {
+		return nil, err
+	}
+	// Only replicas are owned by autoscalingFieldManager.
+	desired.Object["spec"] = map[string]any{"replicas": int64(replicas)}
+
+	// Without ForceOwnership the hand-off fails, and is retried, if the replicas changed since they were read.
+	if err := r.Apply(ctx, client.ApplyConfigurationFromUnstructured(desired), client.FieldOwner(autoscalingFieldManager)); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// managesField reports whether manager applied the field at path of obj.
+func managesField(obj metav1.Object, manager string, path ...string) bool {
+	for _, entry := range obj.GetManagedFields() {
+		if entry.Manager != manager || entry.Operation != metav1.ManagedFieldsOperationApply || entry.FieldsV1 == nil {
+			continue
+		}
+
+		fields := map[string]any{}
+		if err := json.Unmarshal(entry.FieldsV1.Raw, &fields); err != nil {
+			continue
+		}
+
+		found := true
+		for _, name := range path {
+			child, ok := fields["f:"+name].(map[string]any)
+			if !ok {
+				found = false
+				break
+			}
+			fields = child
+		}
+		if found {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *KDexHostReconciler) createOrUpdateHorizontalPodAutoscaler(
+	ctx context.Context,
+	host *kdexv1alpha1.KDexHost,
+) (controllerutil.OperationResult, error) {
+	log := logf.FromContext(ctx)
+
+	hpa := &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: r.hostOwnedObjectMeta(host, host.Name, host.Namespace),
+	}
+
+	// The Deployment of a pool is not scaled by any one of its members.
+	autoscaling, err := hostoptions.GetAutoscaling(host.Annotations)
+	if err == nil && (autoscaling == nil || hostoptions.GetPool(host.Annotations) != "") {
+		var deleted bool
+		deleted, err = r.deleteOwned(ctx, host, hpa)
+		if err == nil {
+			log.V(2).Info("deleteHorizontalPodAutoscaler", "name", hpa.Name, "deleted", deleted)
+			return controllerutil.OperationResultNone, nil
+		}
+	}
+
+	op := controllerutil.OperationResultNone
+	if err == nil {
+		hpa.Spec = autoscalingv2.HorizontalPodAutoscalerSpec{
+			Behavior:    autoscaling.Behavior,
+			MaxReplicas: autoscaling.MaxReplicas,
+			Metrics:     autoscaling.Metrics,
+			MinReplicas: autoscaling.MinReplicas,
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
+				APIVersion: "apps/v1",
+				Kind:       "Deployment",
+				Name:       host.Name,
+			},
+		}
+
+		err = ctrl.SetControllerReference(host, hpa, r.Scheme)
+		if err == nil {
+			op, err = r.applyOwned(ctx, host, hpa)
+		}
+	}
+
+	log.V(2).Info(
+		"createOrUpdateHorizontalPodAutoscaler",
+		"name", hpa.Name,
+		"op", op,
+		"err", err,
+	)
+
+	if err != nil {
+		setDegraded(&host.Status, err)
+		return controllerutil.OperationResultNone, err
+	}
+
+	return op, nil
+}
+
+func (r *KDexHostReconciler) createOrUpdatePodDisruptionBudget(
+	ctx context.Context,
+	host *kdexv1alpha1.KDexHost,
+) (controllerutil.OperationResult, error) {
+	log := logf.FromContext(ctx)
+
+	pdb := &policyv1.PodDisruptionBudget{
+		ObjectMeta: r.hostOwnedObjectMeta(host, host.Name, host.Namespace),
+	}
+
+	replicas, err := r.effectiveReplicas(host)
+	var budget extensions.PodDisruptionBudget
+	if err == nil {
+		budget, err = hostoptions.GetPodDisruptionBudget(
+			host.Annotations, r.getExtensions().HostDefault.PodDisruptionBudget)
+	}
+
+	// A single replica cannot be protected without blocking node drains entirely. Nor is the Deployment of a pool
+	// protected by any one of its members.
+	if err == nil && (budget.IsDisabled() || replicas <= 1 || hostoptions.GetPool(host.Annotations) != "") {
+		var deleted bool
+		deleted, err = r.deleteOwned(ctx, host, pdb)
+		if err == nil {
+			log.V(2).Info("deletePodDisruptionBudget", "name", pdb.Name, "deleted", deleted)
+			return controllerutil.OperationResultNone, nil
+		}
+	}
+
+	op := controllerutil.OperationResultNone
+	if err == nil {
+		pdb.Spec = budget.Spec()
+		pdb.Spec.Selector = &metav1.LabelSelector{
+			MatchLabels: map[string]string{
+				"app.kubernetes.io/name": kdexWeb,
+				"kdex.dev/instance":      host.Name,
+			},
+		}
+
+		err = ctrl.SetControllerReference(host, pdb, r.Scheme)
+		if err == nil {
+			op, err = r.applyOwned(ctx, host, pdb)
+		}
+	}
+
+	log.V(2).Info(
+		"createOrUpdatePodDisruptionBudget",
+		"name", pdb.Name,
+		"replicas", replicas,
+		"op", op,
+		"err", err,
+	)
+
+	if err != nil {
+		setDegraded(&host.Status, err)
+		return controllerutil.OperationResultNone, err
+	}
+
+	return op, nil
+}
+
+// effectiveReplicas returns the number of replicas the host is guaranteed to run: the autoscaling minimum when
+// autoscaling, otherwise the host's replicas falling back to the HostDefault deployment's.
+func (r *KDexHostReconciler) effectiveReplicas(host *kdexv1alpha1.KDexHost) (int32, error) {
+	autoscaling, err := hostoptions.GetAutoscaling(host.Annotations)
+	if err != nil {
+		return 0, err
+	}
+
+	switch {
+	case autoscaling != nil:
+		return *autoscaling.MinReplicas, nil
+	case host.Spec.Replicas != nil:
+		return *host.Spec.Replicas, nil
+	}
+
+	if replicas := r.getMemoizedDeployment().Replicas; replicas != nil {
+		return *replicas, nil
+	}
+
+	return 1, nil
+}
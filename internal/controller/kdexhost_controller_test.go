@@ -20,11 +20,24 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/kdex-tech/nexus-manager/internal/hostoptions"
+	"github.com/kdex-tech/nexus-manager/internal/patch"
+	"github.com/kdex-tech/nexus-manager/internal/utils"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	kdexv1alpha1 "kdex.dev/crds/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 var _ = Describe("KDexHost Controller", func() {
@@ -120,6 +133,857 @@ var _ = Describe("KDexHost Controller", func() {
 				&kdexv1alpha1.KDexHost{}, true)
 		})
 
+		It("it records an event when the host becomes ready", func() {
+			resource := &kdexv1alpha1.KDexHost{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: namespace,
+				},
+				Spec: kdexv1alpha1.KDexHostSpec{
+					BrandName:    "KDex Tech",
+					Organization: "KDex Tech Inc.",
+					Routing: kdexv1alpha1.Routing{
+						Domains: []string{
+							"kdex.dev",
+						},
+					},
+				},
+			}
+
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+			assertResourceReady(
+				ctx, k8sClient, resourceName, namespace,
+				&kdexv1alpha1.KDexHost{}, true)
+
+			Eventually(func(g Gomega) {
+				list := &eventsv1.EventList{}
+				g.Expect(k8sClient.List(ctx, list, client.InNamespace(namespace))).To(Succeed())
+
+				reasons := []string{}
+				for _, event := range list.Items {
+					if event.Regarding.Kind == "KDexHost" && event.Regarding.Name == resourceName {
+						reasons = append(reasons, event.Reason)
+					}
+				}
+				g.Expect(reasons).To(ContainElements(EventReasonCreated, EventReasonReady))
+			}).Should(Succeed())
+		})
+
+		It("it reports why the deployment is not available", func() {
+			resource := &kdexv1alpha1.KDexHost{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: namespace,
+				},
+				Spec: kdexv1alpha1.KDexHostSpec{
+					BrandName:    "KDex Tech",
+					Organization: "KDex Tech Inc.",
+					Routing: kdexv1alpha1.Routing{
+						Domains: []string{
+							"kdex.dev",
+						},
+					},
+				},
+			}
+
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+			assertResourceReady(
+				ctx, k8sClient, resourceName, namespace,
+				&kdexv1alpha1.KDexHost{}, true)
+
+			deployment := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resourceName, Namespace: namespace}, deployment)).To(Succeed())
+
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName + "-pod",
+					Namespace: namespace,
+					Labels:    deployment.Spec.Template.Labels,
+				},
+				Spec: deployment.Spec.Template.Spec,
+			}
+			Expect(k8sClient.Create(ctx, pod)).To(Succeed())
+			pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+				Name:  pod.Spec.Containers[0].Name,
+				Image: pod.Spec.Containers[0].Image,
+				State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{
+					Reason:  "ImagePullBackOff",
+					Message: "Back-off pulling image",
+				}},
+			}}
+			Expect(k8sClient.Status().Update(ctx, pod)).To(Succeed())
+
+			deployment.Status.Conditions = []appsv1.DeploymentCondition{{
+				Type:   appsv1.DeploymentAvailable,
+				Status: corev1.ConditionFalse,
+				Reason: "MinimumReplicasUnavailable",
+			}}
+			Expect(k8sClient.Status().Update(ctx, deployment)).To(Succeed())
+
+			Eventually(func(g Gomega) {
+				g.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resourceName, Namespace: namespace}, resource)).To(Succeed())
+				condition := meta.FindStatusCondition(resource.Status.Conditions, string(kdexv1alpha1.ConditionTypeReady))
+				g.Expect(condition).NotTo(BeNil())
+				g.Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+				g.Expect(condition.Reason).To(Equal("ImagePullBackOff"))
+				g.Expect(condition.Message).To(ContainSubstring("Back-off pulling image"))
+				g.Expect(resource.Status.Attributes).To(HaveKeyWithValue("deployment.reason", "ImagePullBackOff"))
+				g.Expect(resource.Status.Attributes).To(HaveKeyWithValue("deployment.replicas", "0/1"))
+			}).Should(Succeed())
+
+			Expect(k8sClient.Delete(ctx, pod)).To(Succeed())
+		})
+
+		It("it corrects and reports drift on owned resources", func() {
+			resource := &kdexv1alpha1.KDexHost{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: namespace,
+				},
+				Spec: kdexv1alpha1.KDexHostSpec{
+					BrandName:    "KDex Tech",
+					Organization: "KDex Tech Inc.",
+					Routing: kdexv1alpha1.Routing{
+						Domains: []string{
+							"kdex.dev",
+						},
+					},
+				},
+			}
+
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+			assertResourceReady(
+				ctx, k8sClient, resourceName, namespace,
+				&kdexv1alpha1.KDexHost{}, true)
+
+			Eventually(func(g Gomega) {
+				deployment := &appsv1.Deployment{}
+				g.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resourceName, Namespace: namespace}, deployment)).To(Succeed())
+				deployment.Spec.Template.Spec.TerminationGracePeriodSeconds = utils.Ptr(int64(99))
+				g.Expect(k8sClient.Update(ctx, deployment)).To(Succeed())
+			}).Should(Succeed())
+
+			Eventually(func(g Gomega) {
+				deployment := &appsv1.Deployment{}
+				g.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resourceName, Namespace: namespace}, deployment)).To(Succeed())
+				g.Expect(*deployment.Spec.Template.Spec.TerminationGracePeriodSeconds).To(Equal(int64(10)))
+
+				host := &kdexv1alpha1.KDexHost{}
+				g.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resourceName, Namespace: namespace}, host)).To(Succeed())
+				g.Expect(meta.IsStatusConditionTrue(host.Status.Conditions, string(ConditionTypeDrifted))).To(BeTrue())
+				g.Expect(host.Status.Attributes["deployment."+resourceName+".drift"]).To(
+					ContainSubstring("spec.template.spec.terminationGracePeriodSeconds"))
+			}, "10s").Should(Succeed())
+		})
+
+		It("it leaves owned resources alone while the host is paused", func() {
+			resource := &kdexv1alpha1.KDexHost{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: namespace,
+				},
+				Spec: kdexv1alpha1.KDexHostSpec{
+					BrandName:    "KDex Tech",
+					Organization: "KDex Tech Inc.",
+					Routing: kdexv1alpha1.Routing{
+						Domains: []string{
+							"kdex.dev",
+						},
+					},
+				},
+			}
+
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+			assertResourceReady(
+				ctx, k8sClient, resourceName, namespace,
+				&kdexv1alpha1.KDexHost{}, true)
+
+			key := types.NamespacedName{Name: resourceName, Namespace: namespace}
+
+			Eventually(func(g Gomega) {
+				host := &kdexv1alpha1.KDexHost{}
+				g.Expect(k8sClient.Get(ctx, key, host)).To(Succeed())
+				host.Annotations = map[string]string{PausedAnnotation: "true"}
+				g.Expect(k8sClient.Update(ctx, host)).To(Succeed())
+			}).Should(Succeed())
+
+			Eventually(func(g Gomega) {
+				host := &kdexv1alpha1.KDexHost{}
+				g.Expect(k8sClient.Get(ctx, key, host)).To(Succeed())
+				g.Expect(meta.IsStatusConditionTrue(host.Status.Conditions, string(ConditionTypePaused))).To(BeTrue())
+			}).Should(Succeed())
+
+			Eventually(func(g Gomega) {
+				deployment := &appsv1.Deployment{}
+				g.Expect(k8sClient.Get(ctx, key, deployment)).To(Succeed())
+				deployment.Spec.Template.Spec.TerminationGracePeriodSeconds = utils.Ptr(int64(99))
+				g.Expect(k8sClient.Update(ctx, deployment)).To(Succeed())
+			}).Should(Succeed())
+
+			Consistently(func(g Gomega) {
+				deployment := &appsv1.Deployment{}
+				g.Expect(k8sClient.Get(ctx, key, deployment)).To(Succeed())
+				g.Expect(*deployment.Spec.Template.Spec.TerminationGracePeriodSeconds).To(Equal(int64(99)))
+			}, "2s").Should(Succeed())
+
+			Eventually(func(g Gomega) {
+				host := &kdexv1alpha1.KDexHost{}
+				g.Expect(k8sClient.Get(ctx, key, host)).To(Succeed())
+				delete(host.Annotations, PausedAnnotation)
+				g.Expect(k8sClient.Update(ctx, host)).To(Succeed())
+			}).Should(Succeed())
+
+			Eventually(func(g Gomega) {
+				deployment := &appsv1.Deployment{}
+				g.Expect(k8sClient.Get(ctx, key, deployment)).To(Succeed())
+				g.Expect(*deployment.Spec.Template.Spec.TerminationGracePeriodSeconds).To(Equal(int64(10)))
+
+				host := &kdexv1alpha1.KDexHost{}
+				g.Expect(k8sClient.Get(ctx, key, host)).To(Succeed())
+				g.Expect(meta.FindStatusCondition(host.Status.Conditions, string(ConditionTypePaused))).To(BeNil())
+			}, "10s").Should(Succeed())
+		})
+
+		It("it puts the host into maintenance", func() {
+			resource := &kdexv1alpha1.KDexHost{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: namespace,
+					Annotations: map[string]string{
+						hostoptions.MaintenanceAnnotation: "enabled: true\nretryAfter: 10m\n",
+					},
+				},
+				Spec: kdexv1alpha1.KDexHostSpec{
+					BrandName:    "KDex Tech",
+					Organization: "KDex Tech Inc.",
+					Routing: kdexv1alpha1.Routing{
+						Domains: []string{
+							"kdex.dev",
+						},
+					},
+				},
+			}
+
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+			assertResourceReady(
+				ctx, k8sClient, resourceName, namespace,
+				&kdexv1alpha1.KDexHost{}, true)
+
+			key := types.NamespacedName{Name: resourceName, Namespace: namespace}
+
+			Eventually(func(g Gomega) {
+				host := &kdexv1alpha1.KDexHost{}
+				g.Expect(k8sClient.Get(ctx, key, host)).To(Succeed())
+				g.Expect(meta.IsStatusConditionTrue(host.Status.Conditions, string(ConditionTypeMaintenance))).To(BeTrue())
+				g.Expect(host.Status.Attributes).To(HaveKey(maintenanceStartedAttribute))
+
+				internalHost := &kdexv1alpha1.KDexInternalHost{}
+				g.Expect(k8sClient.Get(ctx, key, internalHost)).To(Succeed())
+				g.Expect(internalHost.Annotations).To(HaveKeyWithValue(maintenanceStatusAnnotation, "503"))
+				g.Expect(internalHost.Annotations).To(HaveKeyWithValue(maintenanceRetryAfterAnnotation, "600"))
+			}).Should(Succeed())
+
+			Eventually(func(g Gomega) {
+				host := &kdexv1alpha1.KDexHost{}
+				g.Expect(k8sClient.Get(ctx, key, host)).To(Succeed())
+				delete(host.Annotations, hostoptions.MaintenanceAnnotation)
+				g.Expect(k8sClient.Update(ctx, host)).To(Succeed())
+			}).Should(Succeed())
+
+			Eventually(func(g Gomega) {
+				host := &kdexv1alpha1.KDexHost{}
+				g.Expect(k8sClient.Get(ctx, key, host)).To(Succeed())
+				g.Expect(meta.IsStatusConditionFalse(host.Status.Conditions, string(ConditionTypeMaintenance))).To(BeTrue())
+				g.Expect(host.Status.Attributes).To(HaveKey(maintenanceEndedAttribute))
+
+				internalHost := &kdexv1alpha1.KDexInternalHost{}
+				g.Expect(k8sClient.Get(ctx, key, internalHost)).To(Succeed())
+				g.Expect(internalHost.Annotations).NotTo(HaveKey(maintenanceStatusAnnotation))
+			}).Should(Succeed())
+		})
+
+		It("it waits for its FaaS adaptor", func() {
+			resource := &kdexv1alpha1.KDexHost{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: namespace,
+				},
+				Spec: kdexv1alpha1.KDexHostSpec{
+					BrandName: "KDex Tech",
+					FaaSAdaptorRef: &kdexv1alpha1.KDexObjectReference{
+						Kind: "KDexFaaSAdaptor",
+						Name: "knative",
+					},
+					Organization: "KDex Tech Inc.",
+					Routing: kdexv1alpha1.Routing{
+						Domains: []string{
+							"kdex.dev",
+						},
+					},
+				},
+			}
+
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+			assertResourceReady(
+				ctx, k8sClient, resourceName, namespace,
+				&kdexv1alpha1.KDexHost{}, false)
+
+			setClusterBuilder(ctx, k8sClient, "tiny-builder", true)
+
+			adaptor := &kdexv1alpha1.KDexFaaSAdaptor{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "knative",
+					Namespace: namespace,
+				},
+				Spec: faasAdaptorSpec("tiny-builder"),
+			}
+
+			Expect(k8sClient.Create(ctx, adaptor)).To(Succeed())
+
+			assertResourceReady(
+				ctx, k8sClient, resourceName, namespace,
+				&kdexv1alpha1.KDexHost{}, true)
+
+			key := types.NamespacedName{Name: resourceName, Namespace: namespace}
+
+			Eventually(func(g Gomega) {
+				host := &kdexv1alpha1.KDexHost{}
+				g.Expect(k8sClient.Get(ctx, key, host)).To(Succeed())
+				g.Expect(host.Status.Attributes).To(HaveKeyWithValue(faasAdaptorGenerationAttribute, "1"))
+
+				internalHost := &kdexv1alpha1.KDexInternalHost{}
+				g.Expect(k8sClient.Get(ctx, key, internalHost)).To(Succeed())
+				g.Expect(internalHost.Spec.FaaSAdaptorRef).To(Equal(&kdexv1alpha1.KDexObjectReference{
+					Kind:      "KDexFaaSAdaptor",
+					Name:      "knative",
+					Namespace: namespace,
+				}))
+				g.Expect(internalHost.Annotations).To(HaveKeyWithValue(faasAdaptorGenerationAnnotation, "1"))
+			}).Should(Succeed())
+		})
+
+		It("it serves the hosts of a pool from one deployment", func() {
+			for _, name := range []string{"pooled-a", "pooled-b"} {
+				resource := &kdexv1alpha1.KDexHost{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      name,
+						Namespace: namespace,
+						Annotations: map[string]string{
+							hostoptions.PoolAnnotation: "marketing",
+						},
+					},
+					Spec: kdexv1alpha1.KDexHostSpec{
+						BrandName:    "KDex Tech",
+						Organization: "KDex Tech Inc.",
+						Routing: kdexv1alpha1.Routing{
+							Domains: []string{
+								name + ".kdex.dev",
+							},
+						},
+					},
+				}
+
+				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			}
+
+			Eventually(func(g Gomega) {
+				deployment := &appsv1.Deployment{}
+				g.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "kdex-pool-marketing", Namespace: namespace}, deployment)).To(Succeed())
+				g.Expect(deployment.Spec.Template.Spec.Containers[0].Args).To(ContainElements("--focal-host=pooled-a", "--focal-host=pooled-b"))
+				g.Expect(deployment.Spec.Template.Spec.ServiceAccountName).To(Equal("kdex-pool-marketing"))
+				g.Expect(deployment.OwnerReferences).To(HaveLen(2))
+
+				service := &corev1.Service{}
+				g.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "kdex-pool-marketing", Namespace: namespace}, service)).To(Succeed())
+
+				clusterRoleBinding := &rbacv1.ClusterRoleBinding{}
+				g.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "kdex-pool-marketing-" + namespace}, clusterRoleBinding)).To(Succeed())
+				g.Expect(clusterRoleBinding.Subjects).To(ConsistOf(HaveField("Name", "kdex-pool-marketing")))
+
+				for _, name := range []string{"pooled-a", "pooled-b"} {
+					err := k8sClient.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, &appsv1.Deployment{})
+					g.Expect(errors.IsNotFound(err)).To(BeTrue())
+
+					err = k8sClient.Get(ctx, types.NamespacedName{Name: name + "-" + namespace}, &rbacv1.ClusterRoleBinding{})
+					g.Expect(errors.IsNotFound(err)).To(BeTrue())
+				}
+			}).Should(Succeed())
+		})
+
+		It("it layers host overrides over the default deployment template", func() {
+			resource := &kdexv1alpha1.KDexHost{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: namespace,
+				},
+				Spec: kdexv1alpha1.KDexHostSpec{
+					BrandName:    "KDex Tech",
+					Organization: "KDex Tech Inc.",
+					Routing: kdexv1alpha1.Routing{
+						Domains: []string{
+							"kdex.dev",
+						},
+					},
+				},
+			}
+			resource.Spec.Env = []corev1.EnvVar{
+				{Name: "FOO", Value: "bar"},
+			}
+			resource.Spec.Replicas = utils.Ptr(int32(3))
+
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+			assertResourceReady(
+				ctx, k8sClient, resourceName, namespace,
+				&kdexv1alpha1.KDexHost{}, true)
+
+			deployment := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resourceName, Namespace: namespace}, deployment)).To(Succeed())
+			Expect(*deployment.Spec.Replicas).To(Equal(int32(3)))
+			Expect(deployment.Spec.Template.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: "FOO", Value: "bar"}))
+			Expect(deployment.Spec.Template.Spec.Containers[0].LivenessProbe).NotTo(BeNil())
+			Expect(deployment.Spec.Template.Annotations).To(HaveKey(configChecksumKey))
+		})
+
+		It("it applies the host deployment patch", func() {
+			resource := &kdexv1alpha1.KDexHost{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: namespace,
+					Annotations: map[string]string{
+						patch.DeploymentPatchAnnotation: `
+spec:
+  template:
+    spec:
+      nodeSelector:
+        kubernetes.io/os: linux
+      containers:
+      - name: sidecar
+        image: busybox
+`,
+					},
+				},
+				Spec: kdexv1alpha1.KDexHostSpec{
+					BrandName:    "KDex Tech",
+					Organization: "KDex Tech Inc.",
+					Routing: kdexv1alpha1.Routing{
+						Domains: []string{
+							"kdex.dev",
+						},
+					},
+				},
+			}
+
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+			assertResourceReady(
+				ctx, k8sClient, resourceName, namespace,
+				&kdexv1alpha1.KDexHost{}, true)
+
+			deployment := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resourceName, Namespace: namespace}, deployment)).To(Succeed())
+			Expect(deployment.Spec.Template.Spec.NodeSelector).To(HaveKeyWithValue("kubernetes.io/os", "linux"))
+			Expect(deployment.Spec.Template.Spec.Containers).To(HaveLen(2))
+			Expect(deployment.Spec.Template.Spec.Containers[0].Name).To(Equal(resourceName))
+			Expect(deployment.Spec.Template.Spec.Containers[1].Name).To(Equal("sidecar"))
+		})
+
+		It("it manages a horizontal pod autoscaler when autoscaling is enabled", func() {
+			resource := &kdexv1alpha1.KDexHost{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: namespace,
+					Annotations: map[string]string{
+						hostoptions.AutoscalingAnnotation: "{minReplicas: 2, maxReplicas: 4}",
+					},
+				},
+				Spec: kdexv1alpha1.KDexHostSpec{
+					BrandName:    "KDex Tech",
+					Organization: "KDex Tech Inc.",
+					Routing: kdexv1alpha1.Routing{
+						Domains: []string{
+							"kdex.dev",
+						},
+					},
+				},
+			}
+
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+			assertResourceReady(
+				ctx, k8sClient, resourceName, namespace,
+				&kdexv1alpha1.KDexHost{}, true)
+
+			hpa := &autoscalingv2.HorizontalPodAutoscaler{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resourceName, Namespace: namespace}, hpa)).To(Succeed())
+			Expect(*hpa.Spec.MinReplicas).To(Equal(int32(2)))
+			Expect(hpa.Spec.MaxReplicas).To(Equal(int32(4)))
+			Expect(hpa.Spec.ScaleTargetRef.Name).To(Equal(resourceName))
+
+			Eventually(func(g Gomega) {
+				host := &kdexv1alpha1.KDexHost{}
+				g.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resourceName, Namespace: namespace}, host)).To(Succeed())
+				delete(host.Annotations, hostoptions.AutoscalingAnnotation)
+				g.Expect(k8sClient.Update(ctx, host)).To(Succeed())
+			}).Should(Succeed())
+
+			Eventually(func(g Gomega) {
+				err := k8sClient.Get(ctx, types.NamespacedName{Name: resourceName, Namespace: namespace}, &autoscalingv2.HorizontalPodAutoscaler{})
+				g.Expect(errors.IsNotFound(err)).To(BeTrue())
+			}).Should(Succeed())
+		})
+
+		It("it keeps the replicas of a host switched to autoscaling", func() {
+			resource := &kdexv1alpha1.KDexHost{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: namespace,
+				},
+				Spec: kdexv1alpha1.KDexHostSpec{
+					BrandName:    "KDex Tech",
+					Organization: "KDex Tech Inc.",
+					Routing: kdexv1alpha1.Routing{
+						Domains: []string{
+							"kdex.dev",
+						},
+					},
+				},
+			}
+			resource.Spec.Replicas = utils.Ptr(int32(3))
+
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+			assertResourceReady(
+				ctx, k8sClient, resourceName, namespace,
+				&kdexv1alpha1.KDexHost{}, true)
+
+			key := types.NamespacedName{Name: resourceName, Namespace: namespace}
+
+			Eventually(func(g Gomega) {
+				host := &kdexv1alpha1.KDexHost{}
+				g.Expect(k8sClient.Get(ctx, key, host)).To(Succeed())
+				host.Annotations = map[string]string{
+					hostoptions.AutoscalingAnnotation: "{minReplicas: 2, maxReplicas: 4}",
+				}
+				g.Expect(k8sClient.Update(ctx, host)).To(Succeed())
+			}).Should(Succeed())
+
+			Eventually(func(g Gomega) {
+				g.Expect(k8sClient.Get(ctx, key, &autoscalingv2.HorizontalPodAutoscaler{})).To(Succeed())
+			}).Should(Succeed())
+
+			Consistently(func(g Gomega) {
+				deployment := &appsv1.Deployment{}
+				g.Expect(k8sClient.Get(ctx, key, deployment)).To(Succeed())
+				g.Expect(*deployment.Spec.Replicas).To(BeNumerically(">=", 3))
+			}, "3s").Should(Succeed())
+
+			deployment := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, key, deployment)).To(Succeed())
+			Expect(managesField(deployment, fieldManager, "spec", "replicas")).To(BeFalse())
+		})
+
+		It("it manages a pod disruption budget for multi-replica hosts", func() {
+			resource := &kdexv1alpha1.KDexHost{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: namespace,
+				},
+				Spec: kdexv1alpha1.KDexHostSpec{
+					BrandName:    "KDex Tech",
+					Organization: "KDex Tech Inc.",
+					Routing: kdexv1alpha1.Routing{
+						Domains: []string{
+							"kdex.dev",
+						},
+					},
+				},
+			}
+			resource.Spec.Replicas = utils.Ptr(int32(3))
+
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+			assertResourceReady(
+				ctx, k8sClient, resourceName, namespace,
+				&kdexv1alpha1.KDexHost{}, true)
+
+			pdb := &policyv1.PodDisruptionBudget{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resourceName, Namespace: namespace}, pdb)).To(Succeed())
+			Expect(pdb.Spec.MaxUnavailable.IntValue()).To(Equal(1))
+			Expect(pdb.Spec.Selector.MatchLabels).To(HaveKeyWithValue("kdex.dev/instance", resourceName))
+
+			Eventually(func(g Gomega) {
+				host := &kdexv1alpha1.KDexHost{}
+				g.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resourceName, Namespace: namespace}, host)).To(Succeed())
+				host.Spec.Replicas = utils.Ptr(int32(1))
+				g.Expect(k8sClient.Update(ctx, host)).To(Succeed())
+			}).Should(Succeed())
+
+			Eventually(func(g Gomega) {
+				err := k8sClient.Get(ctx, types.NamespacedName{Name: resourceName, Namespace: namespace}, &policyv1.PodDisruptionBudget{})
+				g.Expect(errors.IsNotFound(err)).To(BeTrue())
+			}).Should(Succeed())
+		})
+
+		It("it isolates the host with a network policy", func() {
+			resource := &kdexv1alpha1.KDexHost{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: namespace,
+				},
+				Spec: kdexv1alpha1.KDexHostSpec{
+					BrandName:    "KDex Tech",
+					Organization: "KDex Tech Inc.",
+					Routing: kdexv1alpha1.Routing{
+						Domains: []string{
+							"kdex.dev",
+						},
+					},
+				},
+			}
+			resource.Spec.Registries.NpmRegistry.Host = "verdaccio.registries.svc:4873"
+
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+			assertResourceReady(
+				ctx, k8sClient, resourceName, namespace,
+				&kdexv1alpha1.KDexHost{}, true)
+
+			networkPolicy := &networkingv1.NetworkPolicy{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resourceName, Namespace: namespace}, networkPolicy)).To(Succeed())
+			Expect(networkPolicy.Spec.PodSelector.MatchLabels).To(HaveKeyWithValue("kdex.dev/instance", resourceName))
+			Expect(networkPolicy.Spec.PolicyTypes).To(ConsistOf(networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress))
+			Expect(networkPolicy.Spec.Egress).To(ContainElement(HaveField("To", ContainElement(HaveField(
+				"NamespaceSelector.MatchLabels", HaveKeyWithValue(corev1.LabelMetadataName, "registries"))))))
+			Expect(networkPolicy.Spec.Egress).To(ContainElement(HaveField("To", ContainElement(HaveField(
+				"IPBlock", Not(BeNil()))))))
+			for _, rule := range networkPolicy.Spec.Egress {
+				Expect(rule.To).NotTo(BeEmpty())
+			}
+		})
+
+		It("it binds the service account to the cluster role of the rbac profile", func() {
+			resource := &kdexv1alpha1.KDexHost{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: namespace,
+					Annotations: map[string]string{
+						hostoptions.RBACProfileAnnotation: "pages",
+					},
+				},
+				Spec: kdexv1alpha1.KDexHostSpec{
+					BrandName:    "KDex Tech",
+					Organization: "KDex Tech Inc.",
+					Routing: kdexv1alpha1.Routing{
+						Domains: []string{
+							"kdex.dev",
+						},
+					},
+				},
+			}
+
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+			assertResourceReady(
+				ctx, k8sClient, resourceName, namespace,
+				&kdexv1alpha1.KDexHost{}, true)
+
+			clusterRoleBinding := &rbacv1.ClusterRoleBinding{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resourceName + "-" + namespace}, clusterRoleBinding)).To(Succeed())
+			Expect(clusterRoleBinding.RoleRef.Name).To(Equal("kdex-nexus-host-pages-role"))
+
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resourceName, Namespace: namespace}, resource)).To(Succeed())
+			Expect(resource.Status.Attributes).To(HaveKeyWithValue("rbac.profile", "pages"))
+		})
+
+		It("it grants the cluster-scoped rules of a namespaced rbac profile cluster-wide", func() {
+			profileRole := &rbacv1.ClusterRole{
+				ObjectMeta: metav1.ObjectMeta{Name: "kdex-nexus-host-pages-role"},
+				Rules: []rbacv1.PolicyRule{
+					{
+						APIGroups: []string{"", "kdex.dev"},
+						Resources: []string{"configmaps", "kdexclusterthemes", "kdexthemes"},
+						Verbs:     []string{"get", "list", "watch"},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, profileRole)).To(Succeed())
+			defer func() {
+				Expect(k8sClient.Delete(ctx, profileRole)).To(Succeed())
+			}()
+
+			resource := &kdexv1alpha1.KDexHost{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: namespace,
+					Annotations: map[string]string{
+						hostoptions.RBACProfileAnnotation: "pages",
+						hostoptions.RBACScopeAnnotation:   hostoptions.RBACScopeNamespace,
+					},
+				},
+				Spec: kdexv1alpha1.KDexHostSpec{
+					BrandName:    "KDex Tech",
+					Organization: "KDex Tech Inc.",
+					Routing: kdexv1alpha1.Routing{
+						Domains: []string{
+							"kdex.dev",
+						},
+					},
+				},
+			}
+
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+			assertResourceReady(
+				ctx, k8sClient, resourceName, namespace,
+				&kdexv1alpha1.KDexHost{}, true)
+
+			role := &rbacv1.Role{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resourceName, Namespace: namespace}, role)).To(Succeed())
+			Expect(role.Rules).To(ConsistOf(
+				HaveField("Resources", ConsistOf("configmaps")),
+				HaveField("Resources", ConsistOf("kdexthemes")),
+			))
+
+			clusterRole := &rbacv1.ClusterRole{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resourceName + "-" + namespace}, clusterRole)).To(Succeed())
+			Expect(clusterRole.Rules).To(ConsistOf(HaveField("Resources", ConsistOf("kdexclusterthemes"))))
+
+			clusterRoleBinding := &rbacv1.ClusterRoleBinding{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resourceName + "-" + namespace}, clusterRoleBinding)).To(Succeed())
+			Expect(clusterRoleBinding.RoleRef.Name).To(Equal(clusterRole.Name))
+		})
+
+		It("it issues a certificate for hosts served over https", func() {
+			resource := &kdexv1alpha1.KDexHost{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: namespace,
+				},
+				Spec: kdexv1alpha1.KDexHostSpec{
+					BrandName:    "KDex Tech",
+					Organization: "KDex Tech Inc.",
+					Routing: kdexv1alpha1.Routing{
+						Domains: []string{
+							"kdex.dev",
+						},
+						Scheme: "https",
+					},
+				},
+			}
+
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+			assertResourceReady(
+				ctx, k8sClient, resourceName, namespace,
+				&kdexv1alpha1.KDexHost{}, true)
+
+			secret := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resourceName + "-tls", Namespace: namespace}, secret)).To(Succeed())
+			Expect(secret.Type).To(Equal(corev1.SecretTypeTLS))
+			Expect(secret.Data).To(HaveKey(corev1.TLSCertKey))
+			Expect(secret.Data).To(HaveKey(corev1.TLSPrivateKeyKey))
+
+			host := &kdexv1alpha1.KDexHost{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resourceName, Namespace: namespace}, host)).To(Succeed())
+			Expect(host.Status.Attributes).To(HaveKeyWithValue("certificate.ready", "true"))
+			Expect(host.Status.Attributes).To(HaveKey("certificate.notAfter"))
+
+			internalHost := &kdexv1alpha1.KDexInternalHost{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resourceName, Namespace: namespace}, internalHost)).To(Succeed())
+			Expect(internalHost.Annotations).To(HaveKeyWithValue("kdex.dev/tls-secret", resourceName+"-tls"))
+		})
+
+		It("it only propagates allowed host annotations", func() {
+			resource := &kdexv1alpha1.KDexHost{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: namespace,
+					Annotations: map[string]string{
+						"kubectl.kubernetes.io/last-applied-configuration": "{}",
+						"propagated.kdex.dev/team":                         "web",
+					},
+				},
+				Spec: kdexv1alpha1.KDexHostSpec{
+					BrandName:    "KDex Tech",
+					Organization: "KDex Tech Inc.",
+					Routing: kdexv1alpha1.Routing{
+						Domains: []string{
+							"kdex.dev",
+						},
+					},
+				},
+			}
+
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+			assertResourceReady(
+				ctx, k8sClient, resourceName, namespace,
+				&kdexv1alpha1.KDexHost{}, true)
+
+			key := types.NamespacedName{Name: resourceName, Namespace: namespace}
+
+			for _, obj := range []client.Object{&kdexv1alpha1.KDexInternalHost{}, &corev1.Service{}, &appsv1.Deployment{}} {
+				Expect(k8sClient.Get(ctx, key, obj)).To(Succeed())
+				Expect(obj.GetAnnotations()).To(HaveKeyWithValue("propagated.kdex.dev/team", "web"))
+				Expect(obj.GetAnnotations()).NotTo(HaveKey("kubectl.kubernetes.io/last-applied-configuration"))
+			}
+		})
+
+		It("it reports the deletion stage while downstream objects are pending", func() {
+			resource := &kdexv1alpha1.KDexHost{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: namespace,
+				},
+				Spec: kdexv1alpha1.KDexHostSpec{
+					BrandName:    "KDex Tech",
+					Organization: "KDex Tech Inc.",
+					Routing: kdexv1alpha1.Routing{
+						Domains: []string{
+							"kdex.dev",
+						},
+					},
+				},
+			}
+
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+			assertResourceReady(
+				ctx, k8sClient, resourceName, namespace,
+				&kdexv1alpha1.KDexHost{}, true)
+
+			internalHost := &kdexv1alpha1.KDexInternalHost{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resourceName, Namespace: namespace}, internalHost)).To(Succeed())
+			internalHost.Finalizers = append(internalHost.Finalizers, "kdex.dev/test-finalizer")
+			Expect(k8sClient.Update(ctx, internalHost)).To(Succeed())
+
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+
+			Eventually(func(g Gomega) {
+				g.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resourceName, Namespace: namespace}, resource)).To(Succeed())
+				condition := meta.FindStatusCondition(resource.Status.Conditions, string(ConditionTypeTerminating))
+				g.Expect(condition).NotTo(BeNil())
+				g.Expect(condition.Reason).To(Equal("DeletingKDexInternalHost"))
+				g.Expect(condition.Message).To(ContainSubstring(resourceName))
+			}).Should(Succeed())
+
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resourceName, Namespace: namespace}, internalHost)).To(Succeed())
+			internalHost.Finalizers = nil
+			Expect(k8sClient.Update(ctx, internalHost)).To(Succeed())
+
+			Eventually(func(g Gomega) {
+				err := k8sClient.Get(ctx, types.NamespacedName{Name: resourceName, Namespace: namespace}, resource)
+				g.Expect(errors.IsNotFound(err)).To(BeTrue())
+			}).Should(Succeed())
+		})
+
 		It("it reconciles if theme reference becomes available", func() {
 			resource := &kdexv1alpha1.KDexHost{
 				ObjectMeta: metav1.ObjectMeta{
@@ -408,6 +1272,91 @@ var _ = Describe("KDexHost Controller", func() {
 			Expect(internalUtilityPage.Spec.ContentEntries[0].ContentEntryStatic.RawHTML).To(Equal("<h1>Announcement</h1>"))
 		})
 
+		It("it reconciles the utility pages of its annotations", func() {
+			pageArchetype := &kdexv1alpha1.KDexClusterPageArchetype{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "kdex-default-page-archetype",
+				},
+				Spec: kdexv1alpha1.KDexPageArchetypeSpec{
+					Content: "[[ .Content.main ]]",
+				},
+			}
+
+			Expect(k8sClient.Create(ctx, pageArchetype)).To(Succeed())
+
+			paymentPage := &kdexv1alpha1.KDexUtilityPage{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "payment-required",
+					Namespace: namespace,
+					Annotations: map[string]string{
+						hostoptions.UtilityPageTypeAnnotation: "PaymentRequired",
+					},
+				},
+				Spec: kdexv1alpha1.KDexUtilityPageSpec{
+					ContentEntries: []kdexv1alpha1.ContentEntry{
+						{
+							Slot: "main",
+							ContentEntryStatic: kdexv1alpha1.ContentEntryStatic{
+								RawHTML: "<h1>Payment Required</h1>",
+							},
+						},
+					},
+					PageArchetypeRef: kdexv1alpha1.KDexObjectReference{
+						Kind: "KDexClusterPageArchetype",
+						Name: pageArchetype.Name,
+					},
+					Type: kdexv1alpha1.ErrorUtilityPageType,
+				},
+			}
+
+			Expect(k8sClient.Create(ctx, paymentPage)).To(Succeed())
+
+			assertResourceReady(
+				ctx, k8sClient, paymentPage.Name, namespace,
+				&kdexv1alpha1.KDexUtilityPage{}, true)
+
+			host := &kdexv1alpha1.KDexHost{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: namespace,
+					Annotations: map[string]string{
+						hostoptions.UtilityPagesAnnotation: "{PaymentRequired: {kind: KDexUtilityPage, name: payment-required}}",
+						hostoptions.ErrorPagesAnnotation:   "{'402': PaymentRequired}",
+					},
+				},
+				Spec: kdexv1alpha1.KDexHostSpec{
+					BrandName:    "KDex Tech",
+					Organization: "KDex Tech Inc.",
+					Routing: kdexv1alpha1.Routing{
+						Domains: []string{
+							"kdex.dev",
+						},
+					},
+				},
+			}
+
+			Expect(k8sClient.Create(ctx, host)).To(Succeed())
+
+			key := types.NamespacedName{Name: host.Name, Namespace: namespace}
+			internalPageName := fmt.Sprintf("%s-paymentrequired", host.Name)
+
+			Eventually(func(g Gomega) {
+				checkedHost := &kdexv1alpha1.KDexHost{}
+				g.Expect(k8sClient.Get(ctx, key, checkedHost)).To(Succeed())
+				g.Expect(checkedHost.Status.Attributes).To(HaveKeyWithValue("paymentrequired.utilitypage.generation", "1"))
+
+				internalUtilityPage := &kdexv1alpha1.KDexInternalUtilityPage{}
+				g.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: internalPageName, Namespace: namespace}, internalUtilityPage)).To(Succeed())
+				g.Expect(internalUtilityPage.Spec.Type).To(Equal(kdexv1alpha1.ErrorUtilityPageType))
+				g.Expect(internalUtilityPage.Annotations).To(HaveKeyWithValue(hostoptions.UtilityPageTypeAnnotation, "PaymentRequired"))
+
+				internalHost := &kdexv1alpha1.KDexInternalHost{}
+				g.Expect(k8sClient.Get(ctx, key, internalHost)).To(Succeed())
+				g.Expect(internalHost.Annotations).To(HaveKeyWithValue(utilityPageRefsAnnotation, `{"PaymentRequired":"`+internalPageName+`"}`))
+				g.Expect(internalHost.Annotations).To(HaveKeyWithValue(errorPageRefsAnnotation, `{"402":"`+internalPageName+`"}`))
+			}).Should(Succeed())
+		})
+
 		It("it reconciles a referenced translation", func() {
 			host := &kdexv1alpha1.KDexHost{
 				ObjectMeta: metav1.ObjectMeta{
@@ -472,7 +1421,7 @@ var _ = Describe("KDexHost Controller", func() {
 
 			internalTranslation := &kdexv1alpha1.KDexInternalTranslation{}
 			err := k8sClient.Get(ctx, types.NamespacedName{
-				Name:      fmt.Sprintf("%s-%s", host.Name, translation.Name),
+				Name:      host.Name + "-translations",
 				Namespace: namespace,
 			}, internalTranslation)
 			Expect(err).NotTo(HaveOccurred())
@@ -481,6 +1430,87 @@ var _ = Describe("KDexHost Controller", func() {
 			Expect(internalTranslation.Spec.Translations[0].KeysAndValues).To(HaveLen(2))
 			Expect(internalTranslation.Spec.Translations[0].KeysAndValues["brandName"]).To(Equal("KDex Tech"))
 			Expect(internalTranslation.Spec.Translations[0].KeysAndValues["organization"]).To(Equal("KDex Tech Inc."))
+			Expect(internalTranslation.Annotations[translationOriginsAnnotation]).To(Equal(internalTranslation.Name + translationOriginsSuffix))
+
+			origins := &corev1.ConfigMap{}
+			Eventually(func() error {
+				return k8sClient.Get(ctx, types.NamespacedName{
+					Name:      internalTranslation.Name + translationOriginsSuffix,
+					Namespace: namespace,
+				}, origins)
+			}).Should(Succeed())
+			Expect(origins.Data).To(HaveKeyWithValue("en", `{"non-existent-translation/en":["brandName","organization"]}`))
+			Expect(metav1.IsControlledBy(origins, internalTranslation)).To(BeTrue())
+			Expect(checkedHost.Status.Attributes["en"+translationSourcesSuffix]).To(Equal("non-existent-translation/en=2"))
+		})
+
+		It("it deletes the internal translation of a removed translation reference", func() {
+			translation := &kdexv1alpha1.KDexTranslation{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "removed-translation",
+					Namespace: namespace,
+				},
+				Spec: kdexv1alpha1.KDexTranslationSpec{
+					Translations: []kdexv1alpha1.Translation{
+						{
+							Lang: "en",
+							KeysAndValues: map[string]string{
+								"brandName": "KDex Tech",
+							},
+						},
+					},
+				},
+			}
+
+			Expect(k8sClient.Create(ctx, translation)).To(Succeed())
+
+			host := &kdexv1alpha1.KDexHost{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: namespace,
+				},
+				Spec: kdexv1alpha1.KDexHostSpec{
+					BrandName:    "KDex Tech",
+					Organization: "KDex Tech Inc.",
+					Routing: kdexv1alpha1.Routing{
+						Domains: []string{
+							"kdex.dev",
+						},
+					},
+					TranslationRefs: []kdexv1alpha1.KDexObjectReference{
+						{
+							Kind: "KDexTranslation",
+							Name: translation.Name,
+						},
+					},
+				},
+			}
+
+			Expect(k8sClient.Create(ctx, host)).To(Succeed())
+
+			checkedHost := &kdexv1alpha1.KDexHost{}
+			assertResourceReady(
+				ctx, k8sClient, host.Name, namespace,
+				checkedHost, true)
+
+			internalTranslationName := types.NamespacedName{
+				Name:      host.Name + "-translations",
+				Namespace: namespace,
+			}
+			Expect(k8sClient.Get(ctx, internalTranslationName, &kdexv1alpha1.KDexInternalTranslation{})).To(Succeed())
+
+			checkedHost.Spec.TranslationRefs = nil
+			Expect(k8sClient.Update(ctx, checkedHost)).To(Succeed())
+
+			Eventually(func(g Gomega) {
+				err := k8sClient.Get(ctx, internalTranslationName, &kdexv1alpha1.KDexInternalTranslation{})
+				g.Expect(errors.IsNotFound(err)).To(BeTrue())
+
+				g.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: host.Name, Namespace: namespace}, checkedHost)).To(Succeed())
+				g.Expect(checkedHost.Status.Attributes).NotTo(HaveKey(translation.Name + ".translation.generation"))
+			}).Should(Succeed())
+
+			Expect(k8sClient.Delete(ctx, translation)).To(Succeed())
 		})
 
 		It("it reconciles a default translation if a default is available", func() {
@@ -540,7 +1570,7 @@ var _ = Describe("KDexHost Controller", func() {
 
 			internalTranslation := &kdexv1alpha1.KDexInternalTranslation{}
 			err := k8sClient.Get(ctx, types.NamespacedName{
-				Name:      fmt.Sprintf("%s-%s", host.Name, translation.Name),
+				Name:      host.Name + "-translations",
 				Namespace: namespace,
 			}, internalTranslation)
 			Expect(err).NotTo(HaveOccurred())
@@ -20,48 +20,63 @@ import (
 	"context"
 	"fmt"
 	"maps"
+	"slices"
 	"strings"
 
+	"github.com/kdex-tech/nexus-manager/internal/hostoptions"
+	"github.com/kdex-tech/nexus-manager/internal/translations"
 	"github.com/kdex-tech/nexus-manager/internal/webhook"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kdexv1alpha1 "kdex.dev/crds/api/v1alpha1"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
+const (
+	translationGenerationSuffix = ".translation.generation"
+	translationSourcesSuffix    = ".translation.sources"
+	utilityPageGenerationSuffix = ".utilitypage.generation"
+
+	// translationOriginsAnnotation names on a KDexInternalTranslation the ConfigMap recording the source and language of
+	// each of its keys, an annotation being too small to hold them.
+	translationOriginsAnnotation = "kdex.dev/translation-origins"
+	translationOriginsSuffix     = "-origins"
+
+	// The limits of a KDexTranslationSpec.
+	maxTranslationKeys  = 256
+	maxTranslationLangs = 32
+)
+
 func (r *KDexHostReconciler) createOrUpdateInternalTranslation(
 	ctx context.Context,
 	translationSpec kdexv1alpha1.KDexTranslationSpec,
 	translationName string,
 	generation int64,
+	origins map[string]map[string]string,
 	host *kdexv1alpha1.KDexHost,
 ) (*kdexv1alpha1.KDexInternalTranslation, error) {
 	name := fmt.Sprintf("%s-%s", host.Name, translationName)
 	internalTranslation := &kdexv1alpha1.KDexInternalTranslation{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      name,
-			Namespace: host.Namespace,
-		},
+		ObjectMeta: r.hostOwnedObjectMeta(host, name, host.Namespace),
 	}
 
-	op, err := ctrl.CreateOrUpdate(ctx, r.Client, internalTranslation, func() error {
-		if internalTranslation.CreationTimestamp.IsZero() {
-			internalTranslation.Annotations = make(map[string]string)
-			maps.Copy(internalTranslation.Annotations, host.Annotations)
-			internalTranslation.Labels = make(map[string]string)
-			maps.Copy(internalTranslation.Labels, host.Labels)
-
-			internalTranslation.Labels["app.kubernetes.io/name"] = kdexWeb
-			internalTranslation.Labels["kdex.dev/instance"] = host.Name
-		}
+	internalTranslation.Annotations[translationOriginsAnnotation] = name + translationOriginsSuffix
+	internalTranslation.Labels["kdex.dev/generation"] = fmt.Sprintf("%d", generation)
+	internalTranslation.Spec.KDexTranslationSpec = translationSpec
+	internalTranslation.Spec.HostRef = corev1.LocalObjectReference{Name: host.Name}
 
-		internalTranslation.Labels["kdex.dev/generation"] = fmt.Sprintf("%d", generation)
-		internalTranslation.Spec.KDexTranslationSpec = translationSpec
-		internalTranslation.Spec.HostRef = corev1.LocalObjectReference{Name: host.Name}
-
-		return ctrl.SetControllerReference(host, internalTranslation, r.Scheme)
-	})
+	err := ctrl.SetControllerReference(host, internalTranslation, r.Scheme)
+	op := controllerutil.OperationResultNone
+	if err == nil {
+		op, err = r.applyOwned(ctx, host, internalTranslation)
+	}
+	if err == nil {
+		err = r.applyTranslationOrigins(ctx, host, internalTranslation, origins)
+	}
 
 	log := logf.FromContext(ctx)
 
@@ -89,6 +104,33 @@ func (r *KDexHostReconciler) createOrUpdateInternalTranslation(
 	return internalTranslation, nil
 }
 
+// applyTranslationOrigins records the origin of each key of the internal translation in a ConfigMap it controls, so
+// that the ConfigMap goes with it.
+func (r *KDexHostReconciler) applyTranslationOrigins(
+	ctx context.Context,
+	host *kdexv1alpha1.KDexHost,
+	internalTranslation *kdexv1alpha1.KDexInternalTranslation,
+	origins map[string]map[string]string,
+) error {
+	index, err := translations.OriginIndex(internalTranslation.Spec.Translations, origins, translations.MaxOriginIndexSize)
+	if err != nil {
+		return err
+	}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: r.hostOwnedObjectMeta(host, internalTranslation.Name+translationOriginsSuffix, host.Namespace),
+		Data:       index,
+	}
+
+	if err := ctrl.SetControllerReference(internalTranslation, configMap, r.Scheme); err != nil {
+		return err
+	}
+
+	_, err = r.applyOwned(ctx, host, configMap)
+
+	return err
+}
+
 func (r *KDexHostReconciler) createOrUpdateInternalUtilityPage(
 	ctx context.Context,
 	host *kdexv1alpha1.KDexHost,
@@ -98,30 +140,22 @@ func (r *KDexHostReconciler) createOrUpdateInternalUtilityPage(
 ) (*corev1.LocalObjectReference, error) {
 	name := fmt.Sprintf("%s-%s", host.Name, strings.ToLower(string(pageType)))
 	internalUtilityPage := &kdexv1alpha1.KDexInternalUtilityPage{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      name,
-			Namespace: host.Namespace,
-		},
+		ObjectMeta: r.hostOwnedObjectMeta(host, name, host.Namespace),
 	}
 
-	op, err := ctrl.CreateOrUpdate(ctx, r.Client, internalUtilityPage, func() error {
-		if internalUtilityPage.CreationTimestamp.IsZero() {
-			internalUtilityPage.Annotations = make(map[string]string)
-			maps.Copy(internalUtilityPage.Annotations, host.Annotations)
-			internalUtilityPage.Labels = make(map[string]string)
-			maps.Copy(internalUtilityPage.Labels, host.Labels)
-
-			internalUtilityPage.Labels["app.kubernetes.io/name"] = kdexWeb
-			internalUtilityPage.Labels["kdex.dev/instance"] = host.Name
-			internalUtilityPage.Labels["kdex.dev/utility-page-type"] = string(pageType)
-		}
-
-		internalUtilityPage.Labels["kdex.dev/generation"] = fmt.Sprintf("%d", utilityPageGeneration)
-		internalUtilityPage.Spec.KDexUtilityPageSpec = utilityPageSpec
-		internalUtilityPage.Spec.HostRef = corev1.LocalObjectReference{Name: host.Name}
+	internalUtilityPage.Labels["kdex.dev/generation"] = fmt.Sprintf("%d", utilityPageGeneration)
+	internalUtilityPage.Labels["kdex.dev/utility-page-type"] = string(utilityPageSpec.Type)
+	if pageType != utilityPageSpec.Type {
+		internalUtilityPage.Annotations[hostoptions.UtilityPageTypeAnnotation] = string(pageType)
+	}
+	internalUtilityPage.Spec.KDexUtilityPageSpec = utilityPageSpec
+	internalUtilityPage.Spec.HostRef = corev1.LocalObjectReference{Name: host.Name}
 
-		return ctrl.SetControllerReference(host, internalUtilityPage, r.Scheme)
-	})
+	err := ctrl.SetControllerReference(host, internalUtilityPage, r.Scheme)
+	op := controllerutil.OperationResultNone
+	if err == nil {
+		op, err = r.applyOwned(ctx, host, internalUtilityPage)
+	}
 
 	log := logf.FromContext(ctx)
 
@@ -140,11 +174,17 @@ func (r *KDexHostReconciler) createOrUpdateInternalUtilityPage(
 	return &corev1.LocalObjectReference{Name: name}, nil
 }
 
+// resolveTranslations merges the translations referenced by the host, the first reference taking precedence, over
+// the default translation unless the host opts out of it. Missing keys are filled in along the fallback chain of each
+// language. The merged set is written to as few KDexInternalTranslations as their size limits allow, and the host
+// status records, for each language, the sources which supplied its keys.
 func (r *KDexHostReconciler) resolveTranslations(
 	ctx context.Context,
 	host *kdexv1alpha1.KDexHost,
 ) ([]corev1.LocalObjectReference, bool, error) {
-	refs := []corev1.LocalObjectReference{}
+	settings := r.getExtensions().HostDefault.Translation.WithDefaults()
+	sources := []translations.Source{}
+	generationKeys := map[string]bool{}
 
 	for _, translationRef := range host.Spec.TranslationRefs {
 		resolvedObj, shouldReturn, _, err := ResolveKDexObjectReference(ctx, r.Client, host, &host.Status.Conditions, &translationRef, r.RequeueDelay)
@@ -153,84 +193,113 @@ func (r *KDexHostReconciler) resolveTranslations(
 		}
 
 		if resolvedObj != nil {
-			var spec kdexv1alpha1.KDexTranslationSpec
-			switch v := resolvedObj.(type) {
-			case *kdexv1alpha1.KDexTranslation:
-				spec = v.Spec
-			case *kdexv1alpha1.KDexClusterTranslation:
-				spec = v.Spec
-			}
+			sources = append(sources, translationSource(resolvedObj))
 
-			internalTranslation, err := r.createOrUpdateInternalTranslation(ctx, spec, resolvedObj.GetName(), resolvedObj.GetGeneration(), host)
-			if err != nil {
-				return nil, true, err
-			}
-			refs = append(refs, corev1.LocalObjectReference{Name: internalTranslation.Name})
-
-			host.Status.Attributes[translationRef.Name+".translation.generation"] = fmt.Sprintf("%d", resolvedObj.GetGeneration())
+			host.Status.Attributes[translationRef.Name+translationGenerationSuffix] = fmt.Sprintf("%d", resolvedObj.GetGeneration())
+			generationKeys[translationRef.Name+translationGenerationSuffix] = true
 		}
 	}
 
-	defaultTranslationRef := kdexv1alpha1.KDexObjectReference{
-		Name: "kdex-default-translation",
-		Kind: "KDexClusterTranslation",
-	}
+	if hostoptions.UsesDefaultTranslation(host.Annotations) {
+		defaultTranslationRef := kdexv1alpha1.KDexObjectReference{
+			Name: settings.Default,
+			Kind: "KDexClusterTranslation",
+		}
 
-	defaultResolvedObj, _, _, err := ResolveKDexObjectReference(ctx, r.Client, host, &host.Status.Conditions, &defaultTranslationRef, r.RequeueDelay)
-	if err != nil {
-		return nil, false, err
+		defaultResolvedObj, _, _, err := ResolveKDexObjectReference(ctx, r.Client, host, &host.Status.Conditions, &defaultTranslationRef, r.RequeueDelay)
+		if err != nil {
+			return nil, false, err
+		}
+
+		if defaultResolvedObj != nil {
+			sources = append(sources, translationSource(defaultResolvedObj))
+
+			host.Status.Attributes[defaultTranslationRef.Name+translationGenerationSuffix] = fmt.Sprintf("%d", defaultResolvedObj.GetGeneration())
+			generationKeys[defaultTranslationRef.Name+translationGenerationSuffix] = true
+		}
 	}
 
-	if defaultResolvedObj != nil {
-		var spec kdexv1alpha1.KDexTranslationSpec
-		switch v := defaultResolvedObj.(type) {
-		case *kdexv1alpha1.KDexTranslation:
-			spec = v.Spec
-		case *kdexv1alpha1.KDexClusterTranslation:
-			spec = v.Spec
+	merged := translations.Merge(sources, slices.Collect(maps.Keys(settings.Fallbacks)), func(lang string) []string {
+		return translations.Chain(lang, settings.Fallbacks, host.Spec.DefaultLang)
+	})
+
+	refs := []corev1.LocalObjectReference{}
+	for i, part := range translations.Split(merged.Translations, maxTranslationLangs, maxTranslationKeys) {
+		name := "translations"
+		if i > 0 {
+			name = fmt.Sprintf("translations-%d", i+1)
 		}
 
-		internalTranslation, err := r.createOrUpdateInternalTranslation(ctx, spec, defaultResolvedObj.GetName(), defaultResolvedObj.GetGeneration(), host)
+		spec := kdexv1alpha1.KDexTranslationSpec{Translations: part}
+		internalTranslation, err := r.createOrUpdateInternalTranslation(ctx, spec, name, host.Generation, merged.Origins, host)
 		if err != nil {
 			return nil, true, err
 		}
 		refs = append(refs, corev1.LocalObjectReference{Name: internalTranslation.Name})
+	}
 
-		host.Status.Attributes[defaultTranslationRef.Name+".translation.generation"] = fmt.Sprintf("%d", defaultResolvedObj.GetGeneration())
+	sourceKeys := map[string]bool{}
+	for lang, origins := range merged.Origins {
+		host.Status.Attributes[lang+translationSourcesSuffix] = translations.Summary(origins)
+		sourceKeys[lang+translationSourcesSuffix] = true
 	}
 
+	if err := r.deleteStaleInternalObjects(ctx, host, &kdexv1alpha1.KDexInternalTranslationList{}, refs); err != nil {
+		return nil, true, err
+	}
+	pruneGenerationAttributes(host, translationGenerationSuffix, generationKeys)
+	pruneGenerationAttributes(host, translationSourcesSuffix, sourceKeys)
+
 	return refs, false, nil
 }
 
+// translationSource returns the translations of a resolved KDexTranslation or KDexClusterTranslation.
+func translationSource(obj client.Object) translations.Source {
+	source := translations.Source{Name: obj.GetName()}
+	switch v := obj.(type) {
+	case *kdexv1alpha1.KDexTranslation:
+		source.Translations = v.Spec.Translations
+	case *kdexv1alpha1.KDexClusterTranslation:
+		source.Translations = v.Spec.Translations
+	}
+	return source
+}
+
+// resolveUtilityPages creates the internal utility pages of the host: those of spec.utilityPages, those of the utility
+// pages annotation and, while a maintenance is configured, the maintenance page. It returns the internal utility page
+// of each utility page type.
+//
 //nolint:gocyclo
 func (r *KDexHostReconciler) resolveUtilityPages(
 	ctx context.Context,
 	host *kdexv1alpha1.KDexHost,
-) (*corev1.LocalObjectReference, *corev1.LocalObjectReference, *corev1.LocalObjectReference, bool, error) {
+) (map[kdexv1alpha1.KDexUtilityPageType]*corev1.LocalObjectReference, bool, error) {
 	refs := map[kdexv1alpha1.KDexUtilityPageType]*corev1.LocalObjectReference{}
+	generationKeys := map[string]bool{}
+
+	pages, err := r.annotatedUtilityPages(host)
+	if err != nil {
+		return nil, true, err
+	}
+	if _, err := hostoptions.GetErrorPages(host.Annotations); err != nil {
+		return nil, true, err
+	}
 
-	types := []kdexv1alpha1.KDexUtilityPageType{
-		kdexv1alpha1.AnnouncementUtilityPageType,
-		kdexv1alpha1.ErrorUtilityPageType,
-		kdexv1alpha1.LoginUtilityPageType,
+	if host.Spec.UtilityPages != nil {
+		pages[kdexv1alpha1.AnnouncementUtilityPageType] = host.Spec.UtilityPages.AnnouncementRef
+		pages[kdexv1alpha1.ErrorUtilityPageType] = host.Spec.UtilityPages.ErrorRef
+		pages[kdexv1alpha1.LoginUtilityPageType] = host.Spec.UtilityPages.LoginRef
 	}
 
-	for _, pageType := range types {
-		var ref *kdexv1alpha1.KDexObjectReference
-		if host.Spec.UtilityPages != nil {
-			switch pageType {
-			case kdexv1alpha1.AnnouncementUtilityPageType:
-				ref = host.Spec.UtilityPages.AnnouncementRef
-			case kdexv1alpha1.ErrorUtilityPageType:
-				ref = host.Spec.UtilityPages.ErrorRef
-			case kdexv1alpha1.LoginUtilityPageType:
-				ref = host.Spec.UtilityPages.LoginRef
-			}
+	for _, pageType := range slices.Sorted(maps.Keys(pages)) {
+		ref := pages[pageType]
+		if ref == nil {
+			continue
 		}
 
 		resolvedObj, shouldReturn, _, err := ResolveKDexObjectReference(ctx, r.Client, host, &host.Status.Conditions, ref, r.RequeueDelay)
 		if shouldReturn && !isDefaultUtilityPage(ref) {
-			return nil, nil, nil, true, err
+			return nil, true, err
 		}
 
 		if resolvedObj != nil {
@@ -242,26 +311,83 @@ func (r *KDexHostReconciler) resolveUtilityPages(
 				spec = v.Spec
 			}
 
-			// Validate Type matches
-			if spec.Type != pageType {
-				return nil, nil, nil, true, fmt.Errorf("utility page type %s does not match requested type %s", spec.Type, pageType)
+			// The maintenance page may be of any type.
+			resolvedType := hostoptions.GetUtilityPageType(resolvedObj.GetAnnotations(), spec.Type)
+			if pageType != hostoptions.MaintenanceUtilityPageType && resolvedType != pageType {
+				return nil, true, fmt.Errorf("utility page type %s does not match requested type %s", resolvedType, pageType)
 			}
 
 			internalRef, err := r.createOrUpdateInternalUtilityPage(ctx, host, spec, pageType, resolvedObj.GetGeneration())
 			if err != nil {
-				return nil, nil, nil, true, err
+				return nil, true, err
 			}
 			refs[pageType] = internalRef
 
-			host.Status.Attributes[strings.ToLower(string(pageType))+".utilitypage.generation"] = fmt.Sprintf("%d", resolvedObj.GetGeneration())
+			host.Status.Attributes[strings.ToLower(string(pageType))+utilityPageGenerationSuffix] = fmt.Sprintf("%d", resolvedObj.GetGeneration())
+			generationKeys[strings.ToLower(string(pageType))+utilityPageGenerationSuffix] = true
 		}
 	}
 
-	return refs[kdexv1alpha1.AnnouncementUtilityPageType], refs[kdexv1alpha1.ErrorUtilityPageType], refs[kdexv1alpha1.LoginUtilityPageType], false, nil
+	keep := []corev1.LocalObjectReference{}
+	for _, ref := range refs {
+		keep = append(keep, *ref)
+	}
+	if err := r.deleteStaleInternalObjects(ctx, host, &kdexv1alpha1.KDexInternalUtilityPageList{}, keep); err != nil {
+		return nil, true, err
+	}
+	pruneGenerationAttributes(host, utilityPageGenerationSuffix, generationKeys)
+
+	return refs, false, nil
+}
+
+// deleteStaleInternalObjects deletes the internal objects of the list's kind which the host controls but no longer
+// produces, such as the KDexInternalTranslation of a translation removed from the host spec.
+func (r *KDexHostReconciler) deleteStaleInternalObjects(
+	ctx context.Context,
+	host *kdexv1alpha1.KDexHost,
+	list client.ObjectList,
+	keep []corev1.LocalObjectReference,
+) error {
+	if err := r.List(ctx, list, client.InNamespace(host.Namespace), client.MatchingFields{hostIndexKey: host.Name}); err != nil {
+		return err
+	}
+
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return err
+	}
+
+	log := logf.FromContext(ctx)
+
+	for _, item := range items {
+		obj, ok := item.(client.Object)
+		if !ok || !metav1.IsControlledBy(obj, host) || !obj.GetDeletionTimestamp().IsZero() {
+			continue
+		}
+		if slices.Contains(keep, corev1.LocalObjectReference{Name: obj.GetName()}) {
+			continue
+		}
+
+		if err := r.Delete(ctx, obj); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+
+		log.V(2).Info("deleteStaleInternalObjects", "kind", fmt.Sprintf("%T", obj), "name", obj.GetName())
+	}
+
+	return nil
+}
+
+// pruneGenerationAttributes removes the host status attributes ending in suffix which were not recorded by the
+// current reconcile.
+func pruneGenerationAttributes(host *kdexv1alpha1.KDexHost, suffix string, keep map[string]bool) {
+	for key := range host.Status.Attributes {
+		if strings.HasSuffix(key, suffix) && !keep[key] {
+			delete(host.Status.Attributes, key)
+		}
+	}
 }
 
 func isDefaultUtilityPage(ref *kdexv1alpha1.KDexObjectReference) bool {
-	return ref.Name == webhook.KDexDefaultUtilityPageAnnouncement ||
-		ref.Name == webhook.KDexDefaultUtilityPageError ||
-		ref.Name == webhook.KDexDefaultUtilityPageLogin
+	return slices.Contains(slices.Collect(maps.Values(webhook.DefaultUtilityPages)), ref.Name)
 }
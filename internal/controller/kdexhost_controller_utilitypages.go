@@ -0,0 +1,130 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"slices"
+	"strconv"
+
+	"github.com/kdex-tech/nexus-manager/internal/hostoptions"
+	"github.com/kdex-tech/nexus-manager/internal/webhook"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	kdexv1alpha1 "kdex.dev/crds/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// The annotations of the KDexInternalHost which name the internal utility pages beyond those of its spec, keyed by
+	// utility page type, and the internal utility page served for each HTTP error status.
+	errorPageRefsAnnotation   = "kdex.dev/error-page-refs"
+	utilityPageRefsAnnotation = "kdex.dev/utility-page-refs"
+)
+
+// annotatedUtilityPages returns the utility pages of the host beyond those of spec.utilityPages, with defaults
+// applied. The maintenance page is only included while a maintenance is configured.
+func (r *KDexHostReconciler) annotatedUtilityPages(host *kdexv1alpha1.KDexHost) (map[kdexv1alpha1.KDexUtilityPageType]*kdexv1alpha1.KDexObjectReference, error) {
+	pages, err := hostoptions.GetUtilityPages(host.Annotations)
+	if err != nil {
+		return nil, err
+	}
+
+	maintenance, err := r.getMaintenance(host)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, pageType := range hostoptions.SpecUtilityPageTypes {
+		delete(pages, pageType)
+	}
+
+	webhook.DefaultUtilityPageRefs(pages)
+
+	switch {
+	case maintenance == nil:
+		delete(pages, hostoptions.MaintenanceUtilityPageType)
+	case maintenance.PageRef != nil:
+		pages[hostoptions.MaintenanceUtilityPageType] = maintenance.PageRef
+	case pages[hostoptions.MaintenanceUtilityPageType] == nil:
+		pages[hostoptions.MaintenanceUtilityPageType] = &kdexv1alpha1.KDexObjectReference{
+			Kind: webhook.KDexClusterUtilityPage,
+			Name: webhook.KDexDefaultUtilityPageMaintenance,
+		}
+	}
+
+	return pages, nil
+}
+
+// utilityPageAnnotations returns the annotations which give the internal host the internal utility pages of refs
+// beyond those of its spec and the page served for each HTTP error status. Statuses whose page could not be resolved
+// are left to the error page.
+func utilityPageAnnotations(
+	host *kdexv1alpha1.KDexHost,
+	refs map[kdexv1alpha1.KDexUtilityPageType]*corev1.LocalObjectReference,
+) (map[string]string, error) {
+	pages := map[kdexv1alpha1.KDexUtilityPageType]string{}
+	for pageType, ref := range refs {
+		if !slices.Contains(hostoptions.SpecUtilityPageTypes, pageType) && pageType != hostoptions.MaintenanceUtilityPageType {
+			pages[pageType] = ref.Name
+		}
+	}
+
+	errorPages, err := hostoptions.GetErrorPages(host.Annotations)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := map[string]string{}
+	for status, pageType := range errorPages {
+		if ref := refs[pageType]; ref != nil {
+			statuses[strconv.Itoa(status)] = ref.Name
+		}
+	}
+
+	annotations := map[string]string{}
+	for key, value := range map[string]any{utilityPageRefsAnnotation: pages, errorPageRefsAnnotation: statuses} {
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		annotations[key] = string(raw)
+	}
+
+	return annotations, nil
+}
+
+// utilityPageHosts maps a KDexUtilityPage or KDexClusterUtilityPage to the hosts which reference it from their
+// annotations. References from spec.utilityPages are watched by reference path.
+func (r *KDexHostReconciler) utilityPageHosts(ctx context.Context, obj client.Object) []reconcile.Request {
+	kind := webhook.KDexUtilityPage
+	if _, ok := obj.(*kdexv1alpha1.KDexClusterUtilityPage); ok {
+		kind = webhook.KDexClusterUtilityPage
+	}
+
+	var hosts kdexv1alpha1.KDexHostList
+	if err := r.List(ctx, &hosts, client.InNamespace(obj.GetNamespace())); err != nil {
+		logf.FromContext(ctx).Error(err, "failed to list hosts")
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, host := range hosts.Items {
+		pages, err := r.annotatedUtilityPages(&host)
+		if err != nil {
+			continue
+		}
+
+		if slices.ContainsFunc(slices.Collect(maps.Values(pages)), func(ref *kdexv1alpha1.KDexObjectReference) bool {
+			return ref != nil && ref.Kind == kind && ref.Name == obj.GetName()
+		}) {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: host.Name, Namespace: host.Namespace},
+			})
+		}
+	}
+
+	return requests
+}
@@ -3,6 +3,7 @@ package controller
 import (
 	"context"
 
+	"github.com/kdex-tech/nexus-manager/internal/hostoptions"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -43,6 +44,9 @@ var _ = Describe("KDexHost Defaulter", func() {
 			Expect(createdResource.Spec.DefaultLang).To(Equal("en"))
 			Expect(createdResource.Spec.ModulePolicy).To(Equal(kdexv1alpha1.StrictModulePolicy))
 			Expect(createdResource.Spec.IngressPath).To(Equal("/-/host"))
+			Expect(createdResource.Spec.UtilityPages.ErrorRef.Name).To(Equal("kdex-default-utility-page-error"))
+			Expect(createdResource.Annotations[hostoptions.UtilityPagesAnnotation]).To(ContainSubstring(
+				`"NotFound":{"name":"kdex-default-utility-page-not-found","kind":"KDexClusterUtilityPage"}`))
 		})
 
 		It("should not overwrite fields if present except ingressPath", func() {
@@ -7,6 +7,7 @@ import (
 	. "github.com/onsi/gomega"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kdexv1alpha1 "kdex.dev/crds/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 var _ = Describe("KDexHost Validator", func() {
@@ -205,5 +206,51 @@ var _ = Describe("KDexHost Validator", func() {
 			err := k8sClient.Create(ctx, resource)
 			Expect(err).NotTo(HaveOccurred())
 		})
+
+		It("should fail with a domain covered by another host's wildcard", func() {
+			existing := &kdexv1alpha1.KDexHost{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-resource",
+					Namespace: namespace,
+				},
+				Spec: kdexv1alpha1.KDexHostSpec{
+					BrandName:    "valid",
+					Organization: "valid",
+					Routing: kdexv1alpha1.Routing{
+						Domains: []string{
+							"*.kdex.dev",
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, existing)).To(Succeed())
+
+			// Once reconciled the host is known to the cache the webhook looks conflicts up in.
+			Eventually(func(g Gomega) {
+				host := &kdexv1alpha1.KDexHost{}
+				g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(existing), host)).To(Succeed())
+				g.Expect(host.Finalizers).NotTo(BeEmpty())
+			}).Should(Succeed())
+
+			resource := &kdexv1alpha1.KDexHost{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-resource-conflict",
+					Namespace: namespace,
+				},
+				Spec: kdexv1alpha1.KDexHostSpec{
+					BrandName:    "valid",
+					Organization: "valid",
+					Routing: kdexv1alpha1.Routing{
+						Domains: []string{
+							"shop.kdex.dev",
+						},
+					},
+				},
+			}
+
+			err := k8sClient.Create(ctx, resource)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(`domain "shop.kdex.dev" overlaps "*.kdex.dev"`))
+		})
 	})
 })
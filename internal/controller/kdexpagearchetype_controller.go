@@ -19,6 +19,7 @@ package controller
 import (
 	"context"
 	"fmt"
+	"slices"
 	"time"
 
 	"os"
@@ -26,6 +27,7 @@ import (
 	nexuswebhook "github.com/kdex-tech/nexus-manager/internal/webhook"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/events"
 	kdexv1alpha1 "kdex.dev/crds/api/v1alpha1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -39,6 +41,7 @@ import (
 type KDexPageArchetypeReconciler struct {
 	client.Client
 	Scheme       *runtime.Scheme
+	Recorder     events.EventRecorder
 	RequeueDelay time.Duration
 }
 
@@ -47,7 +50,6 @@ func (r *KDexPageArchetypeReconciler) Reconcile(ctx context.Context, req ctrl.Re
 
 	var status *kdexv1alpha1.KDexObjectStatus
 	var spec kdexv1alpha1.KDexPageArchetypeSpec
-	var om metav1.ObjectMeta
 	var o client.Object
 
 	if req.Namespace == "" {
@@ -57,7 +59,6 @@ func (r *KDexPageArchetypeReconciler) Reconcile(ctx context.Context, req ctrl.Re
 		}
 		status = &clusterPageArchetype.Status
 		spec = clusterPageArchetype.Spec
-		om = clusterPageArchetype.ObjectMeta
 		o = &clusterPageArchetype
 	} else {
 		var pageArchetype kdexv1alpha1.KDexPageArchetype
@@ -66,7 +67,6 @@ func (r *KDexPageArchetypeReconciler) Reconcile(ctx context.Context, req ctrl.Re
 		}
 		status = &pageArchetype.Status
 		spec = pageArchetype.Spec
-		om = pageArchetype.ObjectMeta
 		o = &pageArchetype
 	}
 
@@ -74,13 +74,17 @@ func (r *KDexPageArchetypeReconciler) Reconcile(ctx context.Context, req ctrl.Re
 		status.Attributes = make(map[string]string)
 	}
 
+	base := o.DeepCopyObject().(client.Object)
+	previousConditions := slices.Clone(status.Conditions)
+
+	if isPaused(o) {
+		return ctrl.Result{}, pauseReconcile(ctx, r.Client, r.Recorder, base, o, status)
+	}
+	resumeReconcile(r.Recorder, o, status)
+
 	// Defer status update
 	defer func() {
-		status.ObservedGeneration = om.Generation
-		if updateErr := r.Status().Update(ctx, o); updateErr != nil {
-			err = updateErr
-			res = ctrl.Result{}
-		}
+		res, err = finishReconcile(ctx, r.Client, r.Recorder, base, o, previousConditions, res, err)
 
 		log.V(2).Info("status", "status", status, "err", err, "res", res)
 	}()
@@ -153,7 +157,7 @@ func (r *KDexPageArchetypeReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	if os.Getenv("ENABLE_WEBHOOKS") != FALSE {
 		err := ctrl.NewWebhookManagedBy(mgr, &kdexv1alpha1.KDexPageArchetype{}).
 			WithDefaulter(&nexuswebhook.KDexPageArchetypeDefaulter[*kdexv1alpha1.KDexPageArchetype]{}).
-			WithValidator(&nexuswebhook.PageContentValidator[*kdexv1alpha1.KDexPageArchetype]{}).
+			WithValidator(nexuswebhook.RecordRejections[*kdexv1alpha1.KDexPageArchetype](r.Recorder, &nexuswebhook.PageContentValidator[*kdexv1alpha1.KDexPageArchetype]{})).
 			Complete()
 		if err != nil {
 			return err
@@ -161,7 +165,7 @@ func (r *KDexPageArchetypeReconciler) SetupWithManager(mgr ctrl.Manager) error {
 
 		err = ctrl.NewWebhookManagedBy(mgr, &kdexv1alpha1.KDexClusterPageArchetype{}).
 			WithDefaulter(&nexuswebhook.KDexPageArchetypeDefaulter[*kdexv1alpha1.KDexClusterPageArchetype]{}).
-			WithValidator(&nexuswebhook.PageContentValidator[*kdexv1alpha1.KDexClusterPageArchetype]{}).
+			WithValidator(nexuswebhook.RecordRejections[*kdexv1alpha1.KDexClusterPageArchetype](r.Recorder, &nexuswebhook.PageContentValidator[*kdexv1alpha1.KDexClusterPageArchetype]{})).
 			Complete()
 		if err != nil {
 			return err
@@ -22,8 +22,8 @@ import (
 
 	nexuswebhook "github.com/kdex-tech/nexus-manager/internal/webhook"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/events"
 	kdexv1alpha1 "kdex.dev/crds/api/v1alpha1"
-	"kdex.dev/crds/configuration"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -31,9 +31,9 @@ import (
 // KDexPageBindingReconciler reconciles a KDexPageBinding object
 type KDexPageBindingReconciler struct {
 	client.Client
-	Configuration configuration.NexusConfiguration
-	RequeueDelay  time.Duration
-	Scheme        *runtime.Scheme
+	Recorder     events.EventRecorder
+	RequeueDelay time.Duration
+	Scheme       *runtime.Scheme
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -41,7 +41,7 @@ func (r *KDexPageBindingReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	if os.Getenv("ENABLE_WEBHOOKS") != FALSE {
 		return ctrl.NewWebhookManagedBy(mgr, &kdexv1alpha1.KDexPageBinding{}).
 			WithDefaulter(&nexuswebhook.KDexPageBindingDefaulter[*kdexv1alpha1.KDexPageBinding]{}).
-			WithValidator(&nexuswebhook.KDexPageBindingValidator[*kdexv1alpha1.KDexPageBinding]{}).
+			WithValidator(nexuswebhook.RecordRejections[*kdexv1alpha1.KDexPageBinding](r.Recorder, &nexuswebhook.KDexPageBindingValidator[*kdexv1alpha1.KDexPageBinding]{})).
 			Complete()
 	}
 
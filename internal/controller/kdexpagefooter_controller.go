@@ -19,6 +19,7 @@ package controller
 import (
 	"context"
 	"fmt"
+	"slices"
 	"time"
 
 	"os"
@@ -26,6 +27,7 @@ import (
 	nexuswebhook "github.com/kdex-tech/nexus-manager/internal/webhook"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/events"
 	kdexv1alpha1 "kdex.dev/crds/api/v1alpha1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -38,6 +40,7 @@ import (
 // KDexPageFooterReconciler reconciles a KDexPageFooter object
 type KDexPageFooterReconciler struct {
 	client.Client
+	Recorder     events.EventRecorder
 	RequeueDelay time.Duration
 	Scheme       *runtime.Scheme
 }
@@ -47,7 +50,6 @@ func (r *KDexPageFooterReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 
 	var status *kdexv1alpha1.KDexObjectStatus
 	var spec kdexv1alpha1.KDexPageFooterSpec
-	var om metav1.ObjectMeta
 	var o client.Object
 
 	if req.Namespace == "" {
@@ -57,7 +59,6 @@ func (r *KDexPageFooterReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		}
 		status = &clusterPageFooter.Status
 		spec = clusterPageFooter.Spec
-		om = clusterPageFooter.ObjectMeta
 		o = &clusterPageFooter
 	} else {
 		var pageFooter kdexv1alpha1.KDexPageFooter
@@ -66,7 +67,6 @@ func (r *KDexPageFooterReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		}
 		status = &pageFooter.Status
 		spec = pageFooter.Spec
-		om = pageFooter.ObjectMeta
 		o = &pageFooter
 	}
 
@@ -74,13 +74,17 @@ func (r *KDexPageFooterReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		status.Attributes = make(map[string]string)
 	}
 
+	base := o.DeepCopyObject().(client.Object)
+	previousConditions := slices.Clone(status.Conditions)
+
+	if isPaused(o) {
+		return ctrl.Result{}, pauseReconcile(ctx, r.Client, r.Recorder, base, o, status)
+	}
+	resumeReconcile(r.Recorder, o, status)
+
 	// Defer status update
 	defer func() {
-		status.ObservedGeneration = om.Generation
-		if updateErr := r.Status().Update(ctx, o); updateErr != nil {
-			err = updateErr
-			res = ctrl.Result{}
-		}
+		res, err = finishReconcile(ctx, r.Client, r.Recorder, base, o, previousConditions, res, err)
 
 		log.V(2).Info("status", "status", status, "err", err, "res", res)
 	}()
@@ -126,7 +130,7 @@ func (r *KDexPageFooterReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	if os.Getenv("ENABLE_WEBHOOKS") != FALSE {
 		err := ctrl.NewWebhookManagedBy(mgr, &kdexv1alpha1.KDexPageFooter{}).
 			WithDefaulter(&nexuswebhook.KDexPageFooterDefaulter[*kdexv1alpha1.KDexPageFooter]{}).
-			WithValidator(&nexuswebhook.PageContentValidator[*kdexv1alpha1.KDexPageFooter]{}).
+			WithValidator(nexuswebhook.RecordRejections[*kdexv1alpha1.KDexPageFooter](r.Recorder, &nexuswebhook.PageContentValidator[*kdexv1alpha1.KDexPageFooter]{})).
 			Complete()
 		if err != nil {
 			return err
@@ -134,7 +138,7 @@ func (r *KDexPageFooterReconciler) SetupWithManager(mgr ctrl.Manager) error {
 
 		err = ctrl.NewWebhookManagedBy(mgr, &kdexv1alpha1.KDexClusterPageFooter{}).
 			WithDefaulter(&nexuswebhook.KDexPageFooterDefaulter[*kdexv1alpha1.KDexClusterPageFooter]{}).
-			WithValidator(&nexuswebhook.PageContentValidator[*kdexv1alpha1.KDexClusterPageFooter]{}).
+			WithValidator(nexuswebhook.RecordRejections[*kdexv1alpha1.KDexClusterPageFooter](r.Recorder, &nexuswebhook.PageContentValidator[*kdexv1alpha1.KDexClusterPageFooter]{})).
 			Complete()
 		if err != nil {
 			return err
@@ -19,6 +19,7 @@ package controller
 import (
 	"context"
 	"fmt"
+	"slices"
 	"time"
 
 	"os"
@@ -33,12 +34,14 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	nexuswebhook "github.com/kdex-tech/nexus-manager/internal/webhook"
+	"k8s.io/client-go/tools/events"
 	kdexv1alpha1 "kdex.dev/crds/api/v1alpha1"
 )
 
 // KDexPageHeaderReconciler reconciles a KDexPageHeader object
 type KDexPageHeaderReconciler struct {
 	client.Client
+	Recorder     events.EventRecorder
 	RequeueDelay time.Duration
 	Scheme       *runtime.Scheme
 }
@@ -48,7 +51,6 @@ func (r *KDexPageHeaderReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 
 	var status *kdexv1alpha1.KDexObjectStatus
 	var spec kdexv1alpha1.KDexPageHeaderSpec
-	var om metav1.ObjectMeta
 	var o client.Object
 
 	if req.Namespace == "" {
@@ -58,7 +60,6 @@ func (r *KDexPageHeaderReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		}
 		status = &clusterPageHeader.Status
 		spec = clusterPageHeader.Spec
-		om = clusterPageHeader.ObjectMeta
 		o = &clusterPageHeader
 	} else {
 		var pageHeader kdexv1alpha1.KDexPageHeader
@@ -67,7 +68,6 @@ func (r *KDexPageHeaderReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		}
 		status = &pageHeader.Status
 		spec = pageHeader.Spec
-		om = pageHeader.ObjectMeta
 		o = &pageHeader
 	}
 
@@ -75,13 +75,17 @@ func (r *KDexPageHeaderReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		status.Attributes = make(map[string]string)
 	}
 
+	base := o.DeepCopyObject().(client.Object)
+	previousConditions := slices.Clone(status.Conditions)
+
+	if isPaused(o) {
+		return ctrl.Result{}, pauseReconcile(ctx, r.Client, r.Recorder, base, o, status)
+	}
+	resumeReconcile(r.Recorder, o, status)
+
 	// Defer status update
 	defer func() {
-		status.ObservedGeneration = om.Generation
-		if updateErr := r.Status().Update(ctx, o); updateErr != nil {
-			err = updateErr
-			res = ctrl.Result{}
-		}
+		res, err = finishReconcile(ctx, r.Client, r.Recorder, base, o, previousConditions, res, err)
 
 		log.V(2).Info("status", "status", status, "err", err, "res", res)
 	}()
@@ -127,7 +131,7 @@ func (r *KDexPageHeaderReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	if os.Getenv("ENABLE_WEBHOOKS") != FALSE {
 		err := ctrl.NewWebhookManagedBy(mgr, &kdexv1alpha1.KDexPageHeader{}).
 			WithDefaulter(&nexuswebhook.KDexPageHeaderDefaulter[*kdexv1alpha1.KDexPageHeader]{}).
-			WithValidator(&nexuswebhook.PageContentValidator[*kdexv1alpha1.KDexPageHeader]{}).
+			WithValidator(nexuswebhook.RecordRejections[*kdexv1alpha1.KDexPageHeader](r.Recorder, &nexuswebhook.PageContentValidator[*kdexv1alpha1.KDexPageHeader]{})).
 			Complete()
 		if err != nil {
 			return err
@@ -135,7 +139,7 @@ func (r *KDexPageHeaderReconciler) SetupWithManager(mgr ctrl.Manager) error {
 
 		err = ctrl.NewWebhookManagedBy(mgr, &kdexv1alpha1.KDexClusterPageHeader{}).
 			WithDefaulter(&nexuswebhook.KDexPageHeaderDefaulter[*kdexv1alpha1.KDexClusterPageHeader]{}).
-			WithValidator(&nexuswebhook.PageContentValidator[*kdexv1alpha1.KDexClusterPageHeader]{}).
+			WithValidator(nexuswebhook.RecordRejections[*kdexv1alpha1.KDexClusterPageHeader](r.Recorder, &nexuswebhook.PageContentValidator[*kdexv1alpha1.KDexClusterPageHeader]{})).
 			Complete()
 		if err != nil {
 			return err
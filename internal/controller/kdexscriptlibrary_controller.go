@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"slices"
 	"time"
 
 	"github.com/kdex-tech/nexus-manager/internal/validation"
@@ -27,6 +28,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/events"
 	kdexv1alpha1 "kdex.dev/crds/api/v1alpha1"
 	"kdex.dev/crds/npm"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -40,6 +42,7 @@ import (
 // KDexScriptLibraryReconciler reconciles a KDexScriptLibrary object
 type KDexScriptLibraryReconciler struct {
 	client.Client
+	Recorder        events.EventRecorder
 	RegistryFactory npm.RegistryFactory
 	RequeueDelay    time.Duration
 	Scheme          *runtime.Scheme
@@ -50,7 +53,6 @@ func (r *KDexScriptLibraryReconciler) Reconcile(ctx context.Context, req ctrl.Re
 
 	var status *kdexv1alpha1.KDexObjectStatus
 	var spec kdexv1alpha1.KDexScriptLibrarySpec
-	var om metav1.ObjectMeta
 	var o client.Object
 
 	if req.Namespace == "" {
@@ -60,7 +62,6 @@ func (r *KDexScriptLibraryReconciler) Reconcile(ctx context.Context, req ctrl.Re
 		}
 		status = &clusterScriptLibrary.Status
 		spec = clusterScriptLibrary.Spec
-		om = clusterScriptLibrary.ObjectMeta
 		o = &clusterScriptLibrary
 	} else {
 		var scriptLibrary kdexv1alpha1.KDexScriptLibrary
@@ -69,7 +70,6 @@ func (r *KDexScriptLibraryReconciler) Reconcile(ctx context.Context, req ctrl.Re
 		}
 		status = &scriptLibrary.Status
 		spec = scriptLibrary.Spec
-		om = scriptLibrary.ObjectMeta
 		o = &scriptLibrary
 	}
 
@@ -77,13 +77,17 @@ func (r *KDexScriptLibraryReconciler) Reconcile(ctx context.Context, req ctrl.Re
 		status.Attributes = make(map[string]string)
 	}
 
+	base := o.DeepCopyObject().(client.Object)
+	previousConditions := slices.Clone(status.Conditions)
+
+	if isPaused(o) {
+		return ctrl.Result{}, pauseReconcile(ctx, r.Client, r.Recorder, base, o, status)
+	}
+	resumeReconcile(r.Recorder, o, status)
+
 	// Defer status update
 	defer func() {
-		status.ObservedGeneration = om.Generation
-		if updateErr := r.Status().Update(ctx, o); updateErr != nil {
-			err = updateErr
-			res = ctrl.Result{}
-		}
+		res, err = finishReconcile(ctx, r.Client, r.Recorder, base, o, previousConditions, res, err)
 
 		log.V(2).Info("status", "status", status, "err", err, "res", res)
 	}()
@@ -110,6 +114,9 @@ func (r *KDexScriptLibraryReconciler) Reconcile(ctx context.Context, req ctrl.Re
 		}
 
 		if err := validation.ValidatePackageReference(spec.PackageReference, secret, r.RegistryFactory); err != nil {
+			r.Recorder.Eventf(o, nil, corev1.EventTypeWarning, EventReasonInvalidPackageReference, eventActionReconcile,
+				"Invalid package reference: %v", err)
+
 			kdexv1alpha1.SetConditions(
 				&status.Conditions,
 				kdexv1alpha1.ConditionStatuses{
@@ -146,7 +153,7 @@ func (r *KDexScriptLibraryReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	if os.Getenv("ENABLE_WEBHOOKS") != FALSE {
 		err := ctrl.NewWebhookManagedBy(mgr, &kdexv1alpha1.KDexScriptLibrary{}).
 			WithDefaulter(&nexuswebhook.KDexScriptLibraryDefaulter[*kdexv1alpha1.KDexScriptLibrary]{}).
-			WithValidator(&nexuswebhook.KDexScriptLibraryValidator[*kdexv1alpha1.KDexScriptLibrary]{}).
+			WithValidator(nexuswebhook.RecordRejections[*kdexv1alpha1.KDexScriptLibrary](r.Recorder, &nexuswebhook.KDexScriptLibraryValidator[*kdexv1alpha1.KDexScriptLibrary]{})).
 			Complete()
 
 		if err != nil {
@@ -155,7 +162,7 @@ func (r *KDexScriptLibraryReconciler) SetupWithManager(mgr ctrl.Manager) error {
 
 		err = ctrl.NewWebhookManagedBy(mgr, &kdexv1alpha1.KDexClusterScriptLibrary{}).
 			WithDefaulter(&nexuswebhook.KDexScriptLibraryDefaulter[*kdexv1alpha1.KDexClusterScriptLibrary]{}).
-			WithValidator(&nexuswebhook.KDexScriptLibraryValidator[*kdexv1alpha1.KDexClusterScriptLibrary]{}).
+			WithValidator(nexuswebhook.RecordRejections[*kdexv1alpha1.KDexClusterScriptLibrary](r.Recorder, &nexuswebhook.KDexScriptLibraryValidator[*kdexv1alpha1.KDexClusterScriptLibrary]{})).
 			Complete()
 
 		if err != nil {
@@ -20,11 +20,13 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"slices"
 	"time"
 
 	nexuswebhook "github.com/kdex-tech/nexus-manager/internal/webhook"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/events"
 	kdexv1alpha1 "kdex.dev/crds/api/v1alpha1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -37,6 +39,7 @@ import (
 // KDexThemeReconciler reconciles a KDexTheme object
 type KDexThemeReconciler struct {
 	client.Client
+	Recorder     events.EventRecorder
 	RequeueDelay time.Duration
 	Scheme       *runtime.Scheme
 }
@@ -46,7 +49,6 @@ func (r *KDexThemeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 
 	var status *kdexv1alpha1.KDexObjectStatus
 	var spec kdexv1alpha1.KDexThemeSpec
-	var om metav1.ObjectMeta
 	var o client.Object
 
 	if req.Namespace == "" {
@@ -56,7 +58,6 @@ func (r *KDexThemeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		}
 		status = &clusterTheme.Status
 		spec = clusterTheme.Spec
-		om = clusterTheme.ObjectMeta
 		o = &clusterTheme
 	} else {
 		var theme kdexv1alpha1.KDexTheme
@@ -65,7 +66,6 @@ func (r *KDexThemeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		}
 		status = &theme.Status
 		spec = theme.Spec
-		om = theme.ObjectMeta
 		o = &theme
 	}
 
@@ -73,13 +73,17 @@ func (r *KDexThemeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		status.Attributes = make(map[string]string)
 	}
 
+	base := o.DeepCopyObject().(client.Object)
+	previousConditions := slices.Clone(status.Conditions)
+
+	if isPaused(o) {
+		return ctrl.Result{}, pauseReconcile(ctx, r.Client, r.Recorder, base, o, status)
+	}
+	resumeReconcile(r.Recorder, o, status)
+
 	// Defer status update
 	defer func() {
-		status.ObservedGeneration = om.Generation
-		if updateErr := r.Status().Update(ctx, o); updateErr != nil {
-			err = updateErr
-			res = ctrl.Result{}
-		}
+		res, err = finishReconcile(ctx, r.Client, r.Recorder, base, o, previousConditions, res, err)
 
 		log.V(2).Info("status", "status", status, "err", err, "res", res)
 	}()
@@ -125,7 +129,7 @@ func (r *KDexThemeReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	if os.Getenv("ENABLE_WEBHOOKS") != FALSE {
 		err := ctrl.NewWebhookManagedBy(mgr, &kdexv1alpha1.KDexTheme{}).
 			WithDefaulter(&nexuswebhook.KDexThemeDefaulter[*kdexv1alpha1.KDexTheme]{}).
-			WithValidator(&nexuswebhook.KDexThemeValidator[*kdexv1alpha1.KDexTheme]{}).
+			WithValidator(nexuswebhook.RecordRejections[*kdexv1alpha1.KDexTheme](r.Recorder, &nexuswebhook.KDexThemeValidator[*kdexv1alpha1.KDexTheme]{})).
 			Complete()
 
 		if err != nil {
@@ -134,7 +138,7 @@ func (r *KDexThemeReconciler) SetupWithManager(mgr ctrl.Manager) error {
 
 		err = ctrl.NewWebhookManagedBy(mgr, &kdexv1alpha1.KDexClusterTheme{}).
 			WithDefaulter(&nexuswebhook.KDexThemeDefaulter[*kdexv1alpha1.KDexClusterTheme]{}).
-			WithValidator(&nexuswebhook.KDexThemeValidator[*kdexv1alpha1.KDexClusterTheme]{}).
+			WithValidator(nexuswebhook.RecordRejections[*kdexv1alpha1.KDexClusterTheme](r.Recorder, &nexuswebhook.KDexThemeValidator[*kdexv1alpha1.KDexClusterTheme]{})).
 			Complete()
 
 		if err != nil {
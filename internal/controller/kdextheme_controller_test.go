@@ -21,7 +21,9 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	kdexv1alpha1 "kdex.dev/crds/api/v1alpha1"
 )
 
@@ -75,6 +77,49 @@ var _ = Describe("KDexTheme Controller", func() {
 				&kdexv1alpha1.KDexTheme{}, true)
 		})
 
+		It("should not write the status when a reconcile changes nothing", func() {
+			resource := &kdexv1alpha1.KDexTheme{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: namespace,
+				},
+				Spec: kdexv1alpha1.KDexThemeSpec{
+					Assets: []kdexv1alpha1.Asset{
+						{
+							Attributes: map[string]string{
+								"rel": "stylesheet",
+							},
+							LinkHref: "http://kdex.dev/style.css",
+						},
+					},
+				},
+			}
+
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+			assertResourceReady(
+				ctx, k8sClient, resourceName, namespace,
+				&kdexv1alpha1.KDexTheme{}, true)
+
+			key := types.NamespacedName{Name: resourceName, Namespace: namespace}
+			Expect(k8sClient.Get(ctx, key, resource)).To(Succeed())
+			ready := meta.FindStatusCondition(resource.Status.Conditions, string(kdexv1alpha1.ConditionTypeReady))
+			Expect(ready).NotTo(BeNil())
+
+			// An annotation triggers a reconcile without changing the outcome.
+			resource.Annotations = map[string]string{"kdex.dev/touched": "true"}
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+			resourceVersion := resource.ResourceVersion
+
+			Consistently(func(g Gomega) {
+				g.Expect(k8sClient.Get(ctx, key, resource)).To(Succeed())
+				g.Expect(resource.ResourceVersion).To(Equal(resourceVersion))
+			}, "2s").Should(Succeed())
+
+			after := meta.FindStatusCondition(resource.Status.Conditions, string(kdexv1alpha1.ConditionTypeReady))
+			Expect(after.LastTransitionTime).To(Equal(ready.LastTransitionTime))
+		})
+
 		It("should not validate with relative assets but no static image", func() {
 			resource := &kdexv1alpha1.KDexTheme{
 				ObjectMeta: metav1.ObjectMeta{
@@ -19,11 +19,13 @@ package controller
 import (
 	"context"
 	"os"
+	"slices"
 	"time"
 
 	nexuswebhook "github.com/kdex-tech/nexus-manager/internal/webhook"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/events"
 	kdexv1alpha1 "kdex.dev/crds/api/v1alpha1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -36,6 +38,7 @@ import (
 // KDexTranslationReconciler reconciles a KDexTranslation object
 type KDexTranslationReconciler struct {
 	client.Client
+	Recorder     events.EventRecorder
 	RequeueDelay time.Duration
 	Scheme       *runtime.Scheme
 }
@@ -44,7 +47,6 @@ func (r *KDexTranslationReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 	log := logf.FromContext(ctx)
 
 	var status *kdexv1alpha1.KDexObjectStatus
-	var om metav1.ObjectMeta
 	var o client.Object
 
 	if req.Namespace == "" {
@@ -53,7 +55,6 @@ func (r *KDexTranslationReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 			return ctrl.Result{}, client.IgnoreNotFound(err)
 		}
 		status = &clusterTranslation.Status
-		om = clusterTranslation.ObjectMeta
 		o = &clusterTranslation
 	} else {
 		var translation kdexv1alpha1.KDexTranslation
@@ -61,7 +62,6 @@ func (r *KDexTranslationReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 			return ctrl.Result{}, client.IgnoreNotFound(err)
 		}
 		status = &translation.Status
-		om = translation.ObjectMeta
 		o = &translation
 	}
 
@@ -69,13 +69,17 @@ func (r *KDexTranslationReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		status.Attributes = make(map[string]string)
 	}
 
+	base := o.DeepCopyObject().(client.Object)
+	previousConditions := slices.Clone(status.Conditions)
+
+	if isPaused(o) {
+		return ctrl.Result{}, pauseReconcile(ctx, r.Client, r.Recorder, base, o, status)
+	}
+	resumeReconcile(r.Recorder, o, status)
+
 	// Defer status update
 	defer func() {
-		status.ObservedGeneration = om.Generation
-		if updateErr := r.Status().Update(ctx, o); updateErr != nil {
-			err = updateErr
-			res = ctrl.Result{}
-		}
+		res, err = finishReconcile(ctx, r.Client, r.Recorder, base, o, previousConditions, res, err)
 
 		log.V(2).Info("status", "status", status, "err", err, "res", res)
 	}()
@@ -111,14 +115,14 @@ func (r *KDexTranslationReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 func (r *KDexTranslationReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	if os.Getenv("ENABLE_WEBHOOKS") != FALSE {
 		err := ctrl.NewWebhookManagedBy(mgr, &kdexv1alpha1.KDexTranslation{}).
-			WithValidator(&nexuswebhook.KDexTranslationValidator[*kdexv1alpha1.KDexTranslation]{}).
+			WithValidator(nexuswebhook.RecordRejections[*kdexv1alpha1.KDexTranslation](r.Recorder, &nexuswebhook.KDexTranslationValidator[*kdexv1alpha1.KDexTranslation]{})).
 			Complete()
 		if err != nil {
 			return err
 		}
 
 		err = ctrl.NewWebhookManagedBy(mgr, &kdexv1alpha1.KDexClusterTranslation{}).
-			WithValidator(&nexuswebhook.KDexTranslationValidator[*kdexv1alpha1.KDexClusterTranslation]{}).
+			WithValidator(nexuswebhook.RecordRejections[*kdexv1alpha1.KDexClusterTranslation](r.Recorder, &nexuswebhook.KDexTranslationValidator[*kdexv1alpha1.KDexClusterTranslation]{})).
 			Complete()
 		if err != nil {
 			return err
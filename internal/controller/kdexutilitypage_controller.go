@@ -21,11 +21,13 @@ import (
 	"fmt"
 	"maps"
 	"os"
+	"slices"
 	"time"
 
 	nexuswebhook "github.com/kdex-tech/nexus-manager/internal/webhook"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/events"
 	kdexv1alpha1 "kdex.dev/crds/api/v1alpha1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -38,6 +40,7 @@ import (
 // KDexUtilityPageReconciler reconciles a KDexUtilityPage or KDexClusterUtilityPage object
 type KDexUtilityPageReconciler struct {
 	client.Client
+	Recorder     events.EventRecorder
 	RequeueDelay time.Duration
 	Scheme       *runtime.Scheme
 }
@@ -48,7 +51,6 @@ func (r *KDexUtilityPageReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 
 	var status *kdexv1alpha1.KDexObjectStatus
 	var spec kdexv1alpha1.KDexUtilityPageSpec
-	var om metav1.ObjectMeta
 	var o client.Object
 
 	if req.Namespace == "" {
@@ -58,7 +60,6 @@ func (r *KDexUtilityPageReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		}
 		status = &clusterUtilityPage.Status
 		spec = clusterUtilityPage.Spec
-		om = clusterUtilityPage.ObjectMeta
 		o = &clusterUtilityPage
 	} else {
 		var utilityPage kdexv1alpha1.KDexUtilityPage
@@ -67,7 +68,6 @@ func (r *KDexUtilityPageReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		}
 		status = &utilityPage.Status
 		spec = utilityPage.Spec
-		om = utilityPage.ObjectMeta
 		o = &utilityPage
 	}
 
@@ -75,13 +75,17 @@ func (r *KDexUtilityPageReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		status.Attributes = make(map[string]string)
 	}
 
+	base := o.DeepCopyObject().(client.Object)
+	previousConditions := slices.Clone(status.Conditions)
+
+	if isPaused(o) {
+		return ctrl.Result{}, pauseReconcile(ctx, r.Client, r.Recorder, base, o, status)
+	}
+	resumeReconcile(r.Recorder, o, status)
+
 	// Defer status update
 	defer func() {
-		status.ObservedGeneration = om.Generation
-		if updateErr := r.Status().Update(ctx, o); updateErr != nil {
-			err = updateErr
-			res = ctrl.Result{}
-		}
+		res, err = finishReconcile(ctx, r.Client, r.Recorder, base, o, previousConditions, res, err)
 
 		log.V(2).Info("status", "status", status, "err", err, "res", res)
 	}()
@@ -188,7 +192,7 @@ func (r *KDexUtilityPageReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
 		err := ctrl.NewWebhookManagedBy(mgr, &kdexv1alpha1.KDexUtilityPage{}).
 			WithDefaulter(&nexuswebhook.KDexUtilityPageDefaulter[*kdexv1alpha1.KDexUtilityPage]{}).
-			WithValidator(&nexuswebhook.KDexUtilityPageValidator[*kdexv1alpha1.KDexUtilityPage]{}).
+			WithValidator(nexuswebhook.RecordRejections[*kdexv1alpha1.KDexUtilityPage](r.Recorder, &nexuswebhook.KDexUtilityPageValidator[*kdexv1alpha1.KDexUtilityPage]{})).
 			Complete()
 
 		if err != nil {
@@ -197,7 +201,7 @@ func (r *KDexUtilityPageReconciler) SetupWithManager(mgr ctrl.Manager) error {
 
 		err = ctrl.NewWebhookManagedBy(mgr, &kdexv1alpha1.KDexClusterUtilityPage{}).
 			WithDefaulter(&nexuswebhook.KDexUtilityPageDefaulter[*kdexv1alpha1.KDexClusterUtilityPage]{}).
-			WithValidator(&nexuswebhook.KDexUtilityPageValidator[*kdexv1alpha1.KDexClusterUtilityPage]{}).
+			WithValidator(nexuswebhook.RecordRejections[*kdexv1alpha1.KDexClusterUtilityPage](r.Recorder, &nexuswebhook.KDexUtilityPageValidator[*kdexv1alpha1.KDexClusterUtilityPage]{})).
 			Complete()
 
 		if err != nil {
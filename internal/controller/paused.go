@@ -0,0 +1,74 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/events"
+	kdexv1alpha1 "kdex.dev/crds/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// PausedAnnotation, set to "true" on any KDex object, stops the manager from reconciling it until the annotation
+	// is removed. Owned resources are left as they are, so they can be patched by hand.
+	PausedAnnotation = "kdex.dev/paused"
+
+	ConditionTypePaused   kdexv1alpha1.ConditionType   = "Paused"
+	ConditionReasonPaused kdexv1alpha1.ConditionReason = "Paused"
+
+	EventReasonPaused  = "Paused"
+	EventReasonResumed = "Resumed"
+)
+
+// isPaused reports whether obj carries the pause annotation. Values which are not booleans count as false.
+func isPaused(obj client.Object) bool {
+	paused, err := strconv.ParseBool(strings.TrimSpace(obj.GetAnnotations()[PausedAnnotation]))
+	return err == nil && paused
+}
+
+// pauseReconcile sets the Paused condition of obj and writes its status, leaving everything else as the last
+// reconcile left it. It stands in for the reconcile of a paused object.
+func pauseReconcile(
+	ctx context.Context,
+	c client.Client,
+	recorder events.EventRecorder,
+	base client.Object,
+	obj client.Object,
+	status *kdexv1alpha1.KDexObjectStatus,
+) error {
+	message := fmt.Sprintf("Reconciliation is paused by the %s annotation", PausedAnnotation)
+	paused := meta.IsStatusConditionTrue(status.Conditions, string(ConditionTypePaused))
+
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Message:            message,
+		ObservedGeneration: obj.GetGeneration(),
+		Reason:             string(ConditionReasonPaused),
+		Status:             metav1.ConditionTrue,
+		Type:               string(ConditionTypePaused),
+	})
+
+	if err := updateStatus(ctx, c, base, obj); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+
+	if !paused {
+		recorder.Eventf(obj, nil, corev1.EventTypeNormal, EventReasonPaused, eventActionReconcile, "%s", message)
+	}
+
+	return nil
+}
+
+// resumeReconcile removes the Paused condition of an object which is no longer paused. The status is written along
+// with the outcome of the reconcile.
+func resumeReconcile(recorder events.EventRecorder, obj client.Object, status *kdexv1alpha1.KDexObjectStatus) {
+	if meta.RemoveStatusCondition(&status.Conditions, string(ConditionTypePaused)) {
+		recorder.Eventf(obj, nil, corev1.EventTypeNormal, EventReasonResumed, eventActionReconcile,
+			"Reconciliation resumed after the %s annotation was removed", PausedAnnotation)
+	}
+}
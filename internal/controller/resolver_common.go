@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/kdex-tech/nexus-manager/internal/metrics"
 	"github.com/kdex-tech/nexus-manager/internal/page"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -216,6 +217,7 @@ func ResolveKDexObjectReference(
 	isReferrerClustered := strings.Contains(referrerKind, "Cluster")
 
 	if isReferrerClustered && !strings.Contains(objectRef.Kind, "Cluster") {
+		metrics.ReferenceResolutionErrors.WithLabelValues(objectRef.Kind, metrics.ReasonError).Inc()
 		return nil, true, ctrl.Result{}, fmt.Errorf(
 			"referrer %s is cluster scoped so %s must also be cluster scoped", referrerKind, objectRef.Kind)
 	}
@@ -223,6 +225,7 @@ func ResolveKDexObjectReference(
 	gvk := schema.GroupVersionKind{Group: "kdex.dev", Version: "v1alpha1", Kind: objectRef.Kind}
 	obj, err := c.Scheme().New(gvk)
 	if err != nil {
+		metrics.ReferenceResolutionErrors.WithLabelValues(objectRef.Kind, metrics.ReasonUnknownKind).Inc()
 		return nil, true, ctrl.Result{}, fmt.Errorf("unknown kind %s", objectRef.Kind)
 	}
 
@@ -235,8 +238,14 @@ func ResolveKDexObjectReference(
 		}
 	}
 
-	if err := c.Get(ctx, key, obj.(client.Object)); err != nil {
+	start := time.Now()
+	err = c.Get(ctx, key, obj.(client.Object))
+	metrics.ReferenceResolutionDuration.WithLabelValues(objectRef.Kind).Observe(time.Since(start).Seconds())
+
+	if err != nil {
 		if errors.IsNotFound(err) {
+			metrics.ReferenceResolutionErrors.WithLabelValues(objectRef.Kind, metrics.ReasonNotFound).Inc()
+
 			kdexv1alpha1.SetConditions(
 				referrerConditions,
 				kdexv1alpha1.ConditionStatuses{
@@ -250,6 +259,9 @@ func ResolveKDexObjectReference(
 
 			return nil, true, ctrl.Result{RequeueAfter: requeueDelay}, nil
 		}
+
+		metrics.ReferenceResolutionErrors.WithLabelValues(objectRef.Kind, metrics.ReasonError).Inc()
+		return nil, true, ctrl.Result{}, err
 	}
 
 	it := reflect.ValueOf(obj).Elem()
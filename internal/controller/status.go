@@ -0,0 +1,169 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/client-go/util/retry"
+	kdexv1alpha1 "kdex.dev/crds/api/v1alpha1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// updateStatus writes the status of obj when it differs from the status of base, the object as it was read at the
+// start of the reconcile.
+//
+// Conditions pass through Reconciling during every reconcile, so a condition which ends up with the status it had
+// keeps its last transition time; otherwise every reconcile would look like a change.
+//
+// The status is written with a merge patch guarded by the resource version of base. On a conflict the latest object
+// is read and the status is written over it again, so a concurrent change of the spec or metadata is not lost.
+func updateStatus(ctx context.Context, c client.Client, base client.Object, obj client.Object) error {
+	baseStatus, err := objectStatus(base)
+	if err != nil {
+		return err
+	}
+	status, err := objectStatus(obj)
+	if err != nil {
+		return err
+	}
+
+	keepTransitionTimes(baseStatus.Conditions, status.Conditions)
+
+	// The whole status is compared and written, including the fields some kinds have beside KDexObjectStatus.
+	baseValue, _ := statusField(base)
+	value, _ := statusField(obj)
+
+	if equality.Semantic.DeepEqual(baseValue.Interface(), value.Interface()) {
+		return nil
+	}
+
+	desired := deepCopyValue(value)
+	first := true
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if !first {
+			if err := c.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+				return err
+			}
+			base = obj.DeepCopyObject().(client.Object)
+			value.Set(deepCopyValue(desired))
+		}
+		first = false
+
+		return c.Status().Patch(ctx, obj, client.MergeFromWithOptions(base, client.MergeFromWithOptimisticLock{}))
+	})
+}
+
+// finishReconcile ends a reconcile of obj, which returned res and err: it records the generation the status was
+// computed for, writes the status with updateStatus and records the transition of the conditions since
+// previousConditions.
+//
+// A failed status write must not hide the error which ended the reconcile, so it is only logged when there was one;
+// otherwise it is returned in place of res to retry. An object which is already gone, such as a deleted host once its
+// finalizer is released, has no status left to write.
+func finishReconcile(
+	ctx context.Context,
+	c client.Client,
+	recorder events.EventRecorder,
+	base client.Object,
+	obj client.Object,
+	previousConditions []metav1.Condition,
+	res ctrl.Result,
+	err error,
+) (ctrl.Result, error) {
+	status, statusErr := objectStatus(obj)
+	if statusErr != nil {
+		return res, errors.Join(err, statusErr)
+	}
+
+	status.ObservedGeneration = obj.GetGeneration()
+
+	switch updateErr := updateStatus(ctx, c, base, obj); {
+	case updateErr == nil:
+		recordConditionTransition(recorder, obj, previousConditions, status.Conditions)
+	case apierrors.IsNotFound(updateErr):
+	case err == nil:
+		return ctrl.Result{}, updateErr
+	default:
+		logf.FromContext(ctx).Error(updateErr, "unable to update status")
+	}
+
+	return res, err
+}
+
+// objectStatus returns the status of a KDex object, all of which share the same status type, either as their status
+// or embedded in it.
+func objectStatus(obj client.Object) (*kdexv1alpha1.KDexObjectStatus, error) {
+	field, err := statusField(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	if status, ok := field.Addr().Interface().(*kdexv1alpha1.KDexObjectStatus); ok {
+		return status, nil
+	}
+
+	if embedded := field.FieldByName("KDexObjectStatus"); embedded.IsValid() {
+		if status, ok := embedded.Addr().Interface().(*kdexv1alpha1.KDexObjectStatus); ok {
+			return status, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no KDexObjectStatus on %T", obj)
+}
+
+// statusField returns the addressable status field of obj.
+func statusField(obj client.Object) (reflect.Value, error) {
+	value := reflect.ValueOf(obj)
+	if value.Kind() == reflect.Pointer {
+		value = value.Elem()
+	}
+
+	if value.Kind() == reflect.Struct {
+		if field := value.FieldByName("Status"); field.IsValid() && field.CanAddr() && field.Kind() == reflect.Struct {
+			return field, nil
+		}
+	}
+
+	return reflect.Value{}, fmt.Errorf("no status on %T", obj)
+}
+
+// deepCopyValue returns a deep copy of a status through its generated DeepCopyInto method.
+func deepCopyValue(value reflect.Value) reflect.Value {
+	copied := reflect.New(value.Type())
+	value.Addr().MethodByName("DeepCopyInto").Call([]reflect.Value{copied})
+	return copied.Elem()
+}
+
+// keepTransitionTimes restores the last transition time of the conditions whose status did not change.
+func keepTransitionTimes(previous []metav1.Condition, current []metav1.Condition) {
+	for i := range current {
+		for _, condition := range previous {
+			if condition.Type == current[i].Type && condition.Status == current[i].Status {
+				current[i].LastTransitionTime = condition.LastTransitionTime
+			}
+		}
+	}
+}
+
+// setDegraded sets the conditions of an object whose reconcile ended with err.
+func setDegraded(status *kdexv1alpha1.KDexObjectStatus, err error) {
+	kdexv1alpha1.SetConditions(
+		&status.Conditions,
+		kdexv1alpha1.ConditionStatuses{
+			Degraded:    metav1.ConditionTrue,
+			Progressing: metav1.ConditionFalse,
+			Ready:       metav1.ConditionFalse,
+		},
+		kdexv1alpha1.ConditionReasonReconcileError,
+		err.Error(),
+	)
+}
@@ -25,13 +25,18 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/kdex-tech/nexus-manager/internal/extensions"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/scheme"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -126,6 +131,7 @@ var _ = BeforeSuite(func() {
 
 	crdPath := getCRDPath()
 	testEnv.CRDDirectoryPaths = append(testEnv.CRDDirectoryPaths, filepath.Join(crdPath, "config", "crd", "bases"))
+	testEnv.CRDDirectoryPaths = append(testEnv.CRDDirectoryPaths, filepath.Join("testdata", "crd"))
 
 	// Retrieve the first found binary directory to allow running tests from IDEs
 	if getFirstFoundEnvTestBinaryDir() != "" {
@@ -138,6 +144,9 @@ var _ = BeforeSuite(func() {
 	err = appsv1.AddToScheme(scheme.Scheme)
 	Expect(err).NotTo(HaveOccurred())
 
+	err = autoscalingv2.AddToScheme(scheme.Scheme)
+	Expect(err).NotTo(HaveOccurred())
+
 	err = batchv1.AddToScheme(scheme.Scheme)
 	Expect(err).NotTo(HaveOccurred())
 
@@ -150,6 +159,12 @@ var _ = BeforeSuite(func() {
 	err = kdexv1alpha1.AddToScheme(scheme.Scheme)
 	Expect(err).NotTo(HaveOccurred())
 
+	err = networkingv1.AddToScheme(scheme.Scheme)
+	Expect(err).NotTo(HaveOccurred())
+
+	err = policyv1.AddToScheme(scheme.Scheme)
+	Expect(err).NotTo(HaveOccurred())
+
 	err = rbacv1.AddToScheme(scheme.Scheme)
 	Expect(err).NotTo(HaveOccurred())
 
@@ -191,6 +206,7 @@ var _ = BeforeSuite(func() {
 	// App
 	appReconciler := &KDexAppReconciler{
 		Client:          k8sManager.GetClient(),
+		Recorder:        k8sManager.GetEventRecorder("kdexapp-controller"),
 		RegistryFactory: registryFactory,
 		RequeueDelay:    0,
 		Scheme:          k8sManager.GetScheme(),
@@ -201,9 +217,17 @@ var _ = BeforeSuite(func() {
 	// Host
 	hostReconciler := &KDexHostReconciler{
 		Client:        k8sManager.GetClient(),
+		APIReader:     k8sManager.GetAPIReader(),
 		Configuration: configuration,
-		RequeueDelay:  0,
-		Scheme:        k8sManager.GetScheme(),
+		Extensions: extensions.Configuration{
+			HostDefault: extensions.HostDefault{
+				MaintenanceMode:       true,
+				PropagatedAnnotations: []string{"propagated.kdex.dev/"},
+			},
+		},
+		Recorder:     k8sManager.GetEventRecorder("kdexhost-controller"),
+		RequeueDelay: 0,
+		Scheme:       k8sManager.GetScheme(),
 	}
 	err = hostReconciler.SetupWithManager(k8sManager)
 	Expect(err).NotTo(HaveOccurred())
@@ -211,6 +235,7 @@ var _ = BeforeSuite(func() {
 	// Page Archetype
 	pageArchetypeReconciler := &KDexPageArchetypeReconciler{
 		Client:       k8sClient,
+		Recorder:     k8sManager.GetEventRecorder("kdexpagearchetype-controller"),
 		RequeueDelay: 0,
 		Scheme:       k8sClient.Scheme(),
 	}
@@ -220,6 +245,7 @@ var _ = BeforeSuite(func() {
 	// Page Binding
 	pageBindingReconciler := &KDexPageBindingReconciler{
 		Client:       k8sClient,
+		Recorder:     k8sManager.GetEventRecorder("kdexpagebinding-controller"),
 		RequeueDelay: 0,
 		Scheme:       k8sClient.Scheme(),
 	}
@@ -229,6 +255,7 @@ var _ = BeforeSuite(func() {
 	// Page Footer
 	pageFooterReconciler := &KDexPageFooterReconciler{
 		Client:       k8sClient,
+		Recorder:     k8sManager.GetEventRecorder("kdexpagefooter-controller"),
 		RequeueDelay: 0,
 		Scheme:       k8sClient.Scheme(),
 	}
@@ -238,6 +265,7 @@ var _ = BeforeSuite(func() {
 	// Page Header
 	pageHeaderReconciler := &KDexPageHeaderReconciler{
 		Client:       k8sClient,
+		Recorder:     k8sManager.GetEventRecorder("kdexpageheader-controller"),
 		RequeueDelay: 0,
 		Scheme:       k8sClient.Scheme(),
 	}
@@ -247,6 +275,7 @@ var _ = BeforeSuite(func() {
 	// Page Navigation
 	pageNavigationReconciler := &KDexPageNavigationReconciler{
 		Client:       k8sClient,
+		Recorder:     k8sManager.GetEventRecorder("kdexpagenavigation-controller"),
 		RequeueDelay: 0,
 		Scheme:       k8sClient.Scheme(),
 	}
@@ -256,6 +285,7 @@ var _ = BeforeSuite(func() {
 	// Script Library
 	scriptLibraryReconciler := &KDexScriptLibraryReconciler{
 		Client:          k8sClient,
+		Recorder:        k8sManager.GetEventRecorder("kdexscriptlibrary-controller"),
 		RegistryFactory: registryFactory,
 		RequeueDelay:    0,
 		Scheme:          k8sClient.Scheme(),
@@ -266,6 +296,7 @@ var _ = BeforeSuite(func() {
 	// Theme
 	themeReconciler := &KDexThemeReconciler{
 		Client:       k8sClient,
+		Recorder:     k8sManager.GetEventRecorder("kdextheme-controller"),
 		RequeueDelay: 0,
 		Scheme:       k8sClient.Scheme(),
 	}
@@ -275,6 +306,7 @@ var _ = BeforeSuite(func() {
 	// Translation
 	translationReconciler := &KDexTranslationReconciler{
 		Client:       k8sClient,
+		Recorder:     k8sManager.GetEventRecorder("kdextranslation-controller"),
 		RequeueDelay: 0,
 		Scheme:       k8sClient.Scheme(),
 	}
@@ -284,16 +316,33 @@ var _ = BeforeSuite(func() {
 	// Utility Page
 	utilityPageReconciler := &KDexUtilityPageReconciler{
 		Client:       k8sClient,
+		Recorder:     k8sManager.GetEventRecorder("kdexutilitypage-controller"),
 		RequeueDelay: 0,
 		Scheme:       k8sClient.Scheme(),
 	}
 	err = utilityPageReconciler.SetupWithManager(k8sManager)
 	Expect(err).ToNot(HaveOccurred())
 
+	// FaaS Adaptor
+	faasAdaptorReconciler := &KDexFaaSAdaptorReconciler{
+		Client:            k8sClient,
+		ObserverNamespace: secondNamespace,
+		Recorder:          k8sManager.GetEventRecorder("kdexfaasadaptor-controller"),
+		// kpack builders are polled rather than watched.
+		RequeueDelay: time.Second,
+		Scheme:       k8sClient.Scheme(),
+	}
+	err = faasAdaptorReconciler.SetupWithManager(k8sManager)
+	Expect(err).ToNot(HaveOccurred())
+
 	// Function
 	functionReconciler := &KDexFunctionReconciler{
-		Client: k8sClient,
-		Scheme: k8sClient.Scheme(),
+		Client:    k8sClient,
+		APIReader: k8sManager.GetAPIReader(),
+		Recorder:  k8sManager.GetEventRecorder("kdexfunction-controller"),
+		// kpack images are polled rather than watched.
+		RequeueDelay: time.Second,
+		Scheme:       k8sClient.Scheme(),
 	}
 	err = functionReconciler.SetupWithManager(k8sManager)
 	Expect(err).ToNot(HaveOccurred())
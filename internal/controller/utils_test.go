@@ -3,12 +3,16 @@ package controller
 import (
 	"context"
 	"reflect"
+	"strings"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/types"
 	kdexv1alpha1 "kdex.dev/crds/api/v1alpha1"
@@ -260,12 +264,84 @@ func assertResourceReady(ctx context.Context, k8sClient client.Client, name stri
 	Eventually(check, "5s").Should(Succeed())
 }
 
+// setClusterBuilder creates the kpack ClusterBuilder name, if needed, and sets its Ready condition.
+func setClusterBuilder(ctx context.Context, k8sClient client.Client, name string, ready bool) {
+	builder := &unstructured.Unstructured{}
+	builder.SetGroupVersionKind(kpackVersion.WithKind("ClusterBuilder"))
+	builder.SetName(name)
+
+	err := k8sClient.Create(ctx, builder)
+	if !errors.IsAlreadyExists(err) {
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	status := string(metav1.ConditionFalse)
+	if ready {
+		status = string(metav1.ConditionTrue)
+	}
+
+	Eventually(func(g Gomega) {
+		g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(builder), builder)).To(Succeed())
+		g.Expect(unstructured.SetNestedSlice(builder.Object, []any{
+			map[string]any{"status": status, "type": "Ready"},
+		}, "status", "conditions")).To(Succeed())
+		g.Expect(k8sClient.Status().Update(ctx, builder)).To(Succeed())
+	}).Should(Succeed())
+}
+
+// getFunctionJob returns the Job running a phase of the function, whose name ends in a hash of its inputs.
+func getFunctionJob(ctx context.Context, g Gomega, k8sClient client.Client, namespace string, function string, phase string) *batchv1.Job {
+	list := &batchv1.JobList{}
+	g.Expect(k8sClient.List(ctx, list, client.InNamespace(namespace), client.MatchingLabels{functionLabel: function})).To(Succeed())
+
+	jobs := []batchv1.Job{}
+	for _, job := range list.Items {
+		if strings.HasPrefix(job.Name, function+"-"+phase+"-") && job.DeletionTimestamp.IsZero() {
+			jobs = append(jobs, job)
+		}
+	}
+	g.Expect(jobs).To(HaveLen(1))
+
+	return &jobs[0]
+}
+
+// finishJob sets the Job complete or, unless succeeded, failed, as the Job controller would.
+func finishJob(ctx context.Context, k8sClient client.Client, key types.NamespacedName, succeeded bool) {
+	Eventually(func(g Gomega) {
+		job := &batchv1.Job{}
+		g.Expect(k8sClient.Get(ctx, key, job)).To(Succeed())
+		now := metav1.Now()
+		job.Status.StartTime = &now
+		if succeeded {
+			job.Status.CompletionTime = &now
+			job.Status.Succeeded = 1
+			job.Status.Conditions = []batchv1.JobCondition{
+				{Type: batchv1.JobSuccessCriteriaMet, Status: corev1.ConditionTrue, LastTransitionTime: now},
+				{Type: batchv1.JobComplete, Status: corev1.ConditionTrue, LastTransitionTime: now},
+			}
+		} else {
+			job.Status.Failed = 1
+			job.Status.Conditions = []batchv1.JobCondition{
+				{Type: batchv1.JobFailureTarget, Status: corev1.ConditionTrue, LastTransitionTime: now, Message: "BackoffLimitExceeded"},
+				{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, LastTransitionTime: now, Message: "BackoffLimitExceeded"},
+			}
+		}
+		g.Expect(k8sClient.Status().Update(ctx, job)).To(Succeed())
+	}).Should(Succeed())
+}
+
 func cleanupResources(namespace string) {
 	By("Cleanup all the test resource instances")
 
+	images := &unstructured.Unstructured{}
+	images.SetGroupVersionKind(kpackVersion.WithKind("Image"))
+	imageList := &unstructured.UnstructuredList{}
+	imageList.SetGroupVersionKind(kpackVersion.WithKind("ImageList"))
+
 	for _, pair := range []Pairs{
 		{&kdexv1alpha1.KDexApp{}, &kdexv1alpha1.KDexAppList{}},
 		{&kdexv1alpha1.KDexClusterApp{}, &kdexv1alpha1.KDexClusterAppList{}},
+		{&kdexv1alpha1.KDexClusterFaaSAdaptor{}, &kdexv1alpha1.KDexClusterFaaSAdaptorList{}},
 		{&kdexv1alpha1.KDexClusterPageArchetype{}, &kdexv1alpha1.KDexClusterPageArchetypeList{}},
 		{&kdexv1alpha1.KDexClusterPageFooter{}, &kdexv1alpha1.KDexClusterPageFooterList{}},
 		{&kdexv1alpha1.KDexClusterPageHeader{}, &kdexv1alpha1.KDexClusterPageHeaderList{}},
@@ -274,6 +350,7 @@ func cleanupResources(namespace string) {
 		{&kdexv1alpha1.KDexClusterTheme{}, &kdexv1alpha1.KDexClusterThemeList{}},
 		{&kdexv1alpha1.KDexClusterTranslation{}, &kdexv1alpha1.KDexClusterTranslationList{}},
 		{&kdexv1alpha1.KDexClusterUtilityPage{}, &kdexv1alpha1.KDexClusterUtilityPageList{}},
+		{&kdexv1alpha1.KDexFaaSAdaptor{}, &kdexv1alpha1.KDexFaaSAdaptorList{}},
 		{&kdexv1alpha1.KDexHost{}, &kdexv1alpha1.KDexHostList{}},
 		{&kdexv1alpha1.KDexInternalHost{}, &kdexv1alpha1.KDexInternalHostList{}},
 		{&kdexv1alpha1.KDexInternalUtilityPage{}, &kdexv1alpha1.KDexInternalUtilityPageList{}},
@@ -288,8 +365,12 @@ func cleanupResources(namespace string) {
 		{&kdexv1alpha1.KDexFunction{}, &kdexv1alpha1.KDexFunctionList{}},
 		{&kdexv1alpha1.KDexUtilityPage{}, &kdexv1alpha1.KDexUtilityPageList{}},
 		{&corev1.Secret{}, &corev1.SecretList{}},
+		{&batchv1.Job{}, &batchv1.JobList{}},
+		{images, imageList},
 	} {
-		err := k8sClient.DeleteAllOf(ctx, pair.resource, client.InNamespace(namespace))
+		// Without a garbage collector, the orphan finalizer Jobs get by default would never be removed.
+		err := k8sClient.DeleteAllOf(ctx, pair.resource, client.InNamespace(namespace),
+			client.PropagationPolicy(metav1.DeletePropagationBackground))
 		Expect(err).NotTo(HaveOccurred())
 
 		Eventually(func(g Gomega) error {
@@ -0,0 +1,216 @@
+package diagnostics
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Reasons reported by Diagnose.
+const (
+	ReasonCrashLoopBackOff         = "CrashLoopBackOff"
+	ReasonImagePullBackOff         = "ImagePullBackOff"
+	ReasonProbeFailure             = "ProbeFailure"
+	ReasonProgressDeadlineExceeded = "ProgressDeadlineExceeded"
+	ReasonReplicaFailure           = "ReplicaFailure"
+	ReasonRolloutInProgress        = "RolloutInProgress"
+	ReasonUnschedulable            = "Unschedulable"
+)
+
+// revisionAnnotation holds the rollout revision on Deployments and their ReplicaSets.
+const revisionAnnotation = "deployment.kubernetes.io/revision"
+
+// Diagnosis summarizes the state of a Deployment and explains why it is not available.
+type Diagnosis struct {
+	// Available tells whether the Deployment reports the Available condition, or reports no conditions yet.
+	Available bool
+
+	// DesiredReplicas and ReadyReplicas count the replicas wanted and ready.
+	DesiredReplicas int32
+	ReadyReplicas   int32
+
+	// Failed tells whether the reason requires intervention, as opposed to a rollout which may still succeed.
+	Failed bool
+
+	// Image is the image of the first container of the pod template.
+	Image string
+
+	// Message details Reason, naming the pods and containers concerned.
+	Message string
+
+	// Reason is one of the Reason constants, empty when nothing is wrong.
+	Reason string
+}
+
+// Diagnose inspects a Deployment together with its ReplicaSets and the pods they own. The most severe problem found
+// is reported: a rollout past its deadline, ReplicaSet creation failures, image pull and crash loops, unschedulable
+// pods, then containers failing their probes.
+func Diagnose(deployment *appsv1.Deployment, replicaSets []appsv1.ReplicaSet, pods []corev1.Pod) Diagnosis {
+	diagnosis := Diagnosis{
+		Available:       true,
+		DesiredReplicas: 1,
+		ReadyReplicas:   deployment.Status.ReadyReplicas,
+	}
+
+	if deployment.Spec.Replicas != nil {
+		diagnosis.DesiredReplicas = *deployment.Spec.Replicas
+	}
+
+	if containers := deployment.Spec.Template.Spec.Containers; len(containers) > 0 {
+		diagnosis.Image = containers[0].Image
+	}
+
+	for _, condition := range deployment.Status.Conditions {
+		if condition.Type == appsv1.DeploymentAvailable && condition.Status != corev1.ConditionTrue {
+			diagnosis.Available = false
+		}
+	}
+
+	for _, condition := range deployment.Status.Conditions {
+		if condition.Type == appsv1.DeploymentProgressing && condition.Status == corev1.ConditionFalse &&
+			condition.Reason == ReasonProgressDeadlineExceeded {
+			return diagnosis.with(ReasonProgressDeadlineExceeded, true, condition.Message)
+		}
+	}
+
+	current := CurrentReplicaSet(deployment, replicaSets)
+	if current != nil {
+		for _, condition := range current.Status.Conditions {
+			if condition.Type == appsv1.ReplicaSetReplicaFailure && condition.Status == corev1.ConditionTrue {
+				return diagnosis.with(ReasonReplicaFailure, true, condition.Message)
+			}
+		}
+	}
+
+	pods = currentPods(current, pods)
+
+	for _, check := range []func(*corev1.Pod) (string, bool, string){
+		imagePullFailure,
+		crashLoop,
+		unschedulable,
+		probeFailure,
+	} {
+		for i := range pods {
+			if reason, failed, message := check(&pods[i]); reason != "" {
+				return diagnosis.with(reason, failed, fmt.Sprintf("pod %s: %s", pods[i].Name, message))
+			}
+		}
+	}
+
+	if !diagnosis.Available || diagnosis.ReadyReplicas < diagnosis.DesiredReplicas {
+		return diagnosis.with(ReasonRolloutInProgress, false, fmt.Sprintf("%d of %d replicas ready",
+			diagnosis.ReadyReplicas, diagnosis.DesiredReplicas))
+	}
+
+	return diagnosis
+}
+
+// CurrentReplicaSet returns the ReplicaSet of the Deployment's current revision, or nil when it is not known yet.
+func CurrentReplicaSet(deployment *appsv1.Deployment, replicaSets []appsv1.ReplicaSet) *appsv1.ReplicaSet {
+	revision := deployment.Annotations[revisionAnnotation]
+	if revision == "" {
+		return nil
+	}
+
+	for i := range replicaSets {
+		replicaSet := &replicaSets[i]
+		if metav1.IsControlledBy(replicaSet, deployment) && replicaSet.Annotations[revisionAnnotation] == revision {
+			return replicaSet
+		}
+	}
+
+	return nil
+}
+
+// String renders the diagnosis in one line, for the host status.
+func (d Diagnosis) String() string {
+	summary := fmt.Sprintf("%d/%d replicas ready", d.ReadyReplicas, d.DesiredReplicas)
+	if d.Reason == "" || d.Reason == ReasonRolloutInProgress {
+		return summary
+	}
+	return fmt.Sprintf("%s: %s (%s)", d.Reason, d.Message, summary)
+}
+
+func (d Diagnosis) with(reason string, failed bool, message string) Diagnosis {
+	d.Reason = reason
+	d.Failed = failed
+	d.Message = message
+	return d
+}
+
+// currentPods returns the pods of replicaSet, or all pods when it is not known, in name order.
+func currentPods(replicaSet *appsv1.ReplicaSet, pods []corev1.Pod) []corev1.Pod {
+	selected := []corev1.Pod{}
+	for _, pod := range pods {
+		if replicaSet == nil || metav1.IsControlledBy(&pod, replicaSet) {
+			selected = append(selected, pod)
+		}
+	}
+
+	sort.Slice(selected, func(i, j int) bool {
+		return selected[i].Name < selected[j].Name
+	})
+
+	return selected
+}
+
+func imagePullFailure(pod *corev1.Pod) (string, bool, string) {
+	for _, status := range containerStatuses(pod) {
+		if waiting := status.State.Waiting; waiting != nil {
+			switch waiting.Reason {
+			case "ErrImagePull", "ImagePullBackOff", "InvalidImageName":
+				return ReasonImagePullBackOff, true, fmt.Sprintf("container %s cannot pull image %s: %s",
+					status.Name, status.Image, orReason(waiting.Message, waiting.Reason))
+			}
+		}
+	}
+	return "", false, ""
+}
+
+func crashLoop(pod *corev1.Pod) (string, bool, string) {
+	for _, status := range containerStatuses(pod) {
+		if waiting := status.State.Waiting; waiting != nil && waiting.Reason == ReasonCrashLoopBackOff {
+			message := fmt.Sprintf("container %s restarted %d times", status.Name, status.RestartCount)
+			if terminated := status.LastTerminationState.Terminated; terminated != nil {
+				message += fmt.Sprintf(", last exit code %d (%s)", terminated.ExitCode,
+					orReason(strings.TrimSpace(terminated.Message), terminated.Reason))
+			}
+			return ReasonCrashLoopBackOff, true, message
+		}
+	}
+	return "", false, ""
+}
+
+func unschedulable(pod *corev1.Pod) (string, bool, string) {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodScheduled && condition.Status == corev1.ConditionFalse &&
+			condition.Reason == corev1.PodReasonUnschedulable {
+			return ReasonUnschedulable, false, condition.Message
+		}
+	}
+	return "", false, ""
+}
+
+func probeFailure(pod *corev1.Pod) (string, bool, string) {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.State.Running != nil && !status.Ready {
+			return ReasonProbeFailure, false, fmt.Sprintf("container %s is running but its readiness probe has not passed", status.Name)
+		}
+	}
+	return "", false, ""
+}
+
+func containerStatuses(pod *corev1.Pod) []corev1.ContainerStatus {
+	return append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+}
+
+func orReason(message string, reason string) string {
+	if message == "" {
+		return reason
+	}
+	return message
+}
@@ -0,0 +1,206 @@
+package diagnostics
+
+import (
+	"testing"
+
+	"github.com/kdex-tech/nexus-manager/internal/utils"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func Test_Diagnose(t *testing.T) {
+	tests := []struct {
+		name       string
+		deployment func(*appsv1.Deployment)
+		replicaSet func(*appsv1.ReplicaSet)
+		pod        func(*corev1.Pod)
+		reason     string
+		failed     bool
+		available  bool
+		message    string
+	}{
+		{
+			name:      "ready",
+			available: true,
+		},
+		{
+			name: "progress deadline exceeded",
+			deployment: func(d *appsv1.Deployment) {
+				d.Status.Conditions = append(d.Status.Conditions, appsv1.DeploymentCondition{
+					Type:    appsv1.DeploymentProgressing,
+					Status:  corev1.ConditionFalse,
+					Reason:  ReasonProgressDeadlineExceeded,
+					Message: `ReplicaSet "host-1" has timed out progressing.`,
+				})
+			},
+			available: true,
+			reason:    ReasonProgressDeadlineExceeded,
+			failed:    true,
+			message:   `ReplicaSet "host-1" has timed out progressing.`,
+		},
+		{
+			name: "replica failure",
+			replicaSet: func(rs *appsv1.ReplicaSet) {
+				rs.Status.Conditions = append(rs.Status.Conditions, appsv1.ReplicaSetCondition{
+					Type:    appsv1.ReplicaSetReplicaFailure,
+					Status:  corev1.ConditionTrue,
+					Message: "exceeded quota",
+				})
+			},
+			available: true,
+			reason:    ReasonReplicaFailure,
+			failed:    true,
+			message:   "exceeded quota",
+		},
+		{
+			name:       "image pull back-off",
+			deployment: unavailable,
+			pod: func(p *corev1.Pod) {
+				p.Status.ContainerStatuses = []corev1.ContainerStatus{{
+					Name:  "host",
+					Image: "kdex-host:missing",
+					State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{
+						Reason:  "ImagePullBackOff",
+						Message: "Back-off pulling image",
+					}},
+				}}
+			},
+			reason:  ReasonImagePullBackOff,
+			failed:  true,
+			message: "pod host-1-abc: container host cannot pull image kdex-host:missing: Back-off pulling image",
+		},
+		{
+			name:       "crash loop",
+			deployment: unavailable,
+			pod: func(p *corev1.Pod) {
+				p.Status.ContainerStatuses = []corev1.ContainerStatus{{
+					Name:         "host",
+					RestartCount: 4,
+					State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{
+						Reason: ReasonCrashLoopBackOff,
+					}},
+					LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+						ExitCode: 1,
+						Reason:   "Error",
+						Message:  "invalid configuration\n",
+					}},
+				}}
+			},
+			reason:  ReasonCrashLoopBackOff,
+			failed:  true,
+			message: "pod host-1-abc: container host restarted 4 times, last exit code 1 (invalid configuration)",
+		},
+		{
+			name:       "unschedulable",
+			deployment: unavailable,
+			pod: func(p *corev1.Pod) {
+				p.Status.Conditions = []corev1.PodCondition{{
+					Type:    corev1.PodScheduled,
+					Status:  corev1.ConditionFalse,
+					Reason:  corev1.PodReasonUnschedulable,
+					Message: "0/3 nodes are available: 3 Insufficient memory.",
+				}}
+			},
+			reason:  ReasonUnschedulable,
+			message: "pod host-1-abc: 0/3 nodes are available: 3 Insufficient memory.",
+		},
+		{
+			name:       "failing readiness probe",
+			deployment: unavailable,
+			pod: func(p *corev1.Pod) {
+				p.Status.ContainerStatuses = []corev1.ContainerStatus{{
+					Name:  "host",
+					State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+				}}
+			},
+			reason:  ReasonProbeFailure,
+			message: "pod host-1-abc: container host is running but its readiness probe has not passed",
+		},
+		{
+			name:       "pods of a previous revision are ignored",
+			deployment: unavailable,
+			pod: func(p *corev1.Pod) {
+				p.OwnerReferences[0].UID = "previous"
+				p.Status.ContainerStatuses = []corev1.ContainerStatus{{
+					Name:  "host",
+					State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: ReasonCrashLoopBackOff}},
+				}}
+			},
+			reason:  ReasonRolloutInProgress,
+			message: "0 of 2 replicas ready",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deployment := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "host",
+					UID:         "deployment",
+					Annotations: map[string]string{revisionAnnotation: "2"},
+				},
+				Spec: appsv1.DeploymentSpec{
+					Replicas: utils.Ptr(int32(2)),
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "host", Image: "kdex-host:1.0"}}},
+					},
+				},
+				Status: appsv1.DeploymentStatus{ReadyReplicas: 2},
+			}
+			replicaSet := appsv1.ReplicaSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            "host-1",
+					UID:             "replicaset",
+					Annotations:     map[string]string{revisionAnnotation: "2"},
+					OwnerReferences: []metav1.OwnerReference{controllerRef("Deployment", "host", deployment.UID)},
+				},
+			}
+			pod := corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            "host-1-abc",
+					OwnerReferences: []metav1.OwnerReference{controllerRef("ReplicaSet", "host-1", replicaSet.UID)},
+				},
+			}
+
+			if tt.deployment != nil {
+				tt.deployment(deployment)
+			}
+			if tt.replicaSet != nil {
+				tt.replicaSet(&replicaSet)
+			}
+			if tt.pod != nil {
+				tt.pod(&pod)
+			}
+
+			diagnosis := Diagnose(deployment, []appsv1.ReplicaSet{replicaSet}, []corev1.Pod{pod})
+
+			assert.Equal(t, tt.available, diagnosis.Available)
+			assert.Equal(t, tt.reason, diagnosis.Reason)
+			assert.Equal(t, tt.failed, diagnosis.Failed)
+			assert.Equal(t, tt.message, diagnosis.Message)
+			assert.Equal(t, "kdex-host:1.0", diagnosis.Image)
+			assert.Equal(t, int32(2), diagnosis.DesiredReplicas)
+		})
+	}
+}
+
+func unavailable(d *appsv1.Deployment) {
+	d.Status.ReadyReplicas = 0
+	d.Status.Conditions = append(d.Status.Conditions, appsv1.DeploymentCondition{
+		Type:   appsv1.DeploymentAvailable,
+		Status: corev1.ConditionFalse,
+	})
+}
+
+func controllerRef(kind string, name string, uid types.UID) metav1.OwnerReference {
+	return metav1.OwnerReference{
+		APIVersion: "apps/v1",
+		Controller: utils.Ptr(true),
+		Kind:       kind,
+		Name:       name,
+		UID:        uid,
+	}
+}
@@ -0,0 +1,139 @@
+package domains
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	kdexv1alpha1 "kdex.dev/crds/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// IndexField is the KDexHost field index holding the values returned by IndexValues.
+const IndexField = "spec.routing.domains"
+
+// coveredPrefix marks the index values under which a domain is found by the wildcards covering it.
+const coveredPrefix = "covered:"
+
+// Conflict describes a domain of a host which overlaps a domain claimed by another host.
+type Conflict struct {
+	Domain string
+	Host   kdexv1alpha1.KDexHost
+	Other  string
+}
+
+func (c Conflict) String() string {
+	if c.Domain == c.Other {
+		return fmt.Sprintf("domain %q is already claimed by KDexHost %s/%s", c.Domain, c.Host.Namespace, c.Host.Name)
+	}
+	return fmt.Sprintf("domain %q overlaps %q claimed by KDexHost %s/%s", c.Domain, c.Other, c.Host.Namespace, c.Host.Name)
+}
+
+// IndexValues returns the values under which host is indexed: every domain, and for every domain the wildcards which
+// would cover it, prefixed so that they are not mistaken for claimed wildcards.
+func IndexValues(host *kdexv1alpha1.KDexHost) []string {
+	values := []string{}
+	for _, domain := range host.Spec.Routing.Domains {
+		domain = normalize(domain)
+		values = append(values, domain)
+		for _, wildcard := range coveringWildcards(domain) {
+			values = append(values, coveredPrefix+wildcard)
+		}
+	}
+
+	slices.Sort(values)
+	return slices.Compact(values)
+}
+
+// Overlaps reports whether a and b can match the same request host: when they are equal, or one is a wildcard
+// covering the other. As in the Gateway API, a wildcard covers every domain and wildcard under its suffix, across any
+// number of labels, but not the suffix itself.
+func Overlaps(a string, b string) bool {
+	a, b = normalize(a), normalize(b)
+	return a == b || covers(a, b) || covers(b, a)
+}
+
+// FindConflicts returns the overlaps between domains and the domains of every other KDexHost, in any namespace. The
+// reader must have the IndexField index.
+func FindConflicts(
+	ctx context.Context,
+	reader client.Reader,
+	host *kdexv1alpha1.KDexHost,
+	domains []string,
+) ([]Conflict, error) {
+	conflicts := []Conflict{}
+	seen := map[string]bool{}
+
+	for _, domain := range domains {
+		for _, value := range lookupValues(normalize(domain)) {
+			var hosts kdexv1alpha1.KDexHostList
+			if err := reader.List(ctx, &hosts, client.MatchingFields{IndexField: value}); err != nil {
+				return nil, err
+			}
+
+			for _, other := range hosts.Items {
+				if other.Namespace == host.Namespace && other.Name == host.Name {
+					continue
+				}
+
+				for _, otherDomain := range other.Spec.Routing.Domains {
+					key := domain + "|" + other.Namespace + "/" + other.Name + "|" + otherDomain
+					if seen[key] || !Overlaps(domain, otherDomain) {
+						continue
+					}
+					seen[key] = true
+					conflicts = append(conflicts, Conflict{Domain: domain, Host: other, Other: otherDomain})
+				}
+			}
+		}
+	}
+
+	return conflicts, nil
+}
+
+// Precedes reports whether a takes precedence over b when both claim the same domain: the older host wins, ties
+// being broken by namespace and name.
+func Precedes(a *kdexv1alpha1.KDexHost, b *kdexv1alpha1.KDexHost) bool {
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
+	}
+	if a.Namespace != b.Namespace {
+		return a.Namespace < b.Namespace
+	}
+	return a.Name < b.Name
+}
+
+// lookupValues returns the index values of the hosts which may overlap domain.
+func lookupValues(domain string) []string {
+	values := []string{domain}
+	values = append(values, coveringWildcards(domain)...)
+	if strings.HasPrefix(domain, "*.") {
+		values = append(values, coveredPrefix+domain)
+	}
+	return values
+}
+
+// coveringWildcards returns the wildcards covering domain, concrete or a wildcard itself: one for every parent suffix,
+// from the nearest to the top level domain.
+func coveringWildcards(domain string) []string {
+	wildcards := []string{}
+	rest := strings.TrimPrefix(domain, "*.")
+	for {
+		_, parent, found := strings.Cut(rest, ".")
+		if !found || parent == "" {
+			return wildcards
+		}
+		wildcards = append(wildcards, "*."+parent)
+		rest = parent
+	}
+}
+
+// covers reports whether wildcard is a wildcard covering domain.
+func covers(wildcard string, domain string) bool {
+	return strings.HasPrefix(wildcard, "*.") && strings.HasSuffix(domain, wildcard[1:])
+}
+
+func normalize(domain string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(domain)), ".")
+}
@@ -0,0 +1,127 @@
+package domains
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kdexv1alpha1 "kdex.dev/crds/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_Overlaps(t *testing.T) {
+	tests := []struct {
+		a        string
+		b        string
+		expected bool
+	}{
+		{a: "kdex.dev", b: "kdex.dev", expected: true},
+		{a: "KDex.dev.", b: "kdex.dev", expected: true},
+		{a: "kdex.dev", b: "www.kdex.dev", expected: false},
+		{a: "*.kdex.dev", b: "www.kdex.dev", expected: true},
+		{a: "www.kdex.dev", b: "*.kdex.dev", expected: true},
+		{a: "*.kdex.dev", b: "*.kdex.dev", expected: true},
+		{a: "*.kdex.dev", b: "kdex.dev", expected: false},
+		{a: "*.kdex.dev", b: "a.b.kdex.dev", expected: true},
+		{a: "a.b.kdex.dev", b: "*.kdex.dev", expected: true},
+		{a: "*.kdex.dev", b: "*.www.kdex.dev", expected: true},
+		{a: "*.www.kdex.dev", b: "*.kdex.dev", expected: true},
+		{a: "*.dev", b: "*.a.b.kdex.dev", expected: true},
+		{a: "*.www.kdex.dev", b: "*.blog.kdex.dev", expected: false},
+		{a: "*.www.kdex.dev", b: "www.kdex.dev", expected: false},
+		{a: "*.kdex.dev", b: "kdex.io", expected: false},
+		{a: "*.dex.dev", b: "kdex.dev", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+" "+tt.b, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Overlaps(tt.a, tt.b))
+		})
+	}
+}
+
+func Test_FindConflicts(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, kdexv1alpha1.AddToScheme(scheme))
+
+	reader := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(
+			testHost("one", "team-a", "kdex.dev", "*.apps.kdex.dev"),
+			testHost("two", "team-b", "shop.kdex.dev"),
+		).
+		WithIndex(&kdexv1alpha1.KDexHost{}, IndexField, func(obj client.Object) []string {
+			return IndexValues(obj.(*kdexv1alpha1.KDexHost))
+		}).
+		Build()
+
+	tests := []struct {
+		name     string
+		host     *kdexv1alpha1.KDexHost
+		expected []string
+	}{
+		{
+			name: "no overlap",
+			host: testHost("three", "team-c", "www.kdex.dev", "*.blog.kdex.dev"),
+		},
+		{
+			name:     "same domain in another namespace",
+			host:     testHost("three", "team-c", "kdex.dev"),
+			expected: []string{"team-a/one kdex.dev"},
+		},
+		{
+			name:     "concrete domain covered by a wildcard",
+			host:     testHost("three", "team-c", "crm.apps.kdex.dev"),
+			expected: []string{"team-a/one *.apps.kdex.dev"},
+		},
+		{
+			name:     "wildcard covering a concrete domain and a wildcard",
+			host:     testHost("three", "team-c", "*.kdex.dev"),
+			expected: []string{"team-a/one *.apps.kdex.dev", "team-b/two shop.kdex.dev"},
+		},
+		{
+			name:     "concrete domain several labels under a wildcard",
+			host:     testHost("three", "team-c", "a.b.apps.kdex.dev"),
+			expected: []string{"team-a/one *.apps.kdex.dev"},
+		},
+		{
+			name:     "wildcard under a wildcard",
+			host:     testHost("three", "team-c", "*.crm.apps.kdex.dev"),
+			expected: []string{"team-a/one *.apps.kdex.dev"},
+		},
+		{
+			name:     "wildcard covering a wildcard and concrete domains",
+			host:     testHost("three", "team-c", "*.dev"),
+			expected: []string{"team-a/one kdex.dev", "team-a/one *.apps.kdex.dev", "team-b/two shop.kdex.dev"},
+		},
+		{
+			name: "the host itself",
+			host: testHost("one", "team-a", "kdex.dev", "*.apps.kdex.dev"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conflicts, err := FindConflicts(context.Background(), reader, tt.host, tt.host.Spec.Routing.Domains)
+			assert.NoError(t, err)
+
+			actual := []string{}
+			for _, conflict := range conflicts {
+				actual = append(actual, conflict.Host.Namespace+"/"+conflict.Host.Name+" "+conflict.Other)
+			}
+			assert.ElementsMatch(t, tt.expected, actual)
+		})
+	}
+}
+
+func testHost(name string, namespace string, domains ...string) *kdexv1alpha1.KDexHost {
+	return &kdexv1alpha1.KDexHost{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: kdexv1alpha1.KDexHostSpec{
+			Routing: kdexv1alpha1.Routing{Domains: domains},
+		},
+	}
+}
@@ -0,0 +1,395 @@
+package extensions
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/yaml"
+)
+
+// Configuration holds the manager settings that NexusConfiguration has no fields for. They are read from the same
+// configuration file, under the same keys (e.g. hostDefault.podDisruptionBudget), which NexusConfiguration ignores.
+// Unlike NexusConfiguration they are never handed to hosts.
+type Configuration struct {
+	HostDefault HostDefault `json:"hostDefault"`
+}
+
+type HostDefault struct {
+	// certificate configures the TLS certificates provisioned for hosts served over https.
+	Certificate Certificate `json:"certificate"`
+
+	// deletion bounds the time a host may spend waiting for its downstream objects to be deleted.
+	Deletion Deletion `json:"deletion"`
+
+	// maintenanceMode allows hosts to be put into maintenance with the kdex.dev/maintenance annotation. It requires a
+	// kdex-host which serves the maintenance annotations of KDexInternalHost; off by default.
+	// +optional
+	MaintenanceMode bool `json:"maintenanceMode,omitempty"`
+
+	// networkPolicy configures the NetworkPolicy isolating each host.
+	NetworkPolicy NetworkPolicy `json:"networkPolicy"`
+
+	// propagatedAnnotations lists the host annotations copied onto the resources generated for the host. An entry
+	// ending in "/" matches every annotation with that prefix (e.g. service.beta.kubernetes.io/), any other entry
+	// matches one key. Host annotations are not propagated by default.
+	// +optional
+	PropagatedAnnotations []string `json:"propagatedAnnotations,omitempty"`
+
+	// podDisruptionBudget is the budget given to hosts running more than one replica.
+	PodDisruptionBudget PodDisruptionBudget `json:"podDisruptionBudget"`
+
+	// rbac configures the permissions granted to host service accounts.
+	RBAC RBAC `json:"rbac"`
+
+	// translation configures how the translations of hosts are merged.
+	Translation Translation `json:"translation"`
+}
+
+// Certificate configures how the TLS certificate of a host is obtained. With an issuerRef a cert-manager Certificate
+// is created; otherwise the manager issues the certificate itself, signed by the CA in caSecretRef or self-signed,
+// and rotates it ahead of expiry.
+type Certificate struct {
+	// caSecretRef references a kubernetes.io/tls Secret holding the internal CA used to sign host certificates when no
+	// issuerRef is set. Certificates are self-signed when neither is set.
+	// +optional
+	CASecretRef *corev1.SecretReference `json:"caSecretRef,omitempty"`
+
+	// disabled turns off certificate provisioning.
+	// +optional
+	Disabled bool `json:"disabled,omitempty"`
+
+	// duration is the requested lifetime of certificates. Defaults to 90 days.
+	// +optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
+
+	// issuerRef references the cert-manager Issuer or ClusterIssuer signing host certificates.
+	// +optional
+	IssuerRef *IssuerReference `json:"issuerRef,omitempty"`
+
+	// renewBefore is how long before expiry certificates are renewed. Defaults to 30 days.
+	// +optional
+	RenewBefore *metav1.Duration `json:"renewBefore,omitempty"`
+}
+
+// Deletion configures the staged deletion of hosts. A host whose downstream objects are still present after timeout
+// is reported as timed out; its downstream finalizers are then only removed when the host carries the force-delete
+// annotation.
+type Deletion struct {
+	// timeout is how long a host may wait for its downstream objects to be deleted. Defaults to 10 minutes.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// IssuerReference mirrors the cert-manager ObjectReference to an issuer.
+type IssuerReference struct {
+	// group of the issuer. Defaults to cert-manager.io.
+	// +optional
+	Group string `json:"group,omitempty"`
+
+	// kind of the issuer, Issuer or ClusterIssuer. Defaults to Issuer.
+	// +optional
+	Kind string `json:"kind,omitempty"`
+
+	// name of the issuer.
+	Name string `json:"name"`
+}
+
+// NetworkPolicy lists the peers host pods may exchange traffic with. Empty lists take the defaults described on each
+// field. Since NetworkPolicies cannot match host names, registries and FaaS endpoints outside the cluster can only be
+// reached through the rules given here; in-cluster ones (*.<namespace>.svc) are allowed automatically. Every egress
+// rule must list its peers, a rule without any would allow its ports to every destination.
+type NetworkPolicy struct {
+	// apiServer lists the egress rules reaching the Kubernetes API server. Defaults to the addresses and ports of the
+	// endpoints of the kubernetes Service in the default namespace, resolved whenever the policy is built.
+	// +optional
+	APIServer []networkingv1.NetworkPolicyEgressRule `json:"apiServer,omitempty"`
+
+	// disabled turns off NetworkPolicy generation.
+	// +optional
+	Disabled bool `json:"disabled,omitempty"`
+
+	// dns lists the egress rules reaching cluster DNS. Defaults to port 53 of the kube-dns pods in kube-system.
+	// +optional
+	DNS []networkingv1.NetworkPolicyEgressRule `json:"dns,omitempty"`
+
+	// faas lists the egress rules reaching the FaaS endpoints of functions outside the cluster.
+	// +optional
+	FaaS []networkingv1.NetworkPolicyEgressRule `json:"faas,omitempty"`
+
+	// gatewayPeers lists the peers allowed to reach hosts using the HTTPRoute routing strategy. Defaults to the
+	// envoy-gateway-system namespace.
+	// +optional
+	GatewayPeers []networkingv1.NetworkPolicyPeer `json:"gatewayPeers,omitempty"`
+
+	// ingressPeers lists the peers allowed to reach hosts using the Ingress routing strategy. Defaults to the
+	// ingress-nginx namespace.
+	// +optional
+	IngressPeers []networkingv1.NetworkPolicyPeer `json:"ingressPeers,omitempty"`
+
+	// managerPeers lists the peers identifying the manager. Defaults to the manager pods in any namespace.
+	// +optional
+	ManagerPeers []networkingv1.NetworkPolicyPeer `json:"managerPeers,omitempty"`
+
+	// registries lists the egress rules reaching npm and image registries outside the cluster. There is no default:
+	// registries outside the cluster cannot be reached unless listed here.
+	// +optional
+	Registries []networkingv1.NetworkPolicyEgressRule `json:"registries,omitempty"`
+}
+
+// RBAC selects the permissions of host service accounts. Each host picks a profile, by default defaultProfile, naming
+// the ClusterRole it is bound to.
+type RBAC struct {
+	// defaultProfile is the profile of hosts which do not choose one. Defaults to full.
+	// +optional
+	DefaultProfile string `json:"defaultProfile,omitempty"`
+
+	// namespaced confines host permissions to the host namespace for hosts which do not set kdex.dev/rbac-scope: the
+	// rules of the profile ClusterRole on namespaced resources are copied into a Role bound by a RoleBinding, and only
+	// those on cluster-scoped resources are granted cluster-wide.
+	// +optional
+	Namespaced bool `json:"namespaced,omitempty"`
+
+	// profiles adds profiles to, or overrides, the built-in full, functions and pages profiles.
+	// +optional
+	Profiles map[string]RBACProfile `json:"profiles,omitempty"`
+}
+
+// Built-in RBAC profiles.
+const (
+	// RBACProfileFull grants hostDefault.roleRef.
+	RBACProfileFull = "full"
+	// RBACProfileFunctions grants what hosts serving pages and KDexFunctions need.
+	RBACProfileFunctions = "functions"
+	// RBACProfilePages grants what hosts serving pages need, with read-only access to page resources.
+	RBACProfilePages = "pages"
+)
+
+type RBACProfile struct {
+	// clusterRole is the name of the ClusterRole granted by the profile. Empty stands for hostDefault.roleRef.
+	// +optional
+	ClusterRole string `json:"clusterRole,omitempty"`
+
+	// functions tells whether the profile allows hosts to generate and run KDexFunctions.
+	// +optional
+	Functions bool `json:"functions,omitempty"`
+}
+
+// DefaultTranslationName is the KDexClusterTranslation bundled with the manager.
+const DefaultTranslationName = "kdex-default-translation"
+
+// Translation configures the translations of hosts. The translations referenced by a host are merged, the first
+// reference taking precedence, over the default translation. A key missing in a language is then looked up along the
+// fallback chain of that language.
+type Translation struct {
+	// default names the KDexClusterTranslation merged into the translations of every host. Defaults to
+	// kdex-default-translation.
+	// +optional
+	Default string `json:"default,omitempty"`
+
+	// fallbacks lists, by language, the languages looked up in order for the keys missing in that language (e.g.
+	// de-AT: [de, en]). Languages not listed fall back to their parent languages (de-AT to de) and then to the default
+	// language of the host.
+	// +optional
+	Fallbacks map[string][]string `json:"fallbacks,omitempty"`
+}
+
+type PodDisruptionBudget struct {
+	// disabled turns off PodDisruptionBudget generation. A host may set it to false to turn generation back on.
+	// +optional
+	Disabled *bool `json:"disabled,omitempty"`
+
+	// maxUnavailable is the number or percentage of pods that can be unavailable after an eviction. Defaults to 1
+	// when neither maxUnavailable nor minAvailable is set.
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+
+	// minAvailable is the number or percentage of pods that must still be available after an eviction.
+	// +optional
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+
+	// unhealthyPodEvictionPolicy defines the criteria for when unhealthy pods should be considered for eviction.
+	// +optional
+	UnhealthyPodEvictionPolicy *policyv1.UnhealthyPodEvictionPolicyType `json:"unhealthyPodEvictionPolicy,omitempty"`
+}
+
+// Load reads the extension settings from configFile. A missing file yields the zero Configuration.
+func Load(configFile string) (Configuration, error) {
+	config := Configuration{}
+
+	in, err := os.ReadFile(configFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return config, nil
+		}
+		return config, err
+	}
+
+	if err := yaml.Unmarshal(in, &config); err != nil {
+		return config, fmt.Errorf("failed to decode configuration extensions: %w", err)
+	}
+
+	return config, nil
+}
+
+// Merge returns a copy of p overridden by the fields set in override. Setting either maxUnavailable or minAvailable
+// in override replaces both, since only one of them may be set.
+func (p PodDisruptionBudget) Merge(override PodDisruptionBudget) PodDisruptionBudget {
+	merged := p
+
+	if override.Disabled != nil {
+		merged.Disabled = override.Disabled
+	}
+
+	if override.MaxUnavailable != nil || override.MinAvailable != nil {
+		merged.MaxUnavailable = override.MaxUnavailable
+		merged.MinAvailable = override.MinAvailable
+	}
+
+	if override.UnhealthyPodEvictionPolicy != nil {
+		merged.UnhealthyPodEvictionPolicy = override.UnhealthyPodEvictionPolicy
+	}
+
+	return merged
+}
+
+// IsDisabled reports whether PodDisruptionBudget generation is turned off.
+func (p PodDisruptionBudget) IsDisabled() bool {
+	return p.Disabled != nil && *p.Disabled
+}
+
+// Spec returns the PodDisruptionBudgetSpec described by p, without a selector.
+func (p PodDisruptionBudget) Spec() policyv1.PodDisruptionBudgetSpec {
+	spec := policyv1.PodDisruptionBudgetSpec{
+		MaxUnavailable:             p.MaxUnavailable,
+		MinAvailable:               p.MinAvailable,
+		UnhealthyPodEvictionPolicy: p.UnhealthyPodEvictionPolicy,
+	}
+
+	if spec.MaxUnavailable == nil && spec.MinAvailable == nil {
+		maxUnavailable := intstr.FromInt32(1)
+		spec.MaxUnavailable = &maxUnavailable
+	}
+
+	return spec
+}
+
+// WithDefaults returns a copy of r in which the built-in profiles not overridden by profiles are added and an unset
+// defaultProfile is full.
+func (r RBAC) WithDefaults() RBAC {
+	profiles := map[string]RBACProfile{
+		RBACProfileFull:      {Functions: true},
+		RBACProfileFunctions: {ClusterRole: "kdex-nexus-host-functions-role", Functions: true},
+		RBACProfilePages:     {ClusterRole: "kdex-nexus-host-pages-role"},
+	}
+	maps.Copy(profiles, r.Profiles)
+	r.Profiles = profiles
+
+	if r.DefaultProfile == "" {
+		r.DefaultProfile = RBACProfileFull
+	}
+
+	return r
+}
+
+// WithDefaults returns a copy of t in which an unset default holds the bundled translation.
+func (t Translation) WithDefaults() Translation {
+	if t.Default == "" {
+		t.Default = DefaultTranslationName
+	}
+
+	return t
+}
+
+// WithDefaults returns a copy of c in which unset durations hold their defaults.
+func (c Certificate) WithDefaults() Certificate {
+	if c.Duration == nil {
+		c.Duration = &metav1.Duration{Duration: 90 * 24 * time.Hour}
+	}
+
+	if c.RenewBefore == nil {
+		c.RenewBefore = &metav1.Duration{Duration: 30 * 24 * time.Hour}
+	}
+
+	return c
+}
+
+// WithDefaults returns a copy of d in which an unset timeout holds its default.
+func (d Deletion) WithDefaults() Deletion {
+	if d.Timeout == nil {
+		d.Timeout = &metav1.Duration{Duration: 10 * time.Minute}
+	}
+
+	return d
+}
+
+// WithDefaults returns a copy of p in which every empty list holds its default, but for apiServer which is resolved
+// against the cluster.
+func (p NetworkPolicy) WithDefaults() NetworkPolicy {
+	if len(p.DNS) == 0 {
+		udp := corev1.ProtocolUDP
+		dnsPort := intstr.FromInt32(53)
+		p.DNS = []networkingv1.NetworkPolicyEgressRule{
+			{
+				Ports: []networkingv1.NetworkPolicyPort{
+					{Port: &dnsPort, Protocol: &udp},
+					tcpPort(53),
+				},
+				To: []networkingv1.NetworkPolicyPeer{
+					{
+						NamespaceSelector: namespaceSelector("kube-system"),
+						PodSelector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{"k8s-app": "kube-dns"},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	if len(p.GatewayPeers) == 0 {
+		p.GatewayPeers = []networkingv1.NetworkPolicyPeer{
+			{NamespaceSelector: namespaceSelector("envoy-gateway-system")},
+		}
+	}
+
+	if len(p.IngressPeers) == 0 {
+		p.IngressPeers = []networkingv1.NetworkPolicyPeer{
+			{NamespaceSelector: namespaceSelector("ingress-nginx")},
+		}
+	}
+
+	if len(p.ManagerPeers) == 0 {
+		p.ManagerPeers = []networkingv1.NetworkPolicyPeer{
+			{
+				NamespaceSelector: &metav1.LabelSelector{},
+				PodSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{
+						"app.kubernetes.io/name": "kdex-nexus",
+						"control-plane":          "controller-manager",
+					},
+				},
+			},
+		}
+	}
+
+	return p
+}
+
+func namespaceSelector(namespace string) *metav1.LabelSelector {
+	return &metav1.LabelSelector{
+		MatchLabels: map[string]string{corev1.LabelMetadataName: namespace},
+	}
+}
+
+func tcpPort(port int32) networkingv1.NetworkPolicyPort {
+	tcp := corev1.ProtocolTCP
+	value := intstr.FromInt32(port)
+	return networkingv1.NetworkPolicyPort{Port: &value, Protocol: &tcp}
+}
@@ -0,0 +1,70 @@
+package hostoptions
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kdex-tech/nexus-manager/internal/utils"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// AutoscalingAnnotation holds, in YAML or JSON, the Autoscaling section of a host. When present the host Deployment is
+// scaled by a HorizontalPodAutoscaler instead of using a fixed number of replicas.
+const AutoscalingAnnotation = "kdex.dev/autoscaling"
+
+const defaultCPUUtilization = 80
+
+type Autoscaling struct {
+	// behavior configures the scaling behavior of the target in both up and down directions.
+	// +optional
+	Behavior *autoscalingv2.HorizontalPodAutoscalerBehavior `json:"behavior,omitempty"`
+
+	// maxReplicas is the upper limit for the number of replicas.
+	MaxReplicas int32 `json:"maxReplicas"`
+
+	// metrics contains the specifications used to calculate the desired replica count. Defaults to 80% average CPU
+	// utilization.
+	// +optional
+	Metrics []autoscalingv2.MetricSpec `json:"metrics,omitempty"`
+
+	// minReplicas is the lower limit for the number of replicas. Defaults to 1.
+	// +optional
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+}
+
+// GetAutoscaling returns the Autoscaling section found in annotations, with defaults applied, or nil when autoscaling
+// is not enabled.
+func GetAutoscaling(annotations map[string]string) (*Autoscaling, error) {
+	value := annotations[AutoscalingAnnotation]
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+
+	autoscaling := &Autoscaling{}
+	if err := yaml.UnmarshalStrict([]byte(value), autoscaling); err != nil {
+		return nil, fmt.Errorf("%s is invalid: %w", AutoscalingAnnotation, err)
+	}
+
+	if autoscaling.MinReplicas == nil {
+		autoscaling.MinReplicas = utils.Ptr(int32(1))
+	}
+
+	if len(autoscaling.Metrics) == 0 {
+		autoscaling.Metrics = []autoscalingv2.MetricSpec{
+			{
+				Type: autoscalingv2.ResourceMetricSourceType,
+				Resource: &autoscalingv2.ResourceMetricSource{
+					Name: corev1.ResourceCPU,
+					Target: autoscalingv2.MetricTarget{
+						Type:               autoscalingv2.UtilizationMetricType,
+						AverageUtilization: utils.Ptr(int32(defaultCPUUtilization)),
+					},
+				},
+			},
+		}
+	}
+
+	return autoscaling, nil
+}
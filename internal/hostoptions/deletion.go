@@ -0,0 +1,16 @@
+package hostoptions
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ForceDeleteAnnotation, set to "true" on a host being deleted, lets the manager remove the finalizers of the
+// downstream objects still pending once the deletion timeout has expired.
+const ForceDeleteAnnotation = "kdex.dev/force-delete"
+
+// IsForceDelete reports whether annotations request forced deletion. Values which are not booleans count as false.
+func IsForceDelete(annotations map[string]string) bool {
+	force, err := strconv.ParseBool(strings.TrimSpace(annotations[ForceDeleteAnnotation]))
+	return err == nil && force
+}
@@ -0,0 +1,112 @@
+package hostoptions
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kdexv1alpha1 "kdex.dev/crds/api/v1alpha1"
+	"sigs.k8s.io/yaml"
+)
+
+// MaintenanceAnnotation holds, in YAML or JSON, the Maintenance section of a host. While the host is in maintenance
+// every route serves the maintenance page.
+const MaintenanceAnnotation = "kdex.dev/maintenance"
+
+// defaultMaintenanceRetryAfter is the Retry-After of a maintenance which has no known end.
+const defaultMaintenanceRetryAfter = 5 * time.Minute
+
+type Maintenance struct {
+	// enabled puts the host into maintenance immediately, regardless of the window.
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// pageRef is a reference to the KDexUtilityPage or KDexClusterUtilityPage served during maintenance. Defaults to
+	// the bundled maintenance page.
+	// +optional
+	PageRef *kdexv1alpha1.KDexObjectReference `json:"pageRef,omitempty"`
+
+	// retryAfter is the delay sent in the Retry-After header. Defaults to the end of the window, or 5m when the
+	// maintenance has no scheduled end.
+	// +optional
+	RetryAfterDelay *metav1.Duration `json:"retryAfter,omitempty"`
+
+	// statusCode is the HTTP status of the responses served during maintenance. Defaults to 503.
+	// +optional
+	StatusCode int32 `json:"statusCode,omitempty"`
+
+	// window schedules the maintenance.
+	// +optional
+	Window *MaintenanceWindow `json:"window,omitempty"`
+}
+
+type MaintenanceWindow struct {
+	// end is the time the maintenance ends, excluded.
+	End metav1.Time `json:"end"`
+
+	// start is the time the maintenance starts.
+	Start metav1.Time `json:"start"`
+}
+
+// GetMaintenance returns the Maintenance section found in annotations, with defaults applied, or nil when no
+// maintenance is configured.
+func GetMaintenance(annotations map[string]string) (*Maintenance, error) {
+	value := annotations[MaintenanceAnnotation]
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+
+	maintenance := &Maintenance{}
+	if err := yaml.UnmarshalStrict([]byte(value), maintenance); err != nil {
+		return nil, fmt.Errorf("%s is invalid: %w", MaintenanceAnnotation, err)
+	}
+
+	if maintenance.StatusCode == 0 {
+		maintenance.StatusCode = http.StatusServiceUnavailable
+	}
+
+	return maintenance, nil
+}
+
+// Active reports whether the host is in maintenance at now.
+func (m *Maintenance) Active(now time.Time) bool {
+	if m == nil {
+		return false
+	}
+	if m.Enabled {
+		return true
+	}
+	return m.Window != nil && !now.Before(m.Window.Start.Time) && now.Before(m.Window.End.Time)
+}
+
+// NextTransition returns the next time the host enters or leaves maintenance after now, or the zero time when it
+// will not change by itself.
+func (m *Maintenance) NextTransition(now time.Time) time.Time {
+	if m == nil || m.Enabled || m.Window == nil {
+		return time.Time{}
+	}
+	if now.Before(m.Window.Start.Time) {
+		return m.Window.Start.Time
+	}
+	if now.Before(m.Window.End.Time) {
+		return m.Window.End.Time
+	}
+	return time.Time{}
+}
+
+// RetryAfter returns the value of the Retry-After header at now: the configured delay in seconds or else, within a
+// window, the end of the window as an HTTP date so that the value does not change as time passes.
+func (m *Maintenance) RetryAfter(now time.Time) string {
+	retryAfter := defaultMaintenanceRetryAfter
+	switch {
+	case m.RetryAfterDelay != nil:
+		retryAfter = m.RetryAfterDelay.Duration
+	case !m.Enabled && m.Window != nil && now.Before(m.Window.End.Time):
+		return m.Window.End.UTC().Format(http.TimeFormat)
+	}
+	return strconv.FormatInt(int64(math.Ceil(retryAfter.Seconds())), 10)
+}
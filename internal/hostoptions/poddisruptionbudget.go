@@ -0,0 +1,43 @@
+package hostoptions
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kdex-tech/nexus-manager/internal/extensions"
+	"sigs.k8s.io/yaml"
+)
+
+// PodDisruptionBudgetAnnotation holds, in YAML or JSON, per host overrides of the HostDefault PodDisruptionBudget
+// settings.
+const PodDisruptionBudgetAnnotation = "kdex.dev/pod-disruption-budget"
+
+// GetPodDisruptionBudget returns the PodDisruptionBudget settings of a host: defaults overridden by the settings found
+// in annotations.
+func GetPodDisruptionBudget(
+	annotations map[string]string,
+	defaults extensions.PodDisruptionBudget,
+) (extensions.PodDisruptionBudget, error) {
+	override, err := GetPodDisruptionBudgetOverride(annotations)
+	if err != nil {
+		return extensions.PodDisruptionBudget{}, err
+	}
+
+	return defaults.Merge(override), nil
+}
+
+// GetPodDisruptionBudgetOverride returns the PodDisruptionBudget settings found in annotations alone.
+func GetPodDisruptionBudgetOverride(annotations map[string]string) (extensions.PodDisruptionBudget, error) {
+	override := extensions.PodDisruptionBudget{}
+
+	value := annotations[PodDisruptionBudgetAnnotation]
+	if strings.TrimSpace(value) == "" {
+		return override, nil
+	}
+
+	if err := yaml.UnmarshalStrict([]byte(value), &override); err != nil {
+		return override, fmt.Errorf("%s is invalid: %w", PodDisruptionBudgetAnnotation, err)
+	}
+
+	return override, nil
+}
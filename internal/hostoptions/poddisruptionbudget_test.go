@@ -0,0 +1,62 @@
+package hostoptions
+
+import (
+	"testing"
+
+	"github.com/kdex-tech/nexus-manager/internal/extensions"
+	"github.com/kdex-tech/nexus-manager/internal/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_GetPodDisruptionBudget(t *testing.T) {
+	tests := []struct {
+		name         string
+		defaults     extensions.PodDisruptionBudget
+		annotation   string
+		wantDisabled bool
+		wantErr      bool
+	}{
+		{
+			name: "enabled by default",
+		},
+		{
+			name:         "disabled by default",
+			defaults:     extensions.PodDisruptionBudget{Disabled: utils.Ptr(true)},
+			wantDisabled: true,
+		},
+		{
+			name:         "disabled by the host",
+			annotation:   "{disabled: true}",
+			wantDisabled: true,
+		},
+		{
+			name:       "enabled by the host",
+			defaults:   extensions.PodDisruptionBudget{Disabled: utils.Ptr(true)},
+			annotation: "{disabled: false}",
+		},
+		{
+			name:         "kept disabled by an unrelated override",
+			defaults:     extensions.PodDisruptionBudget{Disabled: utils.Ptr(true)},
+			annotation:   "{maxUnavailable: 2}",
+			wantDisabled: true,
+		},
+		{
+			name:       "invalid",
+			annotation: "{enabled: true}",
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			budget, err := GetPodDisruptionBudget(map[string]string{PodDisruptionBudgetAnnotation: tt.annotation}, tt.defaults)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantDisabled, budget.IsDisabled())
+		})
+	}
+}
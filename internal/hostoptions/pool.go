@@ -0,0 +1,20 @@
+package hostoptions
+
+import "strings"
+
+// PoolAnnotation names the serving pool a host joins. The hosts of a namespace which name the same pool are served by
+// one shared Deployment, Service, ServiceAccount and ConfigMap instead of each having their own.
+const PoolAnnotation = "kdex.dev/pool"
+
+// PoolResourcePrefix prefixes the name of the resources shared by the hosts of a pool.
+const PoolResourcePrefix = "kdex-pool-"
+
+// GetPool returns the name of the pool chosen in annotations, or "" when the host has its own serving resources.
+func GetPool(annotations map[string]string) string {
+	return strings.TrimSpace(annotations[PoolAnnotation])
+}
+
+// PoolResourceName returns the name of the resources shared by the hosts of pool.
+func PoolResourceName(pool string) string {
+	return PoolResourcePrefix + pool
+}
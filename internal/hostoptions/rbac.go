@@ -0,0 +1,60 @@
+package hostoptions
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/kdex-tech/nexus-manager/internal/extensions"
+)
+
+// RBACProfileAnnotation names the RBAC profile granted to the host service account.
+const RBACProfileAnnotation = "kdex.dev/rbac-profile"
+
+// RBACScopeAnnotation chooses where the RBAC profile is granted: cluster-wide, or only in the host namespace.
+const RBACScopeAnnotation = "kdex.dev/rbac-scope"
+
+// RBAC scopes.
+const (
+	// RBACScopeCluster binds the profile ClusterRole cluster-wide.
+	RBACScopeCluster = "cluster"
+	// RBACScopeNamespace grants the profile in the host namespace, and only its rules on cluster-scoped resources
+	// cluster-wide.
+	RBACScopeNamespace = "namespace"
+)
+
+// GetRBACProfile returns the name and settings of the RBAC profile chosen in annotations, or of the default profile.
+func GetRBACProfile(annotations map[string]string, rbac extensions.RBAC) (string, extensions.RBACProfile, error) {
+	rbac = rbac.WithDefaults()
+
+	name := strings.TrimSpace(annotations[RBACProfileAnnotation])
+	if name == "" {
+		name = rbac.DefaultProfile
+	}
+
+	profile, ok := rbac.Profiles[name]
+	if !ok {
+		known := slices.Sorted(maps.Keys(rbac.Profiles))
+		return "", extensions.RBACProfile{}, fmt.Errorf("%s: unknown profile %q, must be one of %s",
+			RBACProfileAnnotation, name, strings.Join(known, ", "))
+	}
+
+	return name, profile, nil
+}
+
+// IsRBACNamespaced reports whether the RBAC scope chosen in annotations, or rbac.namespaced when none is, confines the
+// profile to the host namespace.
+func IsRBACNamespaced(annotations map[string]string, rbac extensions.RBAC) (bool, error) {
+	switch scope := strings.TrimSpace(annotations[RBACScopeAnnotation]); scope {
+	case "":
+		return rbac.Namespaced, nil
+	case RBACScopeCluster:
+		return false, nil
+	case RBACScopeNamespace:
+		return true, nil
+	default:
+		return false, fmt.Errorf("%s: unknown scope %q, must be one of %s, %s",
+			RBACScopeAnnotation, scope, RBACScopeCluster, RBACScopeNamespace)
+	}
+}
@@ -0,0 +1,16 @@
+package hostoptions
+
+import (
+	"strconv"
+	"strings"
+)
+
+// DefaultTranslationAnnotation, set to "false", leaves the default translation out of the translations of the host.
+const DefaultTranslationAnnotation = "kdex.dev/default-translation"
+
+// UsesDefaultTranslation reports whether the default translation is merged into the translations of the host. Values
+// which are not booleans count as true.
+func UsesDefaultTranslation(annotations map[string]string) bool {
+	use, err := strconv.ParseBool(strings.TrimSpace(annotations[DefaultTranslationAnnotation]))
+	return err != nil || use
+}
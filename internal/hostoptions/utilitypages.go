@@ -0,0 +1,127 @@
+package hostoptions
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	kdexv1alpha1 "kdex.dev/crds/api/v1alpha1"
+	"sigs.k8s.io/yaml"
+)
+
+// UtilityPagesAnnotation holds, in YAML or JSON, the utility pages of a host beyond the announcement, error and login
+// pages of spec.utilityPages, keyed by utility page type. A null reference leaves out the default page of the type.
+const UtilityPagesAnnotation = "kdex.dev/utility-pages"
+
+// ErrorPagesAnnotation holds, in YAML or JSON, the utility page type served for each HTTP error status, keyed by
+// status. It is merged over DefaultErrorPages; an empty type restores the error page for the status. Statuses which
+// are not mapped are served the error page.
+const ErrorPagesAnnotation = "kdex.dev/error-pages"
+
+// UtilityPageTypeAnnotation, set on a KDexUtilityPage or KDexClusterUtilityPage, declares a utility page type which
+// spec.type cannot hold. spec.type is then only the fallback of hosts which do not know the declared type.
+const UtilityPageTypeAnnotation = "kdex.dev/utility-page-type"
+
+// The utility page types known beyond those of spec.type.
+const (
+	ForbiddenUtilityPageType   kdexv1alpha1.KDexUtilityPageType = "Forbidden"
+	LogoutUtilityPageType      kdexv1alpha1.KDexUtilityPageType = "Logout"
+	MaintenanceUtilityPageType kdexv1alpha1.KDexUtilityPageType = "Maintenance"
+	NotFoundUtilityPageType    kdexv1alpha1.KDexUtilityPageType = "NotFound"
+	OfflineUtilityPageType     kdexv1alpha1.KDexUtilityPageType = "Offline"
+)
+
+// DefaultedUtilityPageTypes are the utility page types beyond those of spec.utilityPages which every host gets unless
+// it leaves them out. The maintenance page is only served while the host is in maintenance.
+var DefaultedUtilityPageTypes = []kdexv1alpha1.KDexUtilityPageType{
+	ForbiddenUtilityPageType,
+	LogoutUtilityPageType,
+	NotFoundUtilityPageType,
+	OfflineUtilityPageType,
+}
+
+// SpecUtilityPageTypes are the utility page types referenced from spec.utilityPages.
+var SpecUtilityPageTypes = []kdexv1alpha1.KDexUtilityPageType{
+	kdexv1alpha1.AnnouncementUtilityPageType,
+	kdexv1alpha1.ErrorUtilityPageType,
+	kdexv1alpha1.LoginUtilityPageType,
+}
+
+// DefaultErrorPages maps the HTTP error statuses served a page of their own by default.
+var DefaultErrorPages = map[int]kdexv1alpha1.KDexUtilityPageType{
+	http.StatusForbidden: ForbiddenUtilityPageType,
+	http.StatusNotFound:  NotFoundUtilityPageType,
+}
+
+var utilityPageTypePattern = regexp.MustCompile(`^[A-Z][A-Za-z0-9]{0,62}$`)
+
+// ValidUtilityPageType reports whether pageType can name a utility page type: an upper camel case word, which also
+// keeps the names of the internal utility pages valid.
+func ValidUtilityPageType(pageType kdexv1alpha1.KDexUtilityPageType) bool {
+	return utilityPageTypePattern.MatchString(string(pageType))
+}
+
+// GetUtilityPageType returns the utility page type of a page: the type declared in its annotations or else specType.
+func GetUtilityPageType(annotations map[string]string, specType kdexv1alpha1.KDexUtilityPageType) kdexv1alpha1.KDexUtilityPageType {
+	if pageType := strings.TrimSpace(annotations[UtilityPageTypeAnnotation]); pageType != "" {
+		return kdexv1alpha1.KDexUtilityPageType(pageType)
+	}
+	return specType
+}
+
+// GetUtilityPages returns the utility pages found in annotations. References without a kind reference a
+// KDexClusterUtilityPage, as do those of spec.utilityPages.
+func GetUtilityPages(annotations map[string]string) (map[kdexv1alpha1.KDexUtilityPageType]*kdexv1alpha1.KDexObjectReference, error) {
+	pages := map[kdexv1alpha1.KDexUtilityPageType]*kdexv1alpha1.KDexObjectReference{}
+
+	value := annotations[UtilityPagesAnnotation]
+	if strings.TrimSpace(value) == "" {
+		return pages, nil
+	}
+
+	if err := yaml.UnmarshalStrict([]byte(value), &pages); err != nil {
+		return nil, fmt.Errorf("%s is invalid: %w", UtilityPagesAnnotation, err)
+	}
+
+	for _, ref := range pages {
+		if ref != nil && ref.Kind == "" {
+			ref.Kind = "KDexClusterUtilityPage"
+		}
+	}
+
+	return pages, nil
+}
+
+// GetErrorPages returns the utility page type served for each HTTP error status: DefaultErrorPages overridden by the
+// statuses found in annotations.
+func GetErrorPages(annotations map[string]string) (map[int]kdexv1alpha1.KDexUtilityPageType, error) {
+	pages := maps.Clone(DefaultErrorPages)
+
+	value := annotations[ErrorPagesAnnotation]
+	if strings.TrimSpace(value) == "" {
+		return pages, nil
+	}
+
+	overrides := map[string]kdexv1alpha1.KDexUtilityPageType{}
+	if err := yaml.UnmarshalStrict([]byte(value), &overrides); err != nil {
+		return nil, fmt.Errorf("%s is invalid: %w", ErrorPagesAnnotation, err)
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(overrides)) {
+		status, err := strconv.Atoi(strings.TrimSpace(key))
+		if err != nil || status < 400 || status > 599 {
+			return nil, fmt.Errorf("%s: %q is not an HTTP error status", ErrorPagesAnnotation, key)
+		}
+		if pageType := overrides[key]; pageType != "" {
+			pages[status] = pageType
+		} else {
+			delete(pages, status)
+		}
+	}
+
+	return pages, nil
+}
@@ -0,0 +1,183 @@
+package metrics
+
+import (
+	"net/url"
+	"reflect"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const namespace = "kdex_nexus"
+
+// Outcomes of a package validation.
+const (
+	OutcomeFailure = "failure"
+	OutcomeSuccess = "success"
+)
+
+// Reasons of a reference resolution error.
+const (
+	ReasonError       = "Error"
+	ReasonNotFound    = "NotFound"
+	ReasonUnknownKind = "UnknownKind"
+)
+
+var (
+	ReferenceResolutionDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "reference_resolution_duration_seconds",
+			Help:      "Latency of the lookups of referenced KDex objects, by kind of the referenced object.",
+			Buckets:   prometheus.ExponentialBuckets(0.0005, 2, 12),
+		},
+		[]string{"kind"},
+	)
+
+	ReferenceResolutionErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "reference_resolution_errors_total",
+			Help:      "Lookups of referenced KDex objects which failed, by kind of the referenced object and reason.",
+		},
+		[]string{"kind", "reason"},
+	)
+
+	PackageValidations = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "package_validations_total",
+			Help:      "Validations of npm packages against their registry, by registry host and outcome.",
+		},
+		[]string{"registry", "outcome"},
+	)
+
+	PackageValidationDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "package_validation_duration_seconds",
+			Help:      "Latency of the validations of npm packages against their registry, by registry host.",
+			Buckets:   prometheus.ExponentialBuckets(0.01, 2, 12),
+		},
+		[]string{"registry"},
+	)
+
+	WebhookRejections = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "webhook_rejections_total",
+			Help:      "Admission requests rejected by the validating webhooks, by kind, operation and reason.",
+		},
+		[]string{"kind", "operation", "reason"},
+	)
+
+	ConfigReloadErrors = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "config_reload_errors_total",
+			Help:      "Changes to the configuration file which were rejected, keeping the running configuration.",
+		},
+	)
+
+	ConfigLastReloadSuccessful = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "config_last_reload_successful",
+			Help:      "Whether the last load of the configuration file succeeded.",
+		},
+	)
+
+	HostTimeToReady = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "host_time_to_ready_seconds",
+			Help:      "Time from the creation of a KDexHost until it first becomes ready.",
+			Buckets:   prometheus.ExponentialBuckets(1, 2, 13),
+		},
+	)
+)
+
+func init() {
+	crmetrics.Registry.MustRegister(
+		ReferenceResolutionDuration,
+		ReferenceResolutionErrors,
+		PackageValidations,
+		PackageValidationDuration,
+		WebhookRejections,
+		ConfigReloadErrors,
+		ConfigLastReloadSuccessful,
+		HostTimeToReady,
+	)
+}
+
+// ObserveConfigLoad records the outcome of a load of the configuration file, which failed when err is not nil. A
+// failed reload is also counted as an error.
+func ObserveConfigLoad(reload bool, err error) {
+	if err == nil {
+		ConfigLastReloadSuccessful.Set(1)
+		return
+	}
+
+	ConfigLastReloadSuccessful.Set(0)
+	if reload {
+		ConfigReloadErrors.Inc()
+	}
+}
+
+// ObservePackageValidation records a package validation against registry, which took the time since start and failed
+// when err is not nil. An empty registry stands for the default registry.
+func ObservePackageValidation(registry string, start time.Time, err error) {
+	host := RegistryHost(registry)
+	outcome := OutcomeSuccess
+	if err != nil {
+		outcome = OutcomeFailure
+	}
+
+	PackageValidations.WithLabelValues(host, outcome).Inc()
+	PackageValidationDuration.WithLabelValues(host).Observe(time.Since(start).Seconds())
+}
+
+// ObserveWebhookRejection records the rejection of an admission request for obj. The reason is that of the API status
+// carried by err, and Forbidden, the status the webhook server answers with, for any other error.
+func ObserveWebhookRejection(obj any, operation string, err error) {
+	reason := errors.ReasonForError(err)
+	if reason == metav1.StatusReasonUnknown {
+		reason = metav1.StatusReasonForbidden
+	}
+
+	WebhookRejections.WithLabelValues(Kind(obj), operation, string(reason)).Inc()
+}
+
+// ObserveHostReady records the time a host took to become ready since its creation.
+func ObserveHostReady(created metav1.Time, ready time.Time) {
+	HostTimeToReady.Observe(ready.Sub(created.Time).Seconds())
+}
+
+// Kind returns the name of the type of obj, dereferencing pointers.
+func Kind(obj any) string {
+	t := reflect.TypeOf(obj)
+	if t == nil {
+		return ""
+	}
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Name()
+}
+
+// RegistryHost returns the host of a registry URL, keeping the label cardinality bounded by the number of registries
+// rather than of their paths.
+func RegistryHost(registry string) string {
+	if registry == "" {
+		return "default"
+	}
+
+	if u, err := url.Parse(registry); err == nil && u.Host != "" {
+		return u.Host
+	}
+
+	return registry
+}
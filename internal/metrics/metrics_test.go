@@ -0,0 +1,114 @@
+package metrics
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	kdexv1alpha1 "kdex.dev/crds/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_RegistryHost(t *testing.T) {
+	tests := []struct {
+		name     string
+		registry string
+		want     string
+	}{
+		{name: "default", registry: "", want: "default"},
+		{name: "url", registry: "https://registry.npmjs.org/", want: "registry.npmjs.org"},
+		{name: "url with port and path", registry: "http://verdaccio:4873/npm", want: "verdaccio:4873"},
+		{name: "bare host", registry: "registry.npmjs.org", want: "registry.npmjs.org"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, RegistryHost(tt.registry))
+		})
+	}
+}
+
+func Test_ObserveWebhookRejection(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		reason string
+	}{
+		{
+			name:   "plain error",
+			err:    errors.New("spec.routing.domains is required"),
+			reason: string(metav1.StatusReasonForbidden),
+		},
+		{
+			name:   "status error",
+			err:    apierrors.NewInvalid(schema.GroupKind{Group: "kdex.dev", Kind: "KDexHost"}, "host", nil),
+			reason: string(metav1.StatusReasonInvalid),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counter := WebhookRejections.WithLabelValues("KDexHost", "CREATE", tt.reason)
+			before := testutil.ToFloat64(counter)
+
+			ObserveWebhookRejection(&kdexv1alpha1.KDexHost{}, "CREATE", tt.err)
+
+			assert.Equal(t, before+1, testutil.ToFloat64(counter))
+		})
+	}
+}
+
+func Test_ObjectCollector(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, kdexv1alpha1.AddToScheme(scheme))
+
+	theme := func(name string, namespace string, conditions ...metav1.Condition) *kdexv1alpha1.KDexTheme {
+		return &kdexv1alpha1.KDexTheme{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Status:     kdexv1alpha1.KDexObjectStatus{Conditions: conditions},
+		}
+	}
+	ready := metav1.Condition{Type: string(kdexv1alpha1.ConditionTypeReady), Status: metav1.ConditionTrue}
+	degraded := metav1.Condition{Type: string(kdexv1alpha1.ConditionTypeDegraded), Status: metav1.ConditionTrue}
+
+	reader := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(
+			theme("a", "one", ready),
+			theme("b", "one", ready),
+			theme("c", "one", degraded),
+			theme("d", "two"),
+			&kdexv1alpha1.KDexClusterTheme{
+				ObjectMeta: metav1.ObjectMeta{Name: "e"},
+				Status:     kdexv1alpha1.KDexObjectStatus{Conditions: []metav1.Condition{ready}},
+			},
+			&kdexv1alpha1.KDexFunction{
+				ObjectMeta: metav1.ObjectMeta{Name: "f", Namespace: "one"},
+				Status: kdexv1alpha1.KDexFunctionStatus{
+					KDexObjectStatus: kdexv1alpha1.KDexObjectStatus{Conditions: []metav1.Condition{degraded}},
+				},
+			},
+		).
+		Build()
+
+	collector := &ObjectCollector{Log: logr.Discard(), Reader: reader}
+
+	expected := `
+# HELP kdex_nexus_objects Number of KDex objects by kind, namespace and state.
+# TYPE kdex_nexus_objects gauge
+kdex_nexus_objects{kind="KDexClusterTheme",namespace="",state="Ready"} 1
+kdex_nexus_objects{kind="KDexFunction",namespace="one",state="Degraded"} 1
+kdex_nexus_objects{kind="KDexTheme",namespace="one",state="Degraded"} 1
+kdex_nexus_objects{kind="KDexTheme",namespace="one",state="Ready"} 2
+kdex_nexus_objects{kind="KDexTheme",namespace="two",state="Progressing"} 1
+`
+
+	assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected)))
+}
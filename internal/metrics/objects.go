@@ -0,0 +1,142 @@
+package metrics
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	kdexv1alpha1 "kdex.dev/crds/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Object states reported by ObjectCollector.
+const (
+	StateDegraded    = "Degraded"
+	StateProgressing = "Progressing"
+	StateReady       = "Ready"
+)
+
+// objectCollectTimeout bounds the listing of the objects during a scrape.
+const objectCollectTimeout = 5 * time.Second
+
+var objectsDesc = prometheus.NewDesc(
+	prometheus.BuildFQName(namespace, "", "objects"),
+	"Number of KDex objects by kind, namespace and state.",
+	[]string{"kind", "namespace", "state"},
+	nil,
+)
+
+// ObjectCollector counts the KDex objects reconciled by the manager at scrape time. Reading from the manager cache
+// keeps a scrape from reaching the API server.
+type ObjectCollector struct {
+	Log    logr.Logger
+	Reader client.Reader
+}
+
+var _ prometheus.Collector = &ObjectCollector{}
+
+func (c *ObjectCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- objectsDesc
+}
+
+func (c *ObjectCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), objectCollectTimeout)
+	defer cancel()
+
+	for _, list := range objectLists() {
+		if err := c.Reader.List(ctx, list); err != nil {
+			c.Log.V(1).Info("unable to list objects for metrics", "list", Kind(list), "err", err)
+			continue
+		}
+
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			continue
+		}
+
+		type key struct{ kind, namespace, state string }
+		counts := map[key]int{}
+		for _, item := range items {
+			obj, ok := item.(client.Object)
+			if !ok {
+				continue
+			}
+			counts[key{Kind(obj), obj.GetNamespace(), State(obj)}]++
+		}
+
+		for k, count := range counts {
+			ch <- prometheus.MustNewConstMetric(objectsDesc, prometheus.GaugeValue, float64(count), k.kind, k.namespace, k.state)
+		}
+	}
+}
+
+// State returns the state of a KDex object as told by its conditions: Ready, Degraded, or Progressing for anything
+// else, including an object which was not reconciled yet.
+func State(obj client.Object) string {
+	status, ok := objectStatus(obj)
+	if !ok {
+		return StateProgressing
+	}
+
+	switch {
+	case meta.IsStatusConditionTrue(status.Conditions, string(kdexv1alpha1.ConditionTypeReady)):
+		return StateReady
+	case meta.IsStatusConditionTrue(status.Conditions, string(kdexv1alpha1.ConditionTypeDegraded)):
+		return StateDegraded
+	default:
+		return StateProgressing
+	}
+}
+
+// objectStatus returns the status of a KDex object, all of which share the same status type, either as their status
+// or embedded in it.
+func objectStatus(obj client.Object) (kdexv1alpha1.KDexObjectStatus, bool) {
+	field := reflect.Indirect(reflect.ValueOf(obj)).FieldByName("Status")
+	if !field.IsValid() {
+		return kdexv1alpha1.KDexObjectStatus{}, false
+	}
+
+	if status, ok := field.Interface().(kdexv1alpha1.KDexObjectStatus); ok {
+		return status, true
+	}
+
+	if field.Kind() == reflect.Struct {
+		if embedded := field.FieldByName("KDexObjectStatus"); embedded.IsValid() {
+			status, ok := embedded.Interface().(kdexv1alpha1.KDexObjectStatus)
+			return status, ok
+		}
+	}
+
+	return kdexv1alpha1.KDexObjectStatus{}, false
+}
+
+func objectLists() []client.ObjectList {
+	return []client.ObjectList{
+		&kdexv1alpha1.KDexAppList{},
+		&kdexv1alpha1.KDexClusterAppList{},
+		&kdexv1alpha1.KDexClusterFaaSAdaptorList{},
+		&kdexv1alpha1.KDexClusterPageArchetypeList{},
+		&kdexv1alpha1.KDexClusterPageFooterList{},
+		&kdexv1alpha1.KDexClusterPageHeaderList{},
+		&kdexv1alpha1.KDexClusterPageNavigationList{},
+		&kdexv1alpha1.KDexClusterScriptLibraryList{},
+		&kdexv1alpha1.KDexClusterThemeList{},
+		&kdexv1alpha1.KDexClusterTranslationList{},
+		&kdexv1alpha1.KDexClusterUtilityPageList{},
+		&kdexv1alpha1.KDexFaaSAdaptorList{},
+		&kdexv1alpha1.KDexFunctionList{},
+		&kdexv1alpha1.KDexHostList{},
+		&kdexv1alpha1.KDexPageArchetypeList{},
+		&kdexv1alpha1.KDexPageBindingList{},
+		&kdexv1alpha1.KDexPageFooterList{},
+		&kdexv1alpha1.KDexPageHeaderList{},
+		&kdexv1alpha1.KDexPageNavigationList{},
+		&kdexv1alpha1.KDexScriptLibraryList{},
+		&kdexv1alpha1.KDexThemeList{},
+		&kdexv1alpha1.KDexTranslationList{},
+		&kdexv1alpha1.KDexUtilityPageList{},
+	}
+}
@@ -0,0 +1,274 @@
+package patch
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// DeploymentPatchAnnotation holds a patch, in YAML or JSON, applied over the Deployment generated for a host.
+	DeploymentPatchAnnotation = "kdex.dev/deployment-patch"
+	// DeploymentPatchTypeAnnotation selects how DeploymentPatchAnnotation is applied. It is either
+	// StrategicMergePatchType, the default, or JSONPatchType.
+	DeploymentPatchTypeAnnotation = "kdex.dev/deployment-patch-type"
+
+	JSONPatchType           = "json"
+	StrategicMergePatchType = "strategic"
+
+	configVolumeName = "config"
+	focalHostFlag    = "--focal-host"
+	serviceNameFlag  = "--service-name"
+
+	// The pod labels the Deployment selects and the Service of the host routes to.
+	instanceLabel = "kdex.dev/instance"
+	nameLabel     = "app.kubernetes.io/name"
+)
+
+var volumeIndexPath = regexp.MustCompile(`^/spec/template/spec/volumes/(\d+|-)$`)
+
+// ApplyToDeployment applies the deployment patch found in annotations, if any, to deployment.
+func ApplyToDeployment(deployment *appsv1.Deployment, annotations map[string]string) error {
+	patch, ok := annotations[DeploymentPatchAnnotation]
+	if !ok || strings.TrimSpace(patch) == "" {
+		return nil
+	}
+
+	patchJSON, err := yaml.YAMLToJSON([]byte(patch))
+	if err != nil {
+		return fmt.Errorf("%s is not valid YAML or JSON: %w", DeploymentPatchAnnotation, err)
+	}
+
+	original, err := json.Marshal(deployment)
+	if err != nil {
+		return err
+	}
+
+	var patched []byte
+	switch patchType := annotations[DeploymentPatchTypeAnnotation]; patchType {
+	case "", StrategicMergePatchType:
+		patched, err = strategicpatch.StrategicMergePatch(original, patchJSON, &appsv1.Deployment{})
+	case JSONPatchType:
+		var operations jsonpatch.Patch
+		operations, err = jsonpatch.DecodePatch(patchJSON)
+		if err == nil {
+			patched, err = operations.Apply(original)
+		}
+	default:
+		return fmt.Errorf("%s must be one of %s or %s, got %q",
+			DeploymentPatchTypeAnnotation, StrategicMergePatchType, JSONPatchType, patchType)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to apply %s: %w", DeploymentPatchAnnotation, err)
+	}
+
+	result := &appsv1.Deployment{}
+	if err := json.Unmarshal(patched, result); err != nil {
+		return fmt.Errorf("failed to apply %s: %w", DeploymentPatchAnnotation, err)
+	}
+
+	// Merging lists by key orders new items ahead of existing ones, so sidecars would displace the host container
+	// from the first position the operator relies on.
+	if len(deployment.Spec.Template.Spec.Containers) > 0 {
+		keepFirst(result.Spec.Template.Spec.Containers, deployment.Spec.Template.Spec.Containers[0].Name)
+	}
+
+	*deployment = *result
+
+	return nil
+}
+
+// VerifyOwnedFields returns an error when the fields the operator manages on a host Deployment differ between before
+// and after: the metadata of the Deployment, its selector, the app.kubernetes.io/name and kdex.dev/instance labels of
+// its pods, the name of the first container, its --focal-host and --service-name arguments, the service account and
+// the config volume.
+func VerifyOwnedFields(before *appsv1.Deployment, after *appsv1.Deployment) error {
+	beforeSpec := &before.Spec.Template.Spec
+	afterSpec := &after.Spec.Template.Spec
+
+	// The Deployment is looked up by name and deleted through its owner, labels and finalizers.
+	if !equality.Semantic.DeepEqual(before.ObjectMeta, after.ObjectMeta) {
+		return fmt.Errorf("deployment patch must not change the metadata of the deployment")
+	}
+
+	if !equality.Semantic.DeepEqual(before.Spec.Selector, after.Spec.Selector) {
+		return fmt.Errorf("deployment patch must not change the selector")
+	}
+
+	for _, label := range []string{nameLabel, instanceLabel} {
+		if before.Spec.Template.Labels[label] != after.Spec.Template.Labels[label] {
+			return fmt.Errorf("deployment patch must not change the %s label of the pods", label)
+		}
+	}
+
+	if len(afterSpec.Containers) == 0 || afterSpec.Containers[0].Name != beforeSpec.Containers[0].Name {
+		return fmt.Errorf("deployment patch must not rename or remove container %s",
+			beforeSpec.Containers[0].Name)
+	}
+
+	if !reflect.DeepEqual(ownedArgs(beforeSpec.Containers[0]), ownedArgs(afterSpec.Containers[0])) {
+		return fmt.Errorf("deployment patch must not change the %s or %s arguments", focalHostFlag, serviceNameFlag)
+	}
+
+	if afterSpec.ServiceAccountName != beforeSpec.ServiceAccountName ||
+		afterSpec.DeprecatedServiceAccount != beforeSpec.DeprecatedServiceAccount {
+		return fmt.Errorf("deployment patch must not change the service account")
+	}
+
+	if !reflect.DeepEqual(configVolume(beforeSpec), configVolume(afterSpec)) {
+		return fmt.Errorf("deployment patch must not change the %s volume", configVolumeName)
+	}
+
+	return nil
+}
+
+// ValidateJSONPatch statically rejects JSON patch operations that touch operator owned fields. Unlike a strategic
+// merge patch, a JSON patch addresses list items by position so it cannot be checked against a stand-in Deployment.
+func ValidateJSONPatch(patch []byte) error {
+	operations, err := jsonpatch.DecodePatch(patch)
+	if err != nil {
+		return err
+	}
+
+	owned := []string{
+		"/metadata",
+		"/spec/selector",
+		"/spec/template/metadata/labels/" + escapePointer(instanceLabel),
+		"/spec/template/metadata/labels/" + escapePointer(nameLabel),
+		"/spec/template/spec/containers/0/args",
+		"/spec/template/spec/containers/0/command",
+		"/spec/template/spec/containers/0/name",
+		"/spec/template/spec/serviceAccount",
+		"/spec/template/spec/serviceAccountName",
+	}
+
+	for _, operation := range operations {
+		if operation.Kind() == "test" {
+			continue
+		}
+
+		paths := []string{}
+		if path, err := operation.Path(); err == nil {
+			paths = append(paths, path)
+		}
+		if from, err := operation.From(); err == nil && operation.Kind() == "move" {
+			paths = append(paths, from)
+		}
+
+		for _, path := range paths {
+			for _, ownedPath := range owned {
+				if related(path, ownedPath) {
+					return fmt.Errorf("deployment patch must not %s %s", operation.Kind(), path)
+				}
+			}
+
+			// Volumes can be added but not changed since the config volume cannot be located by position.
+			if related(path, "/spec/template/spec/volumes") &&
+				(operation.Kind() != "add" || !volumeIndexPath.MatchString(path)) {
+				return fmt.Errorf("deployment patch must not %s %s", operation.Kind(), path)
+			}
+		}
+	}
+
+	return nil
+}
+
+// ProbeDeployment returns a minimal stand-in for the Deployment generated for hostName, holding only the operator
+// owned fields, against which a strategic merge patch can be checked.
+func ProbeDeployment(hostName string) *appsv1.Deployment {
+	labels := map[string]string{
+		instanceLabel: hostName,
+		nameLabel:     "kdex-host",
+	}
+
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: maps.Clone(labels),
+			Name:   hostName,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: maps.Clone(labels),
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: maps.Clone(labels),
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Args: []string{
+								focalHostFlag + "=" + hostName,
+								serviceNameFlag + "=" + hostName,
+							},
+							Name: hostName,
+						},
+					},
+					ServiceAccountName: hostName,
+					Volumes: []corev1.Volume{
+						{
+							Name: configVolumeName,
+							VolumeSource: corev1.VolumeSource{
+								ConfigMap: &corev1.ConfigMapVolumeSource{
+									LocalObjectReference: corev1.LocalObjectReference{
+										Name: hostName,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func configVolume(spec *corev1.PodSpec) *corev1.Volume {
+	for _, volume := range spec.Volumes {
+		if volume.Name == configVolumeName {
+			return &volume
+		}
+	}
+	return nil
+}
+
+func keepFirst(containers []corev1.Container, name string) {
+	for idx, container := range containers {
+		if container.Name == name {
+			copy(containers[1:idx+1], containers[:idx])
+			containers[0] = container
+			return
+		}
+	}
+}
+
+func ownedArgs(container corev1.Container) []string {
+	args := []string{}
+	for _, value := range slices.Concat(container.Command, container.Args) {
+		if strings.Contains(value, focalHostFlag) || strings.Contains(value, serviceNameFlag) {
+			args = append(args, value)
+		}
+	}
+	return args
+}
+
+// escapePointer escapes a map key for use in a JSON pointer.
+func escapePointer(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
+
+func related(path string, other string) bool {
+	return path == other || strings.HasPrefix(path, other+"/") || strings.HasPrefix(other, path+"/")
+}
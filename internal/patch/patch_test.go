@@ -0,0 +1,260 @@
+package patch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ApplyToDeployment(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		wantErr     bool
+		wantOwned   bool
+	}{
+		{
+			name:        "no patch",
+			annotations: map[string]string{},
+			wantOwned:   true,
+		},
+		{
+			name: "strategic merge adds a sidecar and scheduling",
+			annotations: map[string]string{
+				DeploymentPatchAnnotation: `
+spec:
+  template:
+    metadata:
+      annotations:
+        example.com/scrape: "true"
+    spec:
+      nodeSelector:
+        kubernetes.io/arch: arm64
+      containers:
+      - name: sidecar
+        image: busybox
+      volumes:
+      - name: extra
+        emptyDir: {}
+`,
+			},
+			wantOwned: true,
+		},
+		{
+			name: "strategic merge changes the service account",
+			annotations: map[string]string{
+				DeploymentPatchAnnotation: `{"spec":{"template":{"spec":{"serviceAccountName":"other"}}}}`,
+			},
+			wantOwned: false,
+		},
+		{
+			name: "strategic merge replaces the owned args",
+			annotations: map[string]string{
+				DeploymentPatchAnnotation: `
+spec:
+  template:
+    spec:
+      containers:
+      - name: host
+        args: ["--focal-host=other"]
+`,
+			},
+			wantOwned: false,
+		},
+		{
+			name: "strategic merge changes the config volume",
+			annotations: map[string]string{
+				DeploymentPatchAnnotation: `
+spec:
+  template:
+    spec:
+      volumes:
+      - name: config
+        configMap:
+          name: other
+`,
+			},
+			wantOwned: false,
+		},
+		{
+			name: "strategic merge renames the deployment",
+			annotations: map[string]string{
+				DeploymentPatchAnnotation: `{"metadata":{"name":"other"}}`,
+			},
+			wantOwned: false,
+		},
+		{
+			name: "strategic merge adds a finalizer",
+			annotations: map[string]string{
+				DeploymentPatchAnnotation: `{"metadata":{"finalizers":["example.com/hold"]}}`,
+			},
+			wantOwned: false,
+		},
+		{
+			name: "strategic merge changes the selector",
+			annotations: map[string]string{
+				DeploymentPatchAnnotation: `{"spec":{"selector":{"matchLabels":{"kdex.dev/instance":"other"}}}}`,
+			},
+			wantOwned: false,
+		},
+		{
+			name: "strategic merge changes the instance label of the pods",
+			annotations: map[string]string{
+				DeploymentPatchAnnotation: `{"spec":{"template":{"metadata":{"labels":{"kdex.dev/instance":"other"}}}}}`,
+			},
+			wantOwned: false,
+		},
+		{
+			name: "strategic merge adds a label to the pods",
+			annotations: map[string]string{
+				DeploymentPatchAnnotation: `{"spec":{"template":{"metadata":{"labels":{"example.com/team":"web"}}}}}`,
+			},
+			wantOwned: true,
+		},
+		{
+			name: "json patch adds a toleration",
+			annotations: map[string]string{
+				DeploymentPatchTypeAnnotation: JSONPatchType,
+				DeploymentPatchAnnotation:     `[{"op":"add","path":"/spec/template/spec/tolerations","value":[{"key":"dedicated","operator":"Exists"}]}]`,
+			},
+			wantOwned: true,
+		},
+		{
+			name: "json patch renames the container",
+			annotations: map[string]string{
+				DeploymentPatchTypeAnnotation: JSONPatchType,
+				DeploymentPatchAnnotation:     `[{"op":"replace","path":"/spec/template/spec/containers/0/name","value":"other"}]`,
+			},
+			wantOwned: false,
+		},
+		{
+			name: "unknown patch type",
+			annotations: map[string]string{
+				DeploymentPatchTypeAnnotation: "merge",
+				DeploymentPatchAnnotation:     `{}`,
+			},
+			wantErr: true,
+		},
+		{
+			name: "malformed patch",
+			annotations: map[string]string{
+				DeploymentPatchAnnotation: `spec: [`,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deployment := ProbeDeployment("host")
+			before := deployment.DeepCopy()
+
+			err := ApplyToDeployment(deployment, tt.annotations)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+
+			err = VerifyOwnedFields(before, deployment)
+			if tt.wantOwned {
+				assert.NoError(t, err)
+				return
+			}
+			assert.Error(t, err)
+		})
+	}
+}
+
+func Test_ValidateJSONPatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		patch   string
+		wantErr bool
+	}{
+		{
+			name:  "add sidecar",
+			patch: `[{"op":"add","path":"/spec/template/spec/containers/-","value":{"name":"sidecar","image":"busybox"}}]`,
+		},
+		{
+			name:  "add env to the host container",
+			patch: `[{"op":"add","path":"/spec/template/spec/containers/0/env/-","value":{"name":"A","value":"b"}}]`,
+		},
+		{
+			name:  "add volume",
+			patch: `[{"op":"add","path":"/spec/template/spec/volumes/-","value":{"name":"extra","emptyDir":{}}}]`,
+		},
+		{
+			name:  "set strategy",
+			patch: `[{"op":"replace","path":"/spec/strategy","value":{"type":"Recreate"}}]`,
+		},
+		{
+			name:  "add a label to the pods",
+			patch: `[{"op":"add","path":"/spec/template/metadata/labels/example.com~1team","value":"web"}]`,
+		},
+		{
+			name:    "rename the deployment",
+			patch:   `[{"op":"replace","path":"/metadata/name","value":"other"}]`,
+			wantErr: true,
+		},
+		{
+			name:    "replace the owner references",
+			patch:   `[{"op":"replace","path":"/metadata/ownerReferences","value":[]}]`,
+			wantErr: true,
+		},
+		{
+			name:    "replace the selector",
+			patch:   `[{"op":"replace","path":"/spec/selector/matchLabels","value":{}}]`,
+			wantErr: true,
+		},
+		{
+			name:    "replace the name label of the pods",
+			patch:   `[{"op":"replace","path":"/spec/template/metadata/labels/app.kubernetes.io~1name","value":"other"}]`,
+			wantErr: true,
+		},
+		{
+			name:    "replace the labels of the pods",
+			patch:   `[{"op":"replace","path":"/spec/template/metadata/labels","value":{}}]`,
+			wantErr: true,
+		},
+		{
+			name:    "replace args",
+			patch:   `[{"op":"replace","path":"/spec/template/spec/containers/0/args","value":[]}]`,
+			wantErr: true,
+		},
+		{
+			name:    "insert container before the host container",
+			patch:   `[{"op":"add","path":"/spec/template/spec/containers/0","value":{"name":"first"}}]`,
+			wantErr: true,
+		},
+		{
+			name:    "move the host container",
+			patch:   `[{"op":"move","from":"/spec/template/spec/containers/0","path":"/spec/template/spec/containers/-"}]`,
+			wantErr: true,
+		},
+		{
+			name:    "replace service account",
+			patch:   `[{"op":"replace","path":"/spec/template/spec/serviceAccountName","value":"other"}]`,
+			wantErr: true,
+		},
+		{
+			name:    "remove a volume",
+			patch:   `[{"op":"remove","path":"/spec/template/spec/volumes/0"}]`,
+			wantErr: true,
+		},
+		{
+			name:    "replace the pod spec",
+			patch:   `[{"op":"replace","path":"/spec/template/spec","value":{}}]`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateJSONPatch([]byte(tt.patch))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
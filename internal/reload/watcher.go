@@ -0,0 +1,164 @@
+package reload
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/kdex-tech/nexus-manager/internal/extensions"
+	"github.com/kdex-tech/nexus-manager/internal/metrics"
+	"github.com/kdex-tech/nexus-manager/internal/validation"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/events"
+	"kdex.dev/crds/configuration"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// Watcher polls the manager configuration file and hands every new, valid configuration, along with its extension
+// settings, to its listeners. A configuration that fails to load or validate is rejected so the running configuration
+// stays in effect: it is logged, counted in the config reload metrics and, given a Recorder and the object Regarding
+// the manager, reported in a Warning event.
+//
+// The file is polled rather than watched because ConfigMap volumes are updated by swapping symlinks, which file
+// notifications do not report reliably.
+type Watcher struct {
+	File      string
+	Interval  time.Duration
+	Listeners []func(context.Context, configuration.NexusConfiguration, extensions.Configuration)
+	Log       logr.Logger
+	Recorder  events.EventRecorder
+	Regarding runtime.Object
+	Scheme    *runtime.Scheme
+
+	checksum [sha256.Size]byte
+}
+
+// Events reported by Watcher.
+const (
+	EventReasonConfigurationRejected = "ConfigurationRejected"
+
+	eventActionReload = "Reload"
+)
+
+var _ manager.Runnable = &Watcher{}
+var _ manager.LeaderElectionRunnable = &Watcher{}
+
+// Load reads and validates the configuration file, remembering its content so that only later changes are reported
+// by Start. Unlike a reload, which is rejected, an invalid configuration is returned along with the error so that the
+// manager can still start: the configuration as loaded, and the default extension settings when those are invalid.
+func (w *Watcher) Load() (configuration.NexusConfiguration, extensions.Configuration, error) {
+	checksum, err := w.fileChecksum()
+	if err != nil {
+		return configuration.NexusConfiguration{}, extensions.Configuration{}, err
+	}
+
+	w.checksum = checksum
+
+	config, ext, err := w.load()
+	metrics.ObserveConfigLoad(false, err)
+
+	return config, ext, err
+}
+
+// Start polls the configuration file until ctx is cancelled.
+func (w *Watcher) Start(ctx context.Context) error {
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			w.poll(ctx)
+		}
+	}
+}
+
+// NeedLeaderElection returns false because every replica serves webhooks which depend on the configuration.
+func (w *Watcher) NeedLeaderElection() bool {
+	return false
+}
+
+func (w *Watcher) poll(ctx context.Context) {
+	checksum, err := w.fileChecksum()
+	if err != nil {
+		w.reject(fmt.Errorf("failed to read configuration: %w", err))
+		return
+	}
+
+	if checksum == w.checksum {
+		return
+	}
+
+	// The checksum is recorded even when the new content is rejected so that the same error is reported only once.
+	w.checksum = checksum
+
+	config, ext, err := w.load()
+	if err != nil {
+		w.reject(err)
+		return
+	}
+
+	metrics.ObserveConfigLoad(true, nil)
+
+	w.Log.Info("reloading configuration", "file", w.File)
+
+	for _, listener := range w.Listeners {
+		listener(ctx, config, ext)
+	}
+}
+
+// reject reports a reload which failed with err.
+func (w *Watcher) reject(err error) {
+	w.Log.Error(err, "rejected configuration reload, keeping the current configuration", "file", w.File)
+
+	metrics.ObserveConfigLoad(true, err)
+
+	if w.Recorder != nil && w.Regarding != nil {
+		w.Recorder.Eventf(w.Regarding, nil, corev1.EventTypeWarning, EventReasonConfigurationRejected, eventActionReload,
+			"Rejected the configuration in %s, keeping the current configuration: %v", w.File, err)
+	}
+}
+
+func (w *Watcher) fileChecksum() ([sha256.Size]byte, error) {
+	content, err := os.ReadFile(w.File)
+	if err != nil && !os.IsNotExist(err) {
+		return [sha256.Size]byte{}, err
+	}
+
+	return sha256.Sum256(content), nil
+}
+
+func (w *Watcher) load() (config configuration.NexusConfiguration, ext extensions.Configuration, err error) {
+	// LoadConfiguration panics on malformed content.
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("failed to load configuration: %v", r)
+		}
+	}()
+
+	config = configuration.LoadConfiguration(w.File, w.Scheme)
+
+	var configErr error
+	if err := validation.ValidateConfiguration(&config); err != nil {
+		configErr = fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	ext, extErr := extensions.Load(w.File)
+	if extErr == nil {
+		if err := validation.ValidateExtensions(&ext); err != nil {
+			extErr = fmt.Errorf("invalid configuration: %w", err)
+		}
+	}
+	if extErr != nil {
+		ext = extensions.Configuration{}
+	}
+
+	return config, ext, errors.Join(configErr, extErr)
+}
@@ -0,0 +1,93 @@
+package reload
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/kdex-tech/nexus-manager/internal/extensions"
+	"github.com/kdex-tech/nexus-manager/internal/metrics"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/tools/events"
+	"kdex.dev/crds/configuration"
+)
+
+func Test_Watcher(t *testing.T) {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(appsv1.AddToScheme(scheme))
+	utilruntime.Must(corev1.AddToScheme(scheme))
+	utilruntime.Must(rbacv1.AddToScheme(scheme))
+	utilruntime.Must(configuration.AddToScheme(scheme))
+
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	write := func(content string) {
+		assert.NoError(t, os.WriteFile(file, []byte(content), 0o600))
+	}
+
+	reloaded := []configuration.NexusConfiguration{}
+	recorder := events.NewFakeRecorder(10)
+	watcher := &Watcher{
+		File: file,
+		Listeners: []func(context.Context, configuration.NexusConfiguration, extensions.Configuration){
+			func(_ context.Context, config configuration.NexusConfiguration, _ extensions.Configuration) {
+				reloaded = append(reloaded, config)
+			},
+		},
+		Log:       logr.Discard(),
+		Recorder:  recorder,
+		Regarding: &corev1.ObjectReference{APIVersion: "v1", Kind: "Pod", Name: "manager", Namespace: "kdex"},
+		Scheme:    scheme,
+	}
+	reloadErrors := testutil.ToFloat64(metrics.ConfigReloadErrors)
+
+	write("defaultNpmRegistry:\n  host: npm.one\n")
+	config, _, err := watcher.Load()
+	assert.NoError(t, err)
+	assert.Equal(t, "npm.one", config.DefaultNpmRegistry.Host)
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.ConfigLastReloadSuccessful))
+
+	watcher.poll(context.Background())
+	assert.Empty(t, reloaded, "unchanged content is not reloaded")
+
+	write("defaultNpmRegistry:\n  host: npm.two\n")
+	watcher.poll(context.Background())
+	assert.Len(t, reloaded, 1)
+	assert.Equal(t, "npm.two", reloaded[0].DefaultNpmRegistry.Host)
+
+	write("defaultNpmRegistry: [\n")
+	watcher.poll(context.Background())
+	assert.Len(t, reloaded, 1, "malformed content is rejected")
+	assert.Equal(t, reloadErrors+1, testutil.ToFloat64(metrics.ConfigReloadErrors))
+	assert.Equal(t, 0.0, testutil.ToFloat64(metrics.ConfigLastReloadSuccessful))
+	assert.Contains(t, <-recorder.Events, "Warning "+EventReasonConfigurationRejected)
+
+	write("defaultNpmRegistry:\n  host: \"\"\n")
+	watcher.poll(context.Background())
+	assert.Len(t, reloaded, 1, "invalid content is rejected")
+
+	write("hostDefault:\n  podDisruptionBudget:\n    minAvailable: 1\n    maxUnavailable: 1\n")
+	watcher.poll(context.Background())
+	assert.Len(t, reloaded, 1, "invalid extensions are rejected")
+	assert.Equal(t, reloadErrors+3, testutil.ToFloat64(metrics.ConfigReloadErrors))
+	assert.Len(t, recorder.Events, 2)
+
+	write("defaultNpmRegistry:\n  host: npm.three\n")
+	watcher.poll(context.Background())
+	assert.Len(t, reloaded, 2)
+	assert.Equal(t, "npm.three", reloaded[1].DefaultNpmRegistry.Host)
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.ConfigLastReloadSuccessful))
+
+	write("defaultNpmRegistry:\n  host: npm.four\nhostDefault:\n  podDisruptionBudget:\n    minAvailable: 1\n    maxUnavailable: 1\n")
+	config, ext, err := watcher.Load()
+	assert.Error(t, err)
+	assert.Equal(t, "npm.four", config.DefaultNpmRegistry.Host, "the manager starts with an invalid configuration")
+	assert.Equal(t, extensions.Configuration{}, ext, "invalid extensions fall back to their defaults")
+}
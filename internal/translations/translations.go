@@ -0,0 +1,208 @@
+package translations
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	kdexv1alpha1 "kdex.dev/crds/api/v1alpha1"
+)
+
+// MaxOriginIndexSize bounds an OriginIndex to fit a ConfigMap, whose data and metadata are limited to 1 MiB.
+const MaxOriginIndexSize = 1<<20 - 64<<10
+
+// Source is a set of translations named after the object it comes from.
+type Source struct {
+	Name         string
+	Translations []kdexv1alpha1.Translation
+}
+
+// Merged is a precedence-resolved set of translations.
+type Merged struct {
+	// Translations holds one translation per language, sorted by language.
+	Translations []kdexv1alpha1.Translation
+
+	// Origins maps each language and key to the source and language which supplied the value, as "<source>/<lang>".
+	Origins map[string]map[string]string
+}
+
+// Chain returns the languages looked up for the keys of lang, lang first: the configured fallbacks of lang or, when
+// there are none, its parent languages (de-AT-1996 to de-AT to de); then defaultLang.
+func Chain(lang string, fallbacks map[string][]string, defaultLang string) []string {
+	chain := []string{lang}
+
+	if configured, ok := fallbacks[lang]; ok {
+		chain = append(chain, configured...)
+	} else {
+		for parent := lang; strings.Contains(parent, "-"); {
+			parent = parent[:strings.LastIndex(parent, "-")]
+			chain = append(chain, parent)
+		}
+	}
+
+	if defaultLang != "" {
+		chain = append(chain, defaultLang)
+	}
+
+	unique := []string{}
+	for _, l := range chain {
+		if l != "" && !slices.Contains(unique, l) {
+			unique = append(unique, l)
+		}
+	}
+
+	return unique
+}
+
+// Merge resolves the translations of sources, given in order of precedence. Every language of the sources, along with
+// every language of extraLangs, gets the keys of its chain: a key takes the value of the first language of the chain
+// which has it, from the first source which has it in that language.
+func Merge(sources []Source, extraLangs []string, chain func(lang string) []string) Merged {
+	// The last translation of a language within a source takes precedence, as in a KDexTranslation.
+	bySource := make([]map[string]map[string]string, len(sources))
+	langs := map[string]bool{}
+	for i, source := range sources {
+		bySource[i] = map[string]map[string]string{}
+		for _, translation := range source.Translations {
+			if bySource[i][translation.Lang] == nil {
+				bySource[i][translation.Lang] = map[string]string{}
+			}
+			maps.Copy(bySource[i][translation.Lang], translation.KeysAndValues)
+			langs[translation.Lang] = true
+		}
+	}
+	for _, lang := range extraLangs {
+		langs[lang] = true
+	}
+
+	merged := Merged{
+		Translations: []kdexv1alpha1.Translation{},
+		Origins:      map[string]map[string]string{},
+	}
+
+	for _, lang := range slices.Sorted(maps.Keys(langs)) {
+		keysAndValues := map[string]string{}
+		origins := map[string]string{}
+
+		for _, fallback := range chain(lang) {
+			for i, source := range sources {
+				for key, value := range bySource[i][fallback] {
+					if _, ok := keysAndValues[key]; !ok {
+						keysAndValues[key] = value
+						origins[key] = source.Name + "/" + fallback
+					}
+				}
+			}
+		}
+
+		if len(keysAndValues) == 0 {
+			continue
+		}
+
+		merged.Translations = append(merged.Translations, kdexv1alpha1.Translation{
+			Lang:          lang,
+			KeysAndValues: keysAndValues,
+		})
+		merged.Origins[lang] = origins
+	}
+
+	return merged
+}
+
+// Split divides translations into parts holding at most maxLangs languages and maxKeys keys per language, the limits
+// of a KDexTranslationSpec. The parts share no key of a language, so their order does not matter.
+func Split(translations []kdexv1alpha1.Translation, maxLangs int, maxKeys int) [][]kdexv1alpha1.Translation {
+	parts := [][]kdexv1alpha1.Translation{}
+
+	for start := 0; start < len(translations); start += maxLangs {
+		batch := translations[start:min(start+maxLangs, len(translations))]
+
+		for chunk := 0; ; chunk++ {
+			part := []kdexv1alpha1.Translation{}
+			for _, translation := range batch {
+				keys := slices.Sorted(maps.Keys(translation.KeysAndValues))
+				if chunk*maxKeys >= len(keys) {
+					continue
+				}
+				keysAndValues := map[string]string{}
+				for _, key := range keys[chunk*maxKeys : min((chunk+1)*maxKeys, len(keys))] {
+					keysAndValues[key] = translation.KeysAndValues[key]
+				}
+				part = append(part, kdexv1alpha1.Translation{Lang: translation.Lang, KeysAndValues: keysAndValues})
+			}
+			if len(part) == 0 {
+				break
+			}
+			parts = append(parts, part)
+		}
+	}
+
+	return parts
+}
+
+// OriginIndex lists, for each language of translations, its keys by the origin which supplied them: a JSON object of
+// origins to sorted keys, keyed by language. Each origin is stored once, so the index grows with the length of the keys
+// alone. A language whose keys would take the index past maxSize bytes is recorded by its Summary instead, under
+// "<lang>.summary", and left out when even that does not fit.
+func OriginIndex(
+	translations []kdexv1alpha1.Translation,
+	origins map[string]map[string]string,
+	maxSize int,
+) (map[string]string, error) {
+	index := map[string]string{}
+	size := 0
+
+	for _, translation := range translations {
+		byOrigin := map[string][]string{}
+		for _, key := range slices.Sorted(maps.Keys(translation.KeysAndValues)) {
+			origin := origins[translation.Lang][key]
+			byOrigin[origin] = append(byOrigin[origin], key)
+		}
+
+		entry, err := json.Marshal(byOrigin)
+		if err != nil {
+			return nil, err
+		}
+
+		if size+len(translation.Lang)+len(entry) <= maxSize {
+			index[translation.Lang] = string(entry)
+			size += len(translation.Lang) + len(entry)
+			continue
+		}
+
+		summaryKey := translation.Lang + ".summary"
+		summary := Summary(origins[translation.Lang])
+		if size+len(summaryKey)+len(summary) <= maxSize {
+			index[summaryKey] = summary
+			size += len(summaryKey) + len(summary)
+		}
+	}
+
+	return index, nil
+}
+
+// Summary describes the origins of the keys of a language: the number of keys each source and language supplied, in
+// order of the count and then of the origin.
+func Summary(origins map[string]string) string {
+	counts := map[string]int{}
+	for _, origin := range origins {
+		counts[origin]++
+	}
+
+	keys := slices.Collect(maps.Keys(counts))
+	slices.SortFunc(keys, func(a, b string) int {
+		if counts[a] != counts[b] {
+			return counts[b] - counts[a]
+		}
+		return strings.Compare(a, b)
+	})
+
+	entries := make([]string, 0, len(keys))
+	for _, key := range keys {
+		entries = append(entries, fmt.Sprintf("%s=%d", key, counts[key]))
+	}
+
+	return strings.Join(entries, ",")
+}
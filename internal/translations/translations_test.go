@@ -0,0 +1,191 @@
+package translations
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	kdexv1alpha1 "kdex.dev/crds/api/v1alpha1"
+)
+
+func Test_Chain(t *testing.T) {
+	tests := []struct {
+		name        string
+		lang        string
+		fallbacks   map[string][]string
+		defaultLang string
+		want        []string
+	}{
+		{
+			name:        "parent languages",
+			lang:        "de-AT-1996",
+			defaultLang: "en",
+			want:        []string{"de-AT-1996", "de-AT", "de", "en"},
+		},
+		{
+			name:        "configured fallbacks",
+			lang:        "de-AT",
+			fallbacks:   map[string][]string{"de-AT": {"de-CH", "de"}},
+			defaultLang: "en",
+			want:        []string{"de-AT", "de-CH", "de", "en"},
+		},
+		{
+			name:        "default language",
+			lang:        "en",
+			defaultLang: "en",
+			want:        []string{"en"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Chain(tt.lang, tt.fallbacks, tt.defaultLang))
+		})
+	}
+}
+
+func Test_Merge(t *testing.T) {
+	sources := []Source{
+		{
+			Name: "marketing",
+			Translations: []kdexv1alpha1.Translation{
+				{Lang: "de-AT", KeysAndValues: map[string]string{"greeting": "Servus"}},
+				{Lang: "en", KeysAndValues: map[string]string{"greeting": "Howdy"}},
+			},
+		},
+		{
+			Name: "kdex-default-translation",
+			Translations: []kdexv1alpha1.Translation{
+				{Lang: "de", KeysAndValues: map[string]string{"greeting": "Hallo", "farewell": "Tschüss"}},
+				{Lang: "en", KeysAndValues: map[string]string{"greeting": "Hello", "farewell": "Goodbye", "thanks": "Thanks"}},
+			},
+		},
+	}
+
+	merged := Merge(sources, []string{"fr"}, func(lang string) []string {
+		return Chain(lang, nil, "en")
+	})
+
+	assert.Equal(t, []kdexv1alpha1.Translation{
+		{Lang: "de", KeysAndValues: map[string]string{"greeting": "Hallo", "farewell": "Tschüss", "thanks": "Thanks"}},
+		{Lang: "de-AT", KeysAndValues: map[string]string{"greeting": "Servus", "farewell": "Tschüss", "thanks": "Thanks"}},
+		{Lang: "en", KeysAndValues: map[string]string{"greeting": "Howdy", "farewell": "Goodbye", "thanks": "Thanks"}},
+		{Lang: "fr", KeysAndValues: map[string]string{"greeting": "Howdy", "farewell": "Goodbye", "thanks": "Thanks"}},
+	}, merged.Translations)
+
+	assert.Equal(t, map[string]string{
+		"greeting": "marketing/de-AT",
+		"farewell": "kdex-default-translation/de",
+		"thanks":   "kdex-default-translation/en",
+	}, merged.Origins["de-AT"])
+
+	assert.Equal(t, "kdex-default-translation/de=1,kdex-default-translation/en=1,marketing/de-AT=1", Summary(merged.Origins["de-AT"]))
+}
+
+func Test_Split(t *testing.T) {
+	translations := []kdexv1alpha1.Translation{
+		{Lang: "de", KeysAndValues: map[string]string{"a": "1", "b": "2", "c": "3"}},
+		{Lang: "en", KeysAndValues: map[string]string{"a": "1"}},
+		{Lang: "fr", KeysAndValues: map[string]string{"a": "1", "b": "2"}},
+	}
+
+	assert.Equal(t, [][]kdexv1alpha1.Translation{
+		{
+			{Lang: "de", KeysAndValues: map[string]string{"a": "1", "b": "2"}},
+			{Lang: "en", KeysAndValues: map[string]string{"a": "1"}},
+		},
+		{
+			{Lang: "de", KeysAndValues: map[string]string{"c": "3"}},
+		},
+		{
+			{Lang: "fr", KeysAndValues: map[string]string{"a": "1", "b": "2"}},
+		},
+	}, Split(translations, 2, 2))
+}
+
+func Test_OriginIndex(t *testing.T) {
+	translations := []kdexv1alpha1.Translation{
+		{Lang: "de", KeysAndValues: map[string]string{"a": "1", "b": "2", "c": "3"}},
+		{Lang: "en", KeysAndValues: map[string]string{"a": "1", "b": "2"}},
+	}
+	origins := map[string]map[string]string{
+		"de": {"a": "marketing/de", "b": "kdex-default-translation/en", "c": "marketing/de"},
+		"en": {"a": "kdex-default-translation/en", "b": "kdex-default-translation/en"},
+	}
+
+	tests := []struct {
+		name    string
+		maxSize int
+		want    map[string]string
+	}{
+		{
+			name:    "every key",
+			maxSize: MaxOriginIndexSize,
+			want: map[string]string{
+				"de": `{"kdex-default-translation/en":["b"],"marketing/de":["a","c"]}`,
+				"en": `{"kdex-default-translation/en":["a","b"]}`,
+			},
+		},
+		{
+			name:    "summary of the languages past the limit",
+			maxSize: 104,
+			want: map[string]string{
+				"de":         `{"kdex-default-translation/en":["b"],"marketing/de":["a","c"]}`,
+				"en.summary": "kdex-default-translation/en=2",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			index, err := OriginIndex(translations, origins, tt.maxSize)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, index)
+		})
+	}
+}
+
+// Test_OriginIndex_size checks the index of a part holding as many languages and keys as a KDexTranslationSpec allows,
+// each key supplied by a different fallback language of the longest source name.
+func Test_OriginIndex_size(t *testing.T) {
+	tests := []struct {
+		name      string
+		keyLength int
+		summaries bool
+	}{
+		{name: "typical keys", keyLength: 64},
+		{name: "long keys", keyLength: 1024, summaries: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := strings.Repeat("s", 253)
+			translations := []kdexv1alpha1.Translation{}
+			origins := map[string]map[string]string{}
+			for l := range 32 {
+				lang := fmt.Sprintf("de-%02d", l)
+				translation := kdexv1alpha1.Translation{Lang: lang, KeysAndValues: map[string]string{}}
+				origins[lang] = map[string]string{}
+				for k := range 256 {
+					key := fmt.Sprintf("%0*d", tt.keyLength, k)
+					translation.KeysAndValues[key] = "value"
+					origins[lang][key] = fmt.Sprintf("%s/fallback-%d", source, k%8)
+				}
+				translations = append(translations, translation)
+			}
+
+			index, err := OriginIndex(translations, origins, MaxOriginIndexSize)
+			assert.NoError(t, err)
+
+			size, summaries := 0, 0
+			for key, value := range index {
+				size += len(key) + len(value)
+				if strings.HasSuffix(key, ".summary") {
+					summaries++
+				}
+			}
+			assert.LessOrEqual(t, size, MaxOriginIndexSize)
+			assert.Len(t, index, 32)
+			assert.Equal(t, tt.summaries, summaries > 0)
+		})
+	}
+}
@@ -3,13 +3,25 @@ package validation
 import (
 	"bytes"
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
+	"time"
 
+	"github.com/kdex-tech/nexus-manager/internal/extensions"
+	"github.com/kdex-tech/nexus-manager/internal/hostoptions"
+	"github.com/kdex-tech/nexus-manager/internal/metrics"
+	"github.com/kdex-tech/nexus-manager/internal/patch"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
 	kdexv1alpha1 "kdex.dev/crds/api/v1alpha1"
+	"kdex.dev/crds/configuration"
 	"kdex.dev/crds/npm"
 	"kdex.dev/crds/render"
 	kdexresource "kdex.dev/crds/resource"
+	"sigs.k8s.io/yaml"
 )
 
 func ValidatePackageReference(
@@ -22,10 +34,14 @@ func ValidatePackageReference(
 		return err
 	}
 
-	return registry.ValidatePackage(
+	start := time.Now()
+	err = registry.ValidatePackage(
 		packageReference.Name,
 		packageReference.Version,
 	)
+	metrics.ObservePackageValidation(packageReference.Registry, start, err)
+
+	return err
 }
 
 func ValidateAssets(assets kdexv1alpha1.Assets) error {
@@ -40,6 +56,481 @@ func ValidateAssets(assets kdexv1alpha1.Assets) error {
 	return err
 }
 
+func ValidateAutoscaling(replicas *int32, annotations map[string]string) error {
+	autoscaling, err := hostoptions.GetAutoscaling(annotations)
+	if err != nil || autoscaling == nil {
+		return err
+	}
+
+	if *autoscaling.MinReplicas < 1 {
+		return fmt.Errorf("autoscaling minReplicas must be at least 1, got %d", *autoscaling.MinReplicas)
+	}
+
+	if autoscaling.MaxReplicas < *autoscaling.MinReplicas {
+		return fmt.Errorf("autoscaling maxReplicas %d must not be less than minReplicas %d",
+			autoscaling.MaxReplicas, *autoscaling.MinReplicas)
+	}
+
+	if replicas != nil && (*replicas < *autoscaling.MinReplicas || *replicas > autoscaling.MaxReplicas) {
+		return fmt.Errorf("replicas %d must be between autoscaling minReplicas %d and maxReplicas %d",
+			*replicas, *autoscaling.MinReplicas, autoscaling.MaxReplicas)
+	}
+
+	return nil
+}
+
+func ValidateConfiguration(config *configuration.NexusConfiguration) error {
+	if config.DefaultImageRegistry.Host == "" {
+		return fmt.Errorf("defaultImageRegistry.host is required")
+	}
+
+	if config.DefaultNpmRegistry.Host == "" {
+		return fmt.Errorf("defaultNpmRegistry.host is required")
+	}
+
+	if config.BackendDefault.ServerImage == "" {
+		return fmt.Errorf("backendDefault.serverImage is required")
+	}
+
+	if len(config.HostDefault.Deployment.Template.Spec.Containers) == 0 {
+		return fmt.Errorf("hostDefault.deployment.template.spec.containers must contain at least one container")
+	}
+
+	for _, volume := range config.HostDefault.Deployment.Template.Spec.Volumes {
+		if volume.Name == "config" && volume.ConfigMap == nil {
+			return fmt.Errorf("hostDefault.deployment.template.spec.volumes[config] must be a configMap volume")
+		}
+	}
+
+	if config.HostDefault.RoleRef.Kind == "" || config.HostDefault.RoleRef.Name == "" {
+		return fmt.Errorf("hostDefault.roleRef.kind and hostDefault.roleRef.name are required")
+	}
+
+	return nil
+}
+
+func ValidateCertificate(certificate extensions.Certificate) error {
+	if certificate.IssuerRef != nil && certificate.CASecretRef != nil {
+		return fmt.Errorf("only one of issuerRef and caSecretRef may be set")
+	}
+
+	if certificate.IssuerRef != nil && certificate.IssuerRef.Name == "" {
+		return fmt.Errorf("issuerRef.name is required")
+	}
+
+	if certificate.CASecretRef != nil && (certificate.CASecretRef.Name == "" || certificate.CASecretRef.Namespace == "") {
+		return fmt.Errorf("caSecretRef.name and caSecretRef.namespace are required")
+	}
+
+	certificate = certificate.WithDefaults()
+
+	if certificate.RenewBefore.Duration <= 0 || certificate.RenewBefore.Duration >= certificate.Duration.Duration {
+		return fmt.Errorf("renewBefore %s must be positive and less than duration %s",
+			certificate.RenewBefore.Duration, certificate.Duration.Duration)
+	}
+
+	return nil
+}
+
+func ValidateDeletion(deletion extensions.Deletion) error {
+	deletion = deletion.WithDefaults()
+
+	if deletion.Timeout.Duration <= 0 {
+		return fmt.Errorf("timeout %s must be positive", deletion.Timeout.Duration)
+	}
+
+	return nil
+}
+
+func ValidateExtensions(config *extensions.Configuration) error {
+	if err := ValidateRBAC(config.HostDefault.RBAC); err != nil {
+		return fmt.Errorf("hostDefault.rbac: %w", err)
+	}
+
+	if err := ValidateCertificate(config.HostDefault.Certificate); err != nil {
+		return fmt.Errorf("hostDefault.certificate: %w", err)
+	}
+
+	if err := ValidateDeletion(config.HostDefault.Deletion); err != nil {
+		return fmt.Errorf("hostDefault.deletion: %w", err)
+	}
+
+	if err := ValidatePodDisruptionBudget(config.HostDefault.PodDisruptionBudget); err != nil {
+		return fmt.Errorf("hostDefault.podDisruptionBudget: %w", err)
+	}
+
+	if err := ValidateNetworkPolicy(config.HostDefault.NetworkPolicy); err != nil {
+		return fmt.Errorf("hostDefault.networkPolicy: %w", err)
+	}
+
+	if err := ValidateTranslation(config.HostDefault.Translation); err != nil {
+		return fmt.Errorf("hostDefault.translation: %w", err)
+	}
+
+	return nil
+}
+
+// ValidateTranslation checks that every fallback chain names its languages.
+func ValidateTranslation(translation extensions.Translation) error {
+	for lang, fallbacks := range translation.Fallbacks {
+		if strings.TrimSpace(lang) == "" {
+			return fmt.Errorf("fallbacks must not contain an empty language")
+		}
+		if slices.ContainsFunc(fallbacks, func(fallback string) bool { return strings.TrimSpace(fallback) == "" }) {
+			return fmt.Errorf("fallbacks.%s must not contain an empty language", lang)
+		}
+	}
+
+	return nil
+}
+
+// ValidateFaaSAdaptor checks that the builders and generators of an adaptor fit together: defaultBuilderGenerator
+// names a builder and a generator of a language it builds, and every generator has a builder for its language.
+func ValidateFaaSAdaptor(spec *kdexv1alpha1.KDexFaaSAdaptorSpec) error {
+	languages := map[string][]string{}
+	for _, builder := range spec.Builders {
+		languages[builder.Name] = builder.Languages
+	}
+
+	for _, generator := range spec.Generators {
+		if strings.TrimSpace(generator.Image) == "" || strings.TrimSpace(generator.Git.Image) == "" {
+			return fmt.Errorf("generators.%s must have an image and a git image", generator.Language)
+		}
+		if !slices.ContainsFunc(spec.Builders, func(builder kdexv1alpha1.Builder) bool {
+			return slices.Contains(builder.Languages, generator.Language)
+		}) {
+			return fmt.Errorf("generators.%s: no builder builds %s", generator.Language, generator.Language)
+		}
+	}
+
+	builder, language, _ := strings.Cut(spec.DefaultBuilderGenerator, "/")
+	builderLanguages, ok := languages[builder]
+	if !ok {
+		return fmt.Errorf("defaultBuilderGenerator: no builder is named %q", builder)
+	}
+	if !slices.ContainsFunc(spec.Generators, func(generator kdexv1alpha1.Generator) bool {
+		return generator.Language == language
+	}) {
+		return fmt.Errorf("defaultBuilderGenerator: no generator is defined for %q", language)
+	}
+	if !slices.Contains(builderLanguages, language) {
+		return fmt.Errorf("defaultBuilderGenerator: builder %q does not build %q", builder, language)
+	}
+
+	if spec.Observer != nil && strings.TrimSpace(spec.Observer.Image) == "" {
+		return fmt.Errorf("observer.image is required")
+	}
+
+	return nil
+}
+
+// ValidateHostRBACProfile checks that the RBAC profile chosen by a host exists and grants what the host uses, and that
+// its RBAC scope is known.
+func ValidateHostRBACProfile(spec *kdexv1alpha1.KDexHostSpec, annotations map[string]string, rbac extensions.RBAC) error {
+	name, profile, err := hostoptions.GetRBACProfile(annotations, rbac)
+	if err != nil {
+		return err
+	}
+
+	if spec.FaaSAdaptorRef != nil && !profile.Functions {
+		return fmt.Errorf("%s: profile %q does not allow KDexFunctions, which spec.faasAdaptorRef requires",
+			hostoptions.RBACProfileAnnotation, name)
+	}
+
+	_, err = hostoptions.IsRBACNamespaced(annotations, rbac)
+
+	return err
+}
+
+func ValidateRBAC(rbac extensions.RBAC) error {
+	for name := range rbac.Profiles {
+		if strings.TrimSpace(name) == "" {
+			return fmt.Errorf("profiles must not contain an empty name")
+		}
+	}
+
+	rbac = rbac.WithDefaults()
+	if _, ok := rbac.Profiles[rbac.DefaultProfile]; !ok {
+		return fmt.Errorf("defaultProfile %q is not a known profile", rbac.DefaultProfile)
+	}
+
+	return nil
+}
+
+func ValidateHostPodDisruptionBudget(annotations map[string]string) error {
+	override, err := hostoptions.GetPodDisruptionBudgetOverride(annotations)
+	if err != nil {
+		return err
+	}
+
+	if err := ValidatePodDisruptionBudget(override); err != nil {
+		return fmt.Errorf("%s: %w", hostoptions.PodDisruptionBudgetAnnotation, err)
+	}
+
+	return nil
+}
+
+// ValidateMaintenance checks the maintenance chosen by a host, which is only allowed when enabled by
+// hostDefault.maintenanceMode.
+func ValidateMaintenance(annotations map[string]string, enabled bool) error {
+	maintenance, err := hostoptions.GetMaintenance(annotations)
+	if err != nil || maintenance == nil {
+		return err
+	}
+
+	if !enabled {
+		return fmt.Errorf("%s: maintenance mode is not enabled by hostDefault.maintenanceMode", hostoptions.MaintenanceAnnotation)
+	}
+
+	if !maintenance.Enabled && maintenance.Window == nil {
+		return fmt.Errorf("%s: one of enabled or window must be set", hostoptions.MaintenanceAnnotation)
+	}
+
+	if window := maintenance.Window; window != nil && !window.End.After(window.Start.Time) {
+		return fmt.Errorf("%s: window end %s must be after its start %s",
+			hostoptions.MaintenanceAnnotation, window.End.UTC().Format(time.RFC3339), window.Start.UTC().Format(time.RFC3339))
+	}
+
+	if maintenance.StatusCode < 200 || maintenance.StatusCode > 599 {
+		return fmt.Errorf("%s: statusCode must be between 200 and 599, got %d", hostoptions.MaintenanceAnnotation, maintenance.StatusCode)
+	}
+
+	if maintenance.RetryAfterDelay != nil && maintenance.RetryAfterDelay.Duration < 0 {
+		return fmt.Errorf("%s: retryAfter must not be negative, got %s", hostoptions.MaintenanceAnnotation, maintenance.RetryAfterDelay.Duration)
+	}
+
+	if ref := maintenance.PageRef; ref != nil && ref.Kind != "KDexUtilityPage" && ref.Kind != "KDexClusterUtilityPage" {
+		return fmt.Errorf("%s: pageRef must reference a KDexUtilityPage or KDexClusterUtilityPage, got %q",
+			hostoptions.MaintenanceAnnotation, ref.Kind)
+	}
+
+	return nil
+}
+
+// ValidateUtilityPages checks the utility pages and error pages chosen by a host. Every error status must be served a
+// utility page the host has: its error page or a page of the utility pages annotation.
+func ValidateUtilityPages(annotations map[string]string) error {
+	pages, err := hostoptions.GetUtilityPages(annotations)
+	if err != nil {
+		return err
+	}
+
+	for _, pageType := range slices.Sorted(maps.Keys(pages)) {
+		if !hostoptions.ValidUtilityPageType(pageType) {
+			return fmt.Errorf("%s: invalid utility page type %q, must be an upper camel case word", hostoptions.UtilityPagesAnnotation, pageType)
+		}
+		if slices.Contains(hostoptions.SpecUtilityPageTypes, pageType) {
+			return fmt.Errorf("%s: the %s page is set in spec.utilityPages", hostoptions.UtilityPagesAnnotation, pageType)
+		}
+
+		ref := pages[pageType]
+		if ref == nil {
+			if !slices.Contains(hostoptions.DefaultedUtilityPageTypes, pageType) {
+				return fmt.Errorf("%s: %s has no default page to leave out", hostoptions.UtilityPagesAnnotation, pageType)
+			}
+			continue
+		}
+		if ref.Kind != "KDexUtilityPage" && ref.Kind != "KDexClusterUtilityPage" {
+			return fmt.Errorf("%s: %s must reference a KDexUtilityPage or KDexClusterUtilityPage, got %q",
+				hostoptions.UtilityPagesAnnotation, pageType, ref.Kind)
+		}
+		hasDefault := slices.Contains(hostoptions.DefaultedUtilityPageTypes, pageType) || pageType == hostoptions.MaintenanceUtilityPageType
+		if ref.Name == "" && !hasDefault {
+			return fmt.Errorf("%s: %s must name a page", hostoptions.UtilityPagesAnnotation, pageType)
+		}
+	}
+
+	errorPages, err := hostoptions.GetErrorPages(annotations)
+	if err != nil {
+		return err
+	}
+
+	for _, status := range slices.Sorted(maps.Keys(errorPages)) {
+		pageType := errorPages[status]
+		ref, referenced := pages[pageType]
+
+		switch {
+		case pageType == kdexv1alpha1.ErrorUtilityPageType:
+		case pageType == hostoptions.MaintenanceUtilityPageType:
+			return fmt.Errorf("%s: %d cannot be served the maintenance page", hostoptions.ErrorPagesAnnotation, status)
+		case referenced && ref != nil:
+		case !referenced && slices.Contains(hostoptions.DefaultedUtilityPageTypes, pageType):
+		default:
+			return fmt.Errorf("%s: %d is served the %s page, which the host does not have", hostoptions.ErrorPagesAnnotation, status, pageType)
+		}
+	}
+
+	return nil
+}
+
+// ValidateUtilityPageType checks the utility page type declared by a KDexUtilityPage or KDexClusterUtilityPage.
+func ValidateUtilityPageType(annotations map[string]string, specType kdexv1alpha1.KDexUtilityPageType) error {
+	pageType := hostoptions.GetUtilityPageType(annotations, specType)
+	if pageType == specType {
+		return nil
+	}
+
+	if !hostoptions.ValidUtilityPageType(pageType) {
+		return fmt.Errorf("%s: invalid utility page type %q, must be an upper camel case word", hostoptions.UtilityPageTypeAnnotation, pageType)
+	}
+	if slices.Contains(hostoptions.SpecUtilityPageTypes, pageType) {
+		return fmt.Errorf("%s: the %s type is set in spec.type", hostoptions.UtilityPageTypeAnnotation, pageType)
+	}
+
+	return nil
+}
+
+// ValidatePool checks the pool chosen by a host. The hosts of a pool share one Deployment, so a pooled host may not
+// carry settings which only make sense for a Deployment of its own.
+func ValidatePool(spec *kdexv1alpha1.KDexHostSpec, annotations map[string]string) error {
+	pool := hostoptions.GetPool(annotations)
+	if pool == "" {
+		return nil
+	}
+
+	if errs := k8svalidation.IsDNS1123Label(hostoptions.PoolResourceName(pool)); len(errs) > 0 {
+		return fmt.Errorf("%s: invalid pool name %q: %s", hostoptions.PoolAnnotation, pool, strings.Join(errs, "; "))
+	}
+
+	for _, annotation := range []string{
+		hostoptions.AutoscalingAnnotation,
+		hostoptions.PodDisruptionBudgetAnnotation,
+		patch.DeploymentPatchAnnotation,
+	} {
+		if strings.TrimSpace(annotations[annotation]) != "" {
+			return fmt.Errorf("%s: pooled hosts share the pool Deployment and may not set %s", hostoptions.PoolAnnotation, annotation)
+		}
+	}
+
+	switch {
+	case len(spec.Env) > 0:
+		return fmt.Errorf("%s: pooled hosts share the pool Deployment and may not set spec.env", hostoptions.PoolAnnotation)
+	case spec.Replicas != nil:
+		return fmt.Errorf("%s: pooled hosts share the pool Deployment and may not set spec.replicas", hostoptions.PoolAnnotation)
+	case spec.Resources.Size() > 0:
+		return fmt.Errorf("%s: pooled hosts share the pool Deployment and may not set spec.resources", hostoptions.PoolAnnotation)
+	}
+
+	return nil
+}
+
+// ValidatePoolRBAC checks that the pool members, other than the host, chose the same RBAC profile and scope as the
+// host: the hosts of a pool share one service account and so one set of bindings. Members whose choice is invalid are
+// left to fail on their own.
+func ValidatePoolRBAC(host *kdexv1alpha1.KDexHost, members []kdexv1alpha1.KDexHost, rbac extensions.RBAC) error {
+	pool := hostoptions.GetPool(host.Annotations)
+	if pool == "" {
+		return nil
+	}
+
+	profile, scope, err := rbacChoice(host.Annotations, rbac)
+	if err != nil {
+		return err
+	}
+
+	for _, member := range members {
+		if member.Name == host.Name || member.Namespace != host.Namespace || hostoptions.GetPool(member.Annotations) != pool {
+			continue
+		}
+
+		memberProfile, memberScope, err := rbacChoice(member.Annotations, rbac)
+		if err != nil {
+			continue
+		}
+
+		if memberProfile != profile || memberScope != scope {
+			return fmt.Errorf("%s: the hosts of pool %q share their RBAC bindings, but host %s has profile %q and scope %q "+
+				"where host %s has profile %q and scope %q",
+				hostoptions.PoolAnnotation, pool, member.Name, memberProfile, memberScope, host.Name, profile, scope)
+		}
+	}
+
+	return nil
+}
+
+// rbacChoice returns the RBAC profile and scope chosen in annotations.
+func rbacChoice(annotations map[string]string, rbac extensions.RBAC) (string, string, error) {
+	profile, _, err := hostoptions.GetRBACProfile(annotations, rbac)
+	if err != nil {
+		return "", "", err
+	}
+
+	namespaced, err := hostoptions.IsRBACNamespaced(annotations, rbac)
+	if err != nil {
+		return "", "", err
+	}
+
+	if namespaced {
+		return profile, hostoptions.RBACScopeNamespace, nil
+	}
+
+	return profile, hostoptions.RBACScopeCluster, nil
+}
+
+func ValidatePodDisruptionBudget(budget extensions.PodDisruptionBudget) error {
+	if budget.MaxUnavailable != nil && budget.MinAvailable != nil {
+		return fmt.Errorf("only one of maxUnavailable and minAvailable may be set")
+	}
+
+	if budget.UnhealthyPodEvictionPolicy != nil {
+		switch *budget.UnhealthyPodEvictionPolicy {
+		case policyv1.IfHealthyBudget, policyv1.AlwaysAllow:
+		default:
+			return fmt.Errorf("unhealthyPodEvictionPolicy must be one of %s or %s, got %q",
+				policyv1.IfHealthyBudget, policyv1.AlwaysAllow, *budget.UnhealthyPodEvictionPolicy)
+		}
+	}
+
+	return nil
+}
+
+// ValidateNetworkPolicy checks that every egress rule lists its peers; a rule without any would allow its ports to
+// every destination.
+func ValidateNetworkPolicy(policy extensions.NetworkPolicy) error {
+	for _, field := range []struct {
+		name  string
+		rules []networkingv1.NetworkPolicyEgressRule
+	}{
+		{"apiServer", policy.APIServer},
+		{"dns", policy.DNS},
+		{"faas", policy.FaaS},
+		{"registries", policy.Registries},
+	} {
+		for i, rule := range field.rules {
+			if len(rule.To) == 0 {
+				return fmt.Errorf("%s[%d].to must list the peers of the rule", field.name, i)
+			}
+		}
+	}
+
+	return nil
+}
+
+func ValidateDeploymentPatch(hostName string, annotations map[string]string) error {
+	value := annotations[patch.DeploymentPatchAnnotation]
+	if strings.TrimSpace(value) == "" {
+		return nil
+	}
+
+	if annotations[patch.DeploymentPatchTypeAnnotation] == patch.JSONPatchType {
+		patchJSON, err := yaml.YAMLToJSON([]byte(value))
+		if err != nil {
+			return fmt.Errorf("%s is not valid YAML or JSON: %w", patch.DeploymentPatchAnnotation, err)
+		}
+
+		return patch.ValidateJSONPatch(patchJSON)
+	}
+
+	probe := patch.ProbeDeployment(hostName)
+	before := probe.DeepCopy()
+
+	if err := patch.ApplyToDeployment(probe, annotations); err != nil {
+		return err
+	}
+
+	return patch.VerifyOwnedFields(before, probe)
+}
+
 func ValidateResourceProvider(resourceProvider kdexresource.ResourceProvider) error {
 	if resourceProvider.GetResourceImage() == "" {
 		for _, url := range resourceProvider.GetResourceURLs() {
@@ -3,8 +3,16 @@ package validation
 import (
 	"testing"
 
+	"github.com/kdex-tech/nexus-manager/internal/extensions"
+	"github.com/kdex-tech/nexus-manager/internal/hostoptions"
+	"github.com/kdex-tech/nexus-manager/internal/patch"
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	kdexv1alpha1 "kdex.dev/crds/api/v1alpha1"
+	"kdex.dev/crds/configuration"
+	"sigs.k8s.io/yaml"
 )
 
 func Test_ValidateScriptLibrary(t *testing.T) {
@@ -64,3 +72,896 @@ func Test_ValidateScriptLibrary(t *testing.T) {
 		})
 	}
 }
+
+func Test_ValidateConfiguration(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, configuration.AddToScheme(scheme))
+
+	tests := []struct {
+		name    string
+		modify  func(*configuration.NexusConfiguration)
+		wantErr bool
+	}{
+		{
+			name:    "defaults",
+			modify:  func(config *configuration.NexusConfiguration) {},
+			wantErr: false,
+		},
+		{
+			name: "missing image registry",
+			modify: func(config *configuration.NexusConfiguration) {
+				config.DefaultImageRegistry.Host = ""
+			},
+			wantErr: true,
+		},
+		{
+			name: "missing npm registry",
+			modify: func(config *configuration.NexusConfiguration) {
+				config.DefaultNpmRegistry.Host = ""
+			},
+			wantErr: true,
+		},
+		{
+			name: "missing host containers",
+			modify: func(config *configuration.NexusConfiguration) {
+				config.HostDefault.Deployment.Template.Spec.Containers = nil
+			},
+			wantErr: true,
+		},
+		{
+			name: "config volume is not a configMap",
+			modify: func(config *configuration.NexusConfiguration) {
+				config.HostDefault.Deployment.Template.Spec.Volumes = []corev1.Volume{
+					{
+						Name: "config",
+						VolumeSource: corev1.VolumeSource{
+							EmptyDir: &corev1.EmptyDirVolumeSource{},
+						},
+					},
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "missing role ref",
+			modify: func(config *configuration.NexusConfiguration) {
+				config.HostDefault.RoleRef.Name = ""
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := configuration.LoadConfiguration("/does-not-exist.yaml", scheme)
+			tt.modify(&config)
+			err := ValidateConfiguration(&config)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func Test_ValidateAutoscaling(t *testing.T) {
+	replicas := func(value int32) *int32 {
+		return &value
+	}
+
+	tests := []struct {
+		name        string
+		replicas    *int32
+		autoscaling string
+		wantErr     bool
+	}{
+		{
+			name: "autoscaling disabled",
+		},
+		{
+			name:        "max only",
+			autoscaling: "maxReplicas: 5",
+		},
+		{
+			name:        "replicas within bounds",
+			replicas:    replicas(3),
+			autoscaling: "{minReplicas: 2, maxReplicas: 5}",
+		},
+		{
+			name:        "replicas below min",
+			replicas:    replicas(1),
+			autoscaling: "{minReplicas: 2, maxReplicas: 5}",
+			wantErr:     true,
+		},
+		{
+			name:        "replicas above max",
+			replicas:    replicas(6),
+			autoscaling: "{minReplicas: 2, maxReplicas: 5}",
+			wantErr:     true,
+		},
+		{
+			name:        "max below min",
+			autoscaling: "{minReplicas: 4, maxReplicas: 2}",
+			wantErr:     true,
+		},
+		{
+			name:        "min below one",
+			autoscaling: "{minReplicas: 0, maxReplicas: 2}",
+			wantErr:     true,
+		},
+		{
+			name:        "unknown field",
+			autoscaling: "{maxReplica: 2}",
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			annotations := map[string]string{}
+			if tt.autoscaling != "" {
+				annotations[hostoptions.AutoscalingAnnotation] = tt.autoscaling
+			}
+			err := ValidateAutoscaling(tt.replicas, annotations)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func Test_ValidateNetworkPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  string
+		wantErr bool
+	}{
+		{
+			name: "defaults",
+		},
+		{
+			name: "registry with peers",
+			policy: `
+registries:
+- to:
+  - ipBlock:
+      cidr: 203.0.113.0/24
+  ports:
+  - port: 443
+    protocol: TCP
+`,
+		},
+		{
+			name: "registry without peers",
+			policy: `
+registries:
+- ports:
+  - port: 443
+    protocol: TCP
+`,
+			wantErr: true,
+		},
+		{
+			name: "api server without peers",
+			policy: `
+apiServer:
+- ports:
+  - port: 6443
+    protocol: TCP
+`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := extensions.NetworkPolicy{}
+			assert.NoError(t, yaml.Unmarshal([]byte(tt.policy), &policy))
+			err := ValidateNetworkPolicy(policy.WithDefaults())
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func Test_ValidateHostPodDisruptionBudget(t *testing.T) {
+	tests := []struct {
+		name    string
+		budget  string
+		wantErr bool
+	}{
+		{
+			name: "no override",
+		},
+		{
+			name:   "min available",
+			budget: "minAvailable: 50%",
+		},
+		{
+			name:   "disabled",
+			budget: "disabled: true",
+		},
+		{
+			name:    "both min and max",
+			budget:  "{minAvailable: 1, maxUnavailable: 1}",
+			wantErr: true,
+		},
+		{
+			name:    "unknown eviction policy",
+			budget:  "unhealthyPodEvictionPolicy: Sometimes",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			annotations := map[string]string{}
+			if tt.budget != "" {
+				annotations[hostoptions.PodDisruptionBudgetAnnotation] = tt.budget
+			}
+			err := ValidateHostPodDisruptionBudget(annotations)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func Test_ValidateMaintenance(t *testing.T) {
+	tests := []struct {
+		name        string
+		maintenance string
+		disabled    bool
+		wantErr     bool
+	}{
+		{
+			name: "no maintenance",
+		},
+		{
+			name:     "no maintenance while disabled",
+			disabled: true,
+		},
+		{
+			name:        "maintenance mode disabled",
+			maintenance: "enabled: true",
+			disabled:    true,
+			wantErr:     true,
+		},
+		{
+			name:        "enabled",
+			maintenance: "enabled: true",
+		},
+		{
+			name:        "window",
+			maintenance: "{window: {start: '2026-10-20T22:00:00Z', end: '2026-10-21T02:00:00Z'}, statusCode: 503, retryAfter: 10m}",
+		},
+		{
+			name:        "neither enabled nor window",
+			maintenance: "statusCode: 503",
+			wantErr:     true,
+		},
+		{
+			name:        "window ends before it starts",
+			maintenance: "{window: {start: '2026-10-21T02:00:00Z', end: '2026-10-20T22:00:00Z'}}",
+			wantErr:     true,
+		},
+		{
+			name:        "status code out of range",
+			maintenance: "{enabled: true, statusCode: 99}",
+			wantErr:     true,
+		},
+		{
+			name:        "page of another kind",
+			maintenance: "{enabled: true, pageRef: {kind: KDexPageBinding, name: home}}",
+			wantErr:     true,
+		},
+		{
+			name:        "unknown field",
+			maintenance: "{enabled: true, until: tomorrow}",
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			annotations := map[string]string{}
+			if tt.maintenance != "" {
+				annotations[hostoptions.MaintenanceAnnotation] = tt.maintenance
+			}
+			err := ValidateMaintenance(annotations, !tt.disabled)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func Test_ValidateCertificate(t *testing.T) {
+	tests := []struct {
+		name        string
+		certificate string
+		wantErr     bool
+	}{
+		{
+			name: "self-signed defaults",
+		},
+		{
+			name:        "issuer",
+			certificate: "issuerRef: {kind: ClusterIssuer, name: letsencrypt}",
+		},
+		{
+			name:        "internal CA",
+			certificate: "caSecretRef: {name: kdex-ca, namespace: kdex-system}",
+		},
+		{
+			name:        "issuer and internal CA",
+			certificate: "{issuerRef: {name: letsencrypt}, caSecretRef: {name: kdex-ca, namespace: kdex-system}}",
+			wantErr:     true,
+		},
+		{
+			name:        "issuer without name",
+			certificate: "issuerRef: {kind: ClusterIssuer}",
+			wantErr:     true,
+		},
+		{
+			name:        "CA without namespace",
+			certificate: "caSecretRef: {name: kdex-ca}",
+			wantErr:     true,
+		},
+		{
+			name:        "renewal window longer than duration",
+			certificate: "{duration: 24h, renewBefore: 48h}",
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			certificate := extensions.Certificate{}
+			assert.NoError(t, yaml.Unmarshal([]byte(tt.certificate), &certificate))
+			err := ValidateCertificate(certificate)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func Test_ValidateDeploymentPatch(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		wantErr     bool
+	}{
+		{
+			name:        "no patch",
+			annotations: map[string]string{},
+		},
+		{
+			name: "strategic merge adds a sidecar",
+			annotations: map[string]string{
+				patch.DeploymentPatchAnnotation: `{"spec":{"template":{"spec":{"containers":[{"name":"sidecar","image":"busybox"}]}}}}`,
+			},
+		},
+		{
+			name: "strategic merge renames the deployment",
+			annotations: map[string]string{
+				patch.DeploymentPatchAnnotation: `{"metadata":{"name":"other"}}`,
+			},
+			wantErr: true,
+		},
+		{
+			name: "strategic merge changes the name label of the pods",
+			annotations: map[string]string{
+				patch.DeploymentPatchAnnotation: `{"spec":{"template":{"metadata":{"labels":{"app.kubernetes.io/name":"other"}}}}}`,
+			},
+			wantErr: true,
+		},
+		{
+			name: "json patch changes the namespace",
+			annotations: map[string]string{
+				patch.DeploymentPatchTypeAnnotation: patch.JSONPatchType,
+				patch.DeploymentPatchAnnotation:     `[{"op":"add","path":"/metadata/namespace","value":"other"}]`,
+			},
+			wantErr: true,
+		},
+		{
+			name: "json patch changes the selector",
+			annotations: map[string]string{
+				patch.DeploymentPatchTypeAnnotation: patch.JSONPatchType,
+				patch.DeploymentPatchAnnotation:     `[{"op":"replace","path":"/spec/selector","value":{"matchLabels":{"a":"b"}}}]`,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateDeploymentPatch("host", tt.annotations)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func Test_ValidateDeletion(t *testing.T) {
+	tests := []struct {
+		name     string
+		deletion string
+		wantErr  bool
+	}{
+		{
+			name: "defaults",
+		},
+		{
+			name:     "timeout",
+			deletion: "timeout: 30m",
+		},
+		{
+			name:     "negative timeout",
+			deletion: "timeout: -1m",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deletion := extensions.Deletion{}
+			assert.NoError(t, yaml.Unmarshal([]byte(tt.deletion), &deletion))
+			err := ValidateDeletion(deletion)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func Test_ValidateHostRBACProfile(t *testing.T) {
+	rbac := extensions.RBAC{
+		DefaultProfile: extensions.RBACProfilePages,
+		Profiles: map[string]extensions.RBACProfile{
+			"reporting": {ClusterRole: "reporting-role"},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		profile     string
+		scope       string
+		faasAdaptor bool
+		wantErr     bool
+	}{
+		{
+			name: "default profile",
+		},
+		{
+			name:    "configured profile",
+			profile: "reporting",
+		},
+		{
+			name:    "unknown profile",
+			profile: "admin",
+			wantErr: true,
+		},
+		{
+			name:        "functions profile with a FaaS adaptor",
+			profile:     extensions.RBACProfileFunctions,
+			faasAdaptor: true,
+		},
+		{
+			name:        "default pages profile with a FaaS adaptor",
+			faasAdaptor: true,
+			wantErr:     true,
+		},
+		{
+			name:  "namespace scope",
+			scope: hostoptions.RBACScopeNamespace,
+		},
+		{
+			name:    "unknown scope",
+			scope:   "global",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			annotations := map[string]string{}
+			if tt.profile != "" {
+				annotations[hostoptions.RBACProfileAnnotation] = tt.profile
+			}
+			if tt.scope != "" {
+				annotations[hostoptions.RBACScopeAnnotation] = tt.scope
+			}
+			spec := &kdexv1alpha1.KDexHostSpec{}
+			if tt.faasAdaptor {
+				spec.FaaSAdaptorRef = &kdexv1alpha1.KDexObjectReference{Kind: "KDexFaaSAdaptor", Name: "knative"}
+			}
+			err := ValidateHostRBACProfile(spec, annotations, rbac)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func Test_ValidatePool(t *testing.T) {
+	replicas := int32(2)
+
+	tests := []struct {
+		name        string
+		pool        string
+		annotations map[string]string
+		replicas    *int32
+		env         []corev1.EnvVar
+		wantErr     bool
+	}{
+		{
+			name:     "not pooled",
+			replicas: &replicas,
+		},
+		{
+			name: "pooled",
+			pool: "marketing",
+		},
+		{
+			name:    "invalid name",
+			pool:    "Marketing_Sites",
+			wantErr: true,
+		},
+		{
+			name:        "autoscaling",
+			pool:        "marketing",
+			annotations: map[string]string{hostoptions.AutoscalingAnnotation: "maxReplicas: 5"},
+			wantErr:     true,
+		},
+		{
+			name:        "deployment patch",
+			pool:        "marketing",
+			annotations: map[string]string{patch.DeploymentPatchAnnotation: "spec: {}"},
+			wantErr:     true,
+		},
+		{
+			name:     "replicas",
+			pool:     "marketing",
+			replicas: &replicas,
+			wantErr:  true,
+		},
+		{
+			name:    "env",
+			pool:    "marketing",
+			env:     []corev1.EnvVar{{Name: "A", Value: "b"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			annotations := map[string]string{}
+			for key, value := range tt.annotations {
+				annotations[key] = value
+			}
+			if tt.pool != "" {
+				annotations[hostoptions.PoolAnnotation] = tt.pool
+			}
+			spec := &kdexv1alpha1.KDexHostSpec{}
+			spec.Replicas = tt.replicas
+			spec.Env = tt.env
+			err := ValidatePool(spec, annotations)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func Test_ValidatePoolRBAC(t *testing.T) {
+	member := func(name string, annotations map[string]string) kdexv1alpha1.KDexHost {
+		return kdexv1alpha1.KDexHost{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Annotations: annotations}}
+	}
+
+	tests := []struct {
+		name    string
+		host    map[string]string
+		other   map[string]string
+		wantErr bool
+	}{
+		{
+			name:  "not pooled",
+			host:  map[string]string{hostoptions.RBACProfileAnnotation: "pages"},
+			other: map[string]string{hostoptions.PoolAnnotation: "marketing"},
+		},
+		{
+			name:  "same profile",
+			host:  map[string]string{hostoptions.PoolAnnotation: "marketing", hostoptions.RBACProfileAnnotation: "pages"},
+			other: map[string]string{hostoptions.PoolAnnotation: "marketing", hostoptions.RBACProfileAnnotation: "pages"},
+		},
+		{
+			name:    "different profile",
+			host:    map[string]string{hostoptions.PoolAnnotation: "marketing", hostoptions.RBACProfileAnnotation: "pages"},
+			other:   map[string]string{hostoptions.PoolAnnotation: "marketing"},
+			wantErr: true,
+		},
+		{
+			name:    "different scope",
+			host:    map[string]string{hostoptions.PoolAnnotation: "marketing", hostoptions.RBACScopeAnnotation: "namespace"},
+			other:   map[string]string{hostoptions.PoolAnnotation: "marketing"},
+			wantErr: true,
+		},
+		{
+			name:  "different profile in another pool",
+			host:  map[string]string{hostoptions.PoolAnnotation: "marketing", hostoptions.RBACProfileAnnotation: "pages"},
+			other: map[string]string{hostoptions.PoolAnnotation: "sales"},
+		},
+		{
+			name:  "invalid member",
+			host:  map[string]string{hostoptions.PoolAnnotation: "marketing"},
+			other: map[string]string{hostoptions.PoolAnnotation: "marketing", hostoptions.RBACProfileAnnotation: "admin"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			host := member("host", tt.host)
+			members := []kdexv1alpha1.KDexHost{host, member("other", tt.other)}
+			err := ValidatePoolRBAC(&host, members, extensions.RBAC{})
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func Test_ValidateFaaSAdaptor(t *testing.T) {
+	valid := func() *kdexv1alpha1.KDexFaaSAdaptorSpec {
+		return &kdexv1alpha1.KDexFaaSAdaptorSpec{
+			Builders: []kdexv1alpha1.Builder{
+				{Languages: []string{"go", "rust"}, Name: "tiny"},
+				{Languages: []string{"nodejs"}, Name: "base"},
+			},
+			DefaultBuilderGenerator: "tiny/go",
+			Generators: []kdexv1alpha1.Generator{
+				{Git: kdexv1alpha1.Git{Image: "git"}, Image: "fngogen", Language: "go"},
+				{Git: kdexv1alpha1.Git{Image: "git"}, Image: "fnnodegen", Language: "nodejs"},
+			},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		mutate  func(spec *kdexv1alpha1.KDexFaaSAdaptorSpec)
+		wantErr bool
+	}{
+		{
+			name:   "valid",
+			mutate: func(spec *kdexv1alpha1.KDexFaaSAdaptorSpec) {},
+		},
+		{
+			name: "valid observer",
+			mutate: func(spec *kdexv1alpha1.KDexFaaSAdaptorSpec) {
+				spec.Observer = &kdexv1alpha1.Observer{Image: "deployer"}
+			},
+		},
+		{
+			name: "unknown default builder",
+			mutate: func(spec *kdexv1alpha1.KDexFaaSAdaptorSpec) {
+				spec.DefaultBuilderGenerator = "full/go"
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown default generator",
+			mutate: func(spec *kdexv1alpha1.KDexFaaSAdaptorSpec) {
+				spec.DefaultBuilderGenerator = "tiny/rust"
+			},
+			wantErr: true,
+		},
+		{
+			name: "default builder does not build the generator language",
+			mutate: func(spec *kdexv1alpha1.KDexFaaSAdaptorSpec) {
+				spec.DefaultBuilderGenerator = "base/go"
+			},
+			wantErr: true,
+		},
+		{
+			name: "generator without builder",
+			mutate: func(spec *kdexv1alpha1.KDexFaaSAdaptorSpec) {
+				spec.Generators = append(spec.Generators, kdexv1alpha1.Generator{
+					Git: kdexv1alpha1.Git{Image: "git"}, Image: "fnpygen", Language: "python",
+				})
+			},
+			wantErr: true,
+		},
+		{
+			name: "generator without git image",
+			mutate: func(spec *kdexv1alpha1.KDexFaaSAdaptorSpec) {
+				spec.Generators[0].Git.Image = " "
+			},
+			wantErr: true,
+		},
+		{
+			name: "observer without image",
+			mutate: func(spec *kdexv1alpha1.KDexFaaSAdaptorSpec) {
+				spec.Observer = &kdexv1alpha1.Observer{}
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := valid()
+			tt.mutate(spec)
+			err := ValidateFaaSAdaptor(spec)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func Test_ValidateTranslation(t *testing.T) {
+	tests := []struct {
+		name      string
+		fallbacks map[string][]string
+		wantErr   bool
+	}{
+		{
+			name:      "valid",
+			fallbacks: map[string][]string{"de-AT": {"de", "en"}},
+		},
+		{
+			name:      "empty language",
+			fallbacks: map[string][]string{"": {"en"}},
+			wantErr:   true,
+		},
+		{
+			name:      "empty fallback",
+			fallbacks: map[string][]string{"de-AT": {"de", " "}},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateTranslation(extensions.Translation{Fallbacks: tt.fallbacks})
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func Test_ValidateUtilityPages(t *testing.T) {
+	tests := []struct {
+		name         string
+		utilityPages string
+		errorPages   string
+		wantErr      bool
+	}{
+		{
+			name: "defaults",
+		},
+		{
+			name:         "custom type",
+			utilityPages: "{PaymentRequired: {kind: KDexUtilityPage, name: payment}}",
+			errorPages:   "{'402': PaymentRequired, '500': Error}",
+		},
+		{
+			name:         "left out default",
+			utilityPages: "{NotFound: null}",
+			errorPages:   "{'404': ''}",
+		},
+		{
+			name:         "error status served a left out page",
+			utilityPages: "{NotFound: null}",
+			wantErr:      true,
+		},
+		{
+			name:         "invalid type",
+			utilityPages: "{not-found: {name: missing}}",
+			wantErr:      true,
+		},
+		{
+			name:         "spec type",
+			utilityPages: "{Login: {name: login}}",
+			wantErr:      true,
+		},
+		{
+			name:         "custom type without a page",
+			utilityPages: "{PaymentRequired: {kind: KDexUtilityPage}}",
+			wantErr:      true,
+		},
+		{
+			name:         "page of another kind",
+			utilityPages: "{Offline: {kind: KDexPageBinding, name: offline}}",
+			wantErr:      true,
+		},
+		{
+			name:       "unknown type",
+			errorPages: "{'402': PaymentRequired}",
+			wantErr:    true,
+		},
+		{
+			name:       "not an error status",
+			errorPages: "{'302': NotFound}",
+			wantErr:    true,
+		},
+		{
+			name:       "maintenance page",
+			errorPages: "{'503': Maintenance}",
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			annotations := map[string]string{}
+			if tt.utilityPages != "" {
+				annotations[hostoptions.UtilityPagesAnnotation] = tt.utilityPages
+			}
+			if tt.errorPages != "" {
+				annotations[hostoptions.ErrorPagesAnnotation] = tt.errorPages
+			}
+			err := ValidateUtilityPages(annotations)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func Test_ValidateUtilityPageType(t *testing.T) {
+	tests := []struct {
+		name     string
+		pageType string
+		wantErr  bool
+	}{
+		{
+			name: "spec type",
+		},
+		{
+			name:     "declared type",
+			pageType: "NotFound",
+		},
+		{
+			name:     "invalid type",
+			pageType: "not found",
+			wantErr:  true,
+		},
+		{
+			name:     "other spec type",
+			pageType: "Login",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			annotations := map[string]string{}
+			if tt.pageType != "" {
+				annotations[hostoptions.UtilityPageTypeAnnotation] = tt.pageType
+			}
+			err := ValidateUtilityPageType(annotations, kdexv1alpha1.ErrorUtilityPageType)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
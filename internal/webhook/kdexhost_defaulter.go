@@ -2,8 +2,11 @@ package webhook
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"sync"
 
+	"github.com/kdex-tech/nexus-manager/internal/hostoptions"
 	"k8s.io/apimachinery/pkg/runtime"
 	kdexv1alpha1 "kdex.dev/crds/api/v1alpha1"
 	"kdex.dev/crds/configuration"
@@ -14,20 +17,26 @@ import (
 
 type KDexHostDefaulter[T runtime.Object] struct {
 	Configuration configuration.NexusConfiguration
+
+	mu sync.RWMutex
 }
 
 var _ admission.Defaulter[*kdexv1alpha1.KDexHost] = &KDexHostDefaulter[*kdexv1alpha1.KDexHost]{}
 
 func (a *KDexHostDefaulter[T]) Default(ctx context.Context, obj T) error {
-	var spec *kdexv1alpha1.KDexHostSpec
+	var host *kdexv1alpha1.KDexHost
 
 	switch t := any(obj).(type) {
 	case *kdexv1alpha1.KDexHost:
-		spec = &t.Spec
+		host = t
 	default:
 		return fmt.Errorf("unsupported type: %T", t)
 	}
 
+	defaultUtilityPagesAnnotation(host)
+
+	spec := &host.Spec
+
 	if spec.DefaultLang == "" {
 		spec.DefaultLang = "en"
 	}
@@ -36,6 +45,10 @@ func (a *KDexHostDefaulter[T]) Default(ctx context.Context, obj T) error {
 		spec.ThemeRef.Kind = "KDexTheme"
 	}
 
+	if spec.FaaSAdaptorRef != nil && spec.FaaSAdaptorRef.Kind == "" {
+		spec.FaaSAdaptorRef.Kind = KDexFaaSAdaptor
+	}
+
 	if spec.ModulePolicy == "" {
 		spec.ModulePolicy = kdexv1alpha1.StrictModulePolicy
 	}
@@ -48,52 +61,93 @@ func (a *KDexHostDefaulter[T]) Default(ctx context.Context, obj T) error {
 		spec.ScriptLibraryRef.Kind = KDexScriptLibrary
 	}
 
+	a.mu.RLock()
+	imageRegistry := a.Configuration.DefaultImageRegistry
+	npmRegistry := a.Configuration.DefaultNpmRegistry
+	a.mu.RUnlock()
+
 	if spec.Registries.ImageRegistry.Host == "" {
-		spec.Registries.ImageRegistry.Host = a.Configuration.DefaultImageRegistry.Host
-		spec.Registries.ImageRegistry.Insecure = a.Configuration.DefaultImageRegistry.InSecure
+		spec.Registries.ImageRegistry.Host = imageRegistry.Host
+		spec.Registries.ImageRegistry.Insecure = imageRegistry.InSecure
 	}
 
 	if spec.Registries.NpmRegistry.Host == "" {
-		spec.Registries.NpmRegistry.Host = a.Configuration.DefaultNpmRegistry.Host
-		spec.Registries.NpmRegistry.Insecure = a.Configuration.DefaultNpmRegistry.InSecure
+		spec.Registries.NpmRegistry.Host = npmRegistry.Host
+		spec.Registries.NpmRegistry.Insecure = npmRegistry.InSecure
 	}
 
 	if spec.UtilityPages == nil {
 		spec.UtilityPages = &kdexv1alpha1.UtilityPages{}
 	}
-	if spec.UtilityPages.AnnouncementRef == nil {
-		spec.UtilityPages.AnnouncementRef = &kdexv1alpha1.KDexObjectReference{}
-	}
-	if spec.UtilityPages.AnnouncementRef.Kind == "" {
-		spec.UtilityPages.AnnouncementRef.Kind = KDexClusterUtilityPage
+	spec.UtilityPages.AnnouncementRef = defaultUtilityPageRef(spec.UtilityPages.AnnouncementRef, kdexv1alpha1.AnnouncementUtilityPageType)
+	spec.UtilityPages.ErrorRef = defaultUtilityPageRef(spec.UtilityPages.ErrorRef, kdexv1alpha1.ErrorUtilityPageType)
+	spec.UtilityPages.LoginRef = defaultUtilityPageRef(spec.UtilityPages.LoginRef, kdexv1alpha1.LoginUtilityPageType)
+
+	spec.IngressPath = "/-/host"
+
+	BackendDefaults(&spec.Backend)
+
+	return nil
+}
+
+// DefaultUtilityPageRefs adds the bundled page of every defaulted utility page type which pages neither references nor
+// leaves out, and completes the references of pages.
+func DefaultUtilityPageRefs(pages map[kdexv1alpha1.KDexUtilityPageType]*kdexv1alpha1.KDexObjectReference) {
+	for _, pageType := range hostoptions.DefaultedUtilityPageTypes {
+		if _, ok := pages[pageType]; !ok {
+			pages[pageType] = defaultUtilityPageRef(nil, pageType)
+		}
 	}
-	if spec.UtilityPages.AnnouncementRef.Name == "" {
-		spec.UtilityPages.AnnouncementRef.Name = "kdex-default-utility-page-announcement"
+
+	for pageType, ref := range pages {
+		if ref != nil {
+			pages[pageType] = defaultUtilityPageRef(ref, pageType)
+		}
 	}
+}
 
-	if spec.UtilityPages.ErrorRef == nil {
-		spec.UtilityPages.ErrorRef = &kdexv1alpha1.KDexObjectReference{}
+// defaultUtilityPageRef completes ref, or a new reference when ref is nil, with the bundled page of pageType.
+func defaultUtilityPageRef(
+	ref *kdexv1alpha1.KDexObjectReference,
+	pageType kdexv1alpha1.KDexUtilityPageType,
+) *kdexv1alpha1.KDexObjectReference {
+	if ref == nil {
+		ref = &kdexv1alpha1.KDexObjectReference{}
 	}
-	if spec.UtilityPages.ErrorRef.Kind == "" {
-		spec.UtilityPages.ErrorRef.Kind = KDexClusterUtilityPage
+	if ref.Kind == "" {
+		ref.Kind = KDexClusterUtilityPage
 	}
-	if spec.UtilityPages.ErrorRef.Name == "" {
-		spec.UtilityPages.ErrorRef.Name = "kdex-default-utility-page-error"
+	if ref.Name == "" {
+		ref.Name = DefaultUtilityPages[pageType]
 	}
+	return ref
+}
 
-	if spec.UtilityPages.LoginRef == nil {
-		spec.UtilityPages.LoginRef = &kdexv1alpha1.KDexObjectReference{}
-	}
-	if spec.UtilityPages.LoginRef.Kind == "" {
-		spec.UtilityPages.LoginRef.Kind = KDexClusterUtilityPage
+// defaultUtilityPagesAnnotation rewrites the utility pages annotation of host with the defaults applied. An invalid
+// annotation is left as is for the validator to reject.
+func defaultUtilityPagesAnnotation(host *kdexv1alpha1.KDexHost) {
+	pages, err := hostoptions.GetUtilityPages(host.Annotations)
+	if err != nil {
+		return
 	}
-	if spec.UtilityPages.LoginRef.Name == "" {
-		spec.UtilityPages.LoginRef.Name = "kdex-default-utility-page-login"
+
+	DefaultUtilityPageRefs(pages)
+
+	value, err := json.Marshal(pages)
+	if err != nil {
+		return
 	}
 
-	spec.IngressPath = "/-/host"
+	if host.Annotations == nil {
+		host.Annotations = map[string]string{}
+	}
+	host.Annotations[hostoptions.UtilityPagesAnnotation] = string(value)
+}
 
-	BackendDefaults(&spec.Backend)
+// SetConfiguration replaces the configuration used for defaulting subsequent requests.
+func (a *KDexHostDefaulter[T]) SetConfiguration(config configuration.NexusConfiguration) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
 
-	return nil
+	a.Configuration = config
 }
@@ -3,33 +3,51 @@ package webhook
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
 
+	"github.com/kdex-tech/nexus-manager/internal/domains"
+	"github.com/kdex-tech/nexus-manager/internal/extensions"
+	"github.com/kdex-tech/nexus-manager/internal/hostoptions"
 	"github.com/kdex-tech/nexus-manager/internal/validation"
 	"k8s.io/apimachinery/pkg/runtime"
 	kdexv1alpha1 "kdex.dev/crds/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
 // +kubebuilder:webhook:path=/validate-kdex-dev-v1alpha1-kdexhost,mutating=false,failurePolicy=Ignore,sideEffects=None,groups=kdex.dev,resources=kdexhosts,verbs=create;update,versions=v1alpha1,name=validate.kdexhost.kdex.dev,admissionReviewVersions=v1
 
 type KDexHostValidator[T runtime.Object] struct {
+	// Client looks up the domains claimed by other hosts and the members of pools. It must have the
+	// domains.IndexField index. Domain conflicts and the RBAC of pools are not checked when it is nil.
+	Client client.Reader
+
+	// Extensions returns the current extension settings, which define the RBAC profiles hosts may choose. The built-in
+	// profiles alone are known when it is nil.
+	Extensions func() extensions.Configuration
 }
 
 var _ admission.Validator[*kdexv1alpha1.KDexHost] = &KDexHostValidator[*kdexv1alpha1.KDexHost]{}
 
 func (v *KDexHostValidator[T]) ValidateCreate(ctx context.Context, obj T) (admission.Warnings, error) {
-	return v.validate(ctx, obj)
+	return v.validate(ctx, obj, nil)
 }
 
 func (v *KDexHostValidator[T]) ValidateUpdate(ctx context.Context, oldObj, newObj T) (admission.Warnings, error) {
-	return v.validate(ctx, newObj)
+	var previousDomains []string
+	if oldHost, ok := any(oldObj).(*kdexv1alpha1.KDexHost); ok {
+		previousDomains = oldHost.Spec.Routing.Domains
+	}
+
+	return v.validate(ctx, newObj, previousDomains)
 }
 
 func (v *KDexHostValidator[T]) ValidateDelete(ctx context.Context, obj T) (admission.Warnings, error) {
 	return nil, nil
 }
 
-func (v *KDexHostValidator[T]) validate(_ context.Context, obj T) (admission.Warnings, error) {
+func (v *KDexHostValidator[T]) validate(ctx context.Context, obj T, previousDomains []string) (admission.Warnings, error) {
 	var host *kdexv1alpha1.KDexHost
 
 	switch t := any(obj).(type) {
@@ -39,6 +57,11 @@ func (v *KDexHostValidator[T]) validate(_ context.Context, obj T) (admission.War
 		return nil, fmt.Errorf("unsupported type: %T", t)
 	}
 
+	ext := extensions.Configuration{}
+	if v.Extensions != nil {
+		ext = v.Extensions()
+	}
+
 	spec := &host.Spec
 
 	if spec.BrandName == "" {
@@ -54,5 +77,93 @@ func (v *KDexHostValidator[T]) validate(_ context.Context, obj T) (admission.War
 		return nil, err
 	}
 
+	if err := validation.ValidateDeploymentPatch(host.Name, host.Annotations); err != nil {
+		return nil, err
+	}
+
+	if err := validation.ValidateAutoscaling(spec.Replicas, host.Annotations); err != nil {
+		return nil, err
+	}
+
+	if err := validation.ValidateHostPodDisruptionBudget(host.Annotations); err != nil {
+		return nil, err
+	}
+
+	if err := validation.ValidateMaintenance(host.Annotations, ext.HostDefault.MaintenanceMode); err != nil {
+		return nil, err
+	}
+
+	if err := validation.ValidateUtilityPages(host.Annotations); err != nil {
+		return nil, err
+	}
+
+	if err := validation.ValidatePool(spec, host.Annotations); err != nil {
+		return nil, err
+	}
+
+	if err := validation.ValidateHostRBACProfile(spec, host.Annotations, ext.HostDefault.RBAC); err != nil {
+		return nil, err
+	}
+
+	if err := v.validatePoolRBAC(ctx, host, ext.HostDefault.RBAC); err != nil {
+		return nil, err
+	}
+
+	if err := v.validateDomains(ctx, host, previousDomains); err != nil {
+		return nil, err
+	}
+
 	return nil, nil
 }
+
+// validatePoolRBAC rejects a pooled host whose RBAC profile or scope differs from that of the other members of its pool.
+func (v *KDexHostValidator[T]) validatePoolRBAC(
+	ctx context.Context,
+	host *kdexv1alpha1.KDexHost,
+	rbac extensions.RBAC,
+) error {
+	if v.Client == nil || hostoptions.GetPool(host.Annotations) == "" {
+		return nil
+	}
+
+	var hosts kdexv1alpha1.KDexHostList
+	if err := v.Client.List(ctx, &hosts, client.InNamespace(host.Namespace)); err != nil {
+		return fmt.Errorf("failed to list the members of the pool: %w", err)
+	}
+
+	return validation.ValidatePoolRBAC(host, hosts.Items, rbac)
+}
+
+// validateDomains rejects domains overlapping those of another host. Only domains added by an update are checked so
+// that a host which already lost a conflict can still be edited to resolve it.
+func (v *KDexHostValidator[T]) validateDomains(
+	ctx context.Context,
+	host *kdexv1alpha1.KDexHost,
+	previousDomains []string,
+) error {
+	if v.Client == nil {
+		return nil
+	}
+
+	added := []string{}
+	for _, domain := range host.Spec.Routing.Domains {
+		if !slices.Contains(previousDomains, domain) {
+			added = append(added, domain)
+		}
+	}
+
+	conflicts, err := domains.FindConflicts(ctx, v.Client, host, added)
+	if err != nil {
+		return fmt.Errorf("failed to check domain conflicts: %w", err)
+	}
+
+	if len(conflicts) > 0 {
+		messages := make([]string, 0, len(conflicts))
+		for _, conflict := range conflicts {
+			messages = append(messages, conflict.String())
+		}
+		return fmt.Errorf("spec.routing.domains: %s", strings.Join(messages, "; "))
+	}
+
+	return nil
+}
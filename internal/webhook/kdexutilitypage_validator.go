@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/kdex-tech/nexus-manager/internal/validation"
 	"k8s.io/apimachinery/pkg/runtime"
 	kdexv1alpha1 "kdex.dev/crds/api/v1alpha1"
 	"kdex.dev/crds/render"
@@ -32,16 +33,23 @@ func (v *KDexUtilityPageValidator[T]) ValidateDelete(ctx context.Context, obj T)
 
 func (v *KDexUtilityPageValidator[T]) validate(_ context.Context, obj T) (admission.Warnings, error) {
 	var spec *kdexv1alpha1.KDexUtilityPageSpec
+	var annotations map[string]string
 
 	switch t := any(obj).(type) {
 	case *kdexv1alpha1.KDexUtilityPage:
 		spec = &t.Spec
+		annotations = t.Annotations
 	case *kdexv1alpha1.KDexClusterUtilityPage:
 		spec = &t.Spec
+		annotations = t.Annotations
 	default:
 		return nil, fmt.Errorf("unsupported type: %T", t)
 	}
 
+	if err := validation.ValidateUtilityPageType(annotations, spec.Type); err != nil {
+		return nil, err
+	}
+
 	for idx, entry := range spec.ContentEntries {
 		if entry.RawHTML != "" {
 			if err := render.ValidateContent(entry.Slot, entry.RawHTML); err != nil {
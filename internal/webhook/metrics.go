@@ -0,0 +1,58 @@
+package webhook
+
+import (
+	"context"
+
+	"github.com/kdex-tech/nexus-manager/internal/metrics"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// EventReasonRejected is the reason of the Events recorded for the admission requests a validator rejects.
+const EventReasonRejected = "Rejected"
+
+// rejectionRecorder counts, and records as Events, the admission requests rejected by the validator it wraps.
+type rejectionRecorder[T runtime.Object] struct {
+	recorder  events.EventRecorder
+	validator admission.Validator[T]
+}
+
+// RecordRejections wraps validator so that the requests it rejects are counted in the webhook rejection metric and
+// recorded as Warning Events regarding the rejected object.
+func RecordRejections[T runtime.Object](recorder events.EventRecorder, validator admission.Validator[T]) admission.Validator[T] {
+	return &rejectionRecorder[T]{recorder: recorder, validator: validator}
+}
+
+func (v *rejectionRecorder[T]) ValidateCreate(ctx context.Context, obj T) (admission.Warnings, error) {
+	warnings, err := v.validator.ValidateCreate(ctx, obj)
+	if err != nil {
+		v.reject(obj, admissionv1.Create, err)
+	}
+	return warnings, err
+}
+
+func (v *rejectionRecorder[T]) ValidateUpdate(ctx context.Context, oldObj, newObj T) (admission.Warnings, error) {
+	warnings, err := v.validator.ValidateUpdate(ctx, oldObj, newObj)
+	if err != nil {
+		v.reject(newObj, admissionv1.Update, err)
+	}
+	return warnings, err
+}
+
+func (v *rejectionRecorder[T]) ValidateDelete(ctx context.Context, obj T) (admission.Warnings, error) {
+	warnings, err := v.validator.ValidateDelete(ctx, obj)
+	if err != nil {
+		v.reject(obj, admissionv1.Delete, err)
+	}
+	return warnings, err
+}
+
+func (v *rejectionRecorder[T]) reject(obj T, operation admissionv1.Operation, err error) {
+	metrics.ObserveWebhookRejection(obj, string(operation), err)
+
+	v.recorder.Eventf(obj, nil, corev1.EventTypeWarning, EventReasonRejected, string(operation),
+		"Rejected %s: %v", metrics.Kind(obj), err)
+}
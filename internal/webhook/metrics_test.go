@@ -0,0 +1,39 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/events"
+	kdexv1alpha1 "kdex.dev/crds/api/v1alpha1"
+)
+
+func TestRecordRejections(t *testing.T) {
+	recorder := events.NewFakeRecorder(10)
+	validator := RecordRejections(recorder, &KDexFunctionValidator[*kdexv1alpha1.KDexFunction]{})
+	ctx := context.Background()
+
+	t.Run("rejected", func(t *testing.T) {
+		function := &kdexv1alpha1.KDexFunction{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-function", Namespace: "default"},
+		}
+
+		_, err := validator.ValidateCreate(ctx, function)
+		assert.Error(t, err)
+
+		select {
+		case event := <-recorder.Events:
+			assert.Contains(t, event, "Warning Rejected Rejected KDexFunction: ")
+		default:
+			t.Fatal("expected an Event")
+		}
+	})
+
+	t.Run("allowed", func(t *testing.T) {
+		_, err := validator.ValidateDelete(ctx, &kdexv1alpha1.KDexFunction{})
+		assert.NoError(t, err)
+		assert.Empty(t, recorder.Events)
+	})
+}
@@ -1,8 +1,14 @@
 package webhook
 
+import (
+	"github.com/kdex-tech/nexus-manager/internal/hostoptions"
+	kdexv1alpha1 "kdex.dev/crds/api/v1alpha1"
+)
+
 const (
 	KDexApp                   = "KDexApp"
 	KDexClusterApp            = "KDexClusterApp"
+	KDexClusterFaaSAdaptor    = "KDexClusterFaaSAdaptor"
 	KDexClusterPageArchetype  = "KDexClusterPageArchetype"
 	KDexClusterPageFooter     = "KDexClusterPageFooter"
 	KDexClusterPageHeader     = "KDexClusterPageHeader"
@@ -10,6 +16,7 @@ const (
 	KDexClusterScriptLibrary  = "KDexClusterScriptLibrary"
 	KDexClusterTheme          = "KDexClusterTheme"
 	KDexClusterUtilityPage    = "KDexClusterUtilityPage"
+	KDexFaaSAdaptor           = "KDexFaaSAdaptor"
 	KDexPageArchetype         = "KDexPageArchetype"
 	KDexPageFooter            = "KDexPageFooter"
 	KDexPageHeader            = "KDexPageHeader"
@@ -20,5 +27,22 @@ const (
 
 	KDexDefaultUtilityPageAnnouncement = "kdex-default-utility-page-announcement"
 	KDexDefaultUtilityPageError        = "kdex-default-utility-page-error"
+	KDexDefaultUtilityPageForbidden    = "kdex-default-utility-page-forbidden"
 	KDexDefaultUtilityPageLogin        = "kdex-default-utility-page-login"
+	KDexDefaultUtilityPageLogout       = "kdex-default-utility-page-logout"
+	KDexDefaultUtilityPageMaintenance  = "kdex-default-utility-page-maintenance"
+	KDexDefaultUtilityPageNotFound     = "kdex-default-utility-page-not-found"
+	KDexDefaultUtilityPageOffline      = "kdex-default-utility-page-offline"
 )
+
+// DefaultUtilityPages names the bundled KDexClusterUtilityPage of each utility page type which has one.
+var DefaultUtilityPages = map[kdexv1alpha1.KDexUtilityPageType]string{
+	kdexv1alpha1.AnnouncementUtilityPageType: KDexDefaultUtilityPageAnnouncement,
+	kdexv1alpha1.ErrorUtilityPageType:        KDexDefaultUtilityPageError,
+	kdexv1alpha1.LoginUtilityPageType:        KDexDefaultUtilityPageLogin,
+	hostoptions.ForbiddenUtilityPageType:     KDexDefaultUtilityPageForbidden,
+	hostoptions.LogoutUtilityPageType:        KDexDefaultUtilityPageLogout,
+	hostoptions.MaintenanceUtilityPageType:   KDexDefaultUtilityPageMaintenance,
+	hostoptions.NotFoundUtilityPageType:      KDexDefaultUtilityPageNotFound,
+	hostoptions.OfflineUtilityPageType:       KDexDefaultUtilityPageOffline,
+}